@@ -0,0 +1,107 @@
+package httphead
+
+import (
+	"bytes"
+	"sort"
+)
+
+// MediaRange represents single item of Accept header value.
+type MediaRange struct {
+	// Type and Subtype are the parts of media range, such as "text" and
+	// "html". Any of them could be "*".
+	Type, Subtype []byte
+
+	// Parameters contains media range parameters which precede the weight
+	// parameter. Accept extensions which follow the weight are dropped.
+	Parameters Parameters
+
+	// Quality is the weight of media range in thousandths (see
+	// ParseQuality()). It is 1000 if weight is omitted.
+	Quality uint16
+}
+
+// Specificity returns the specificity of media range. That is, "*/*" has
+// specificity 0, "type/*" has specificity 1, "type/subtype" has specificity 2
+// and "type/subtype" with parameters has specificity 3.
+func (r MediaRange) Specificity() int {
+	switch {
+	case isWildcard(r.Type):
+		return 0
+	case isWildcard(r.Subtype):
+		return 1
+	case r.Parameters.Size() == 0:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// ParseAccept parses Accept header value and appends media ranges to given
+// slice in order of their appearance:
+//
+// Accept       = #( media-range [ weight ] )
+// media-range  = ( "*/*" / ( type "/" "*" ) / ( type "/" subtype ) ) parameters
+// weight       = OWS ";" OWS "q=" qvalue
+//
+// Note that appended media ranges consist of subslices of data. It returns
+// false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-12.5.1
+func ParseAccept(data []byte, ranges []MediaRange) ([]MediaRange, bool) {
+	var (
+		index  = -1
+		valid  = true
+		weight bool
+	)
+	s := ListScanner{Flags: ScanMediaTypes}
+	ok := s.ScanOptions(data, func(i int, name, attr, val []byte) Control {
+		if i != index {
+			index = i
+			weight = false
+			j := bytes.IndexByte(name, '/')
+			ranges = append(ranges, MediaRange{
+				Type:    name[:j],
+				Subtype: name[j+1:],
+				Quality: 1000,
+			})
+			if isWildcard(name[:j]) && !isWildcard(name[j+1:]) {
+				valid = false
+				return ControlBreak
+			}
+		}
+		if attr == nil || weight {
+			return ControlContinue
+		}
+		r := &ranges[len(ranges)-1]
+		if isQualityName(attr) {
+			var err error
+			if r.Quality, err = ParseQuality(val); err != nil {
+				valid = false
+				return ControlBreak
+			}
+			weight = true
+			return ControlContinue
+		}
+		r.Parameters.Set(attr, val)
+		return ControlContinue
+	})
+	return ranges, ok && valid
+}
+
+// SortMediaRanges sorts media ranges by preference. That is, media ranges
+// are sorted by quality and then by specificity in descending order. Media
+// ranges of the same specificity are sorted by number of parameters in
+// descending order, such that "text/html;level=1;charset=utf-8" precedes
+// "text/html;level=1". Order of equally preferred media ranges is preserved.
+// See https://tools.ietf.org/html/rfc9110#section-12.5.1
+func SortMediaRanges(ranges []MediaRange) {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		a, b := ranges[i], ranges[j]
+		if a.Quality != b.Quality {
+			return a.Quality > b.Quality
+		}
+		if sa, sb := a.Specificity(), b.Specificity(); sa != sb {
+			return sa > sb
+		}
+		return a.Parameters.Size() > b.Parameters.Size()
+	})
+}
@@ -0,0 +1,85 @@
+package httphead
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseAccept(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{
+			in:  `text/html`,
+			exp: `[text/html[]:1000]`,
+			ok:  true,
+		},
+		{
+			in:  `text/*;q=0.3, text/html;q=0.7, text/html;level=1, text/html;level=2;q=0.4, */*;q=0.5`,
+			exp: `[text/*[]:300 text/html[]:700 text/html[level:1]:1000 text/html[level:2]:400 */*[]:500]`,
+			ok:  true,
+		},
+		{
+			in:  `application/json;q=0.9;ext=1, image/*`,
+			exp: `[application/json[]:900 image/*[]:1000]`,
+			ok:  true,
+		},
+		{
+			in: `text`,
+			ok: false,
+		},
+		{
+			in: `text/`,
+			ok: false,
+		},
+		{
+			in: `*/html`,
+			ok: false,
+		},
+		{
+			in: `text/html;q=1.5`,
+			ok: false,
+		},
+	} {
+		ranges, ok := ParseAccept([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseAccept(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if act := dumpMediaRanges(ranges); act != test.exp {
+			t.Errorf("ParseAccept(%q) = %s; want %s", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestSortMediaRanges(t *testing.T) {
+	ranges, ok := ParseAccept([]byte(`*/*;q=0.5, text/*, text/html;level=1, text/html, image/png;q=0.5`), nil)
+	if !ok {
+		t.Fatalf("ParseAccept() wellformed sign is false; want true")
+	}
+	SortMediaRanges(ranges)
+	exp := `[text/html[level:1]:1000 text/html[]:1000 text/*[]:1000 image/png[]:500 */*[]:500]`
+	if act := dumpMediaRanges(ranges); act != exp {
+		t.Errorf("SortMediaRanges() = %s; want %s", act, exp)
+	}
+
+	ranges, _ = ParseAccept([]byte(`text/*;a=1, text/html;a=1, */*, text/html;a=1;b=2, text/*`), nil)
+	SortMediaRanges(ranges)
+	exp = `[text/html[a:1 b:2]:1000 text/html[a:1]:1000 text/*[a:1]:1000 text/*[]:1000 */*[]:1000]`
+	if act := dumpMediaRanges(ranges); act != exp {
+		t.Errorf("SortMediaRanges() = %s; want %s", act, exp)
+	}
+}
+
+func dumpMediaRanges(ranges []MediaRange) string {
+	var ret []string
+	for _, r := range ranges {
+		ret = append(ret, fmt.Sprintf("%s/%s%s:%d", r.Type, r.Subtype, r.Parameters.String(), r.Quality))
+	}
+	return fmt.Sprint(ret)
+}
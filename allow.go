@@ -0,0 +1,126 @@
+package httphead
+
+import (
+	"io"
+	"sort"
+)
+
+// KnownMethods contains request methods defined by RFC9110 and RFC5789. It
+// could be passed to ParseAllow() to check methods against it.
+var KnownMethods = []string{
+	"GET",
+	"HEAD",
+	"POST",
+	"PUT",
+	"DELETE",
+	"CONNECT",
+	"OPTIONS",
+	"TRACE",
+	"PATCH",
+}
+
+// ParseAllow parses Allow header value and appends methods to given slice in
+// order of their appearance:
+//
+// Allow = #method
+//
+// Data is parsed by ParseMethods(). If known is not nil, each method must be
+// present in known. Note that methods are case-sensitive. Empty value is
+// valid and means that no methods are allowed. Appended methods are
+// subslices of data.
+//
+// It returns false if data is malformed or contains unknown method. In the
+// latter case methods preceding the unknown one are appended.
+// See https://tools.ietf.org/html/rfc9110#section-10.2.1
+func ParseAllow(data []byte, methods [][]byte, known []string) ([][]byte, bool) {
+	n := len(methods)
+	methods, ok := ParseMethods(data, methods)
+	if !ok || known == nil {
+		return methods, ok
+	}
+	for i, method := range methods[n:] {
+		if !containsString(known, method) {
+			return methods[:n+i], false
+		}
+	}
+	return methods, true
+}
+
+// ParseMethods parses comma separated list of request methods, such as
+// values of Allow, Access-Control-Allow-Methods or
+// Access-Control-Request-Method headers, and appends them to given slice in
+// order of their appearance:
+//
+// #method
+//
+// Methods must be separated by commas, that is, "GET POST" is malformed.
+// Empty value is valid. Appended methods are subslices of data.
+//
+// It returns false if data is malformed. In that case no methods are
+// appended.
+func ParseMethods(data []byte, methods [][]byte) ([][]byte, bool) {
+	if len(trim(data)) == 0 {
+		return methods, true
+	}
+	n := len(methods)
+	s := ListScanner{Flags: ScanRequireComma}
+	ok := s.ScanTokens(data, func(method []byte) bool {
+		methods = append(methods, method)
+		return true
+	})
+	if !ok {
+		return methods[:n], false
+	}
+	return methods, true
+}
+
+// ContainsMethod reports whether comma separated list of methods data
+// contains given method. Note that methods are case-sensitive, that is,
+// "get" does not match "GET". It returns false if data is malformed before
+// the method is found.
+//
+// It does not allocate, thus it could be used to check
+// Access-Control-Request-Method against Access-Control-Allow-Methods on
+// every preflight request.
+func ContainsMethod(data []byte, method string) bool {
+	return containsToken(data, func(p []byte) bool {
+		return string(p) == method
+	})
+}
+
+// WriteAllow writes methods list to the dest. Methods are sorted and
+// duplicates are removed, that is, output does not depend on methods order.
+//
+// It returns ErrMalformed without writing anything if some of methods is not
+// a valid token.
+func WriteAllow(dest io.Writer, methods []string) (n int, err error) {
+	for _, m := range methods {
+		if !isToken([]byte(m)) {
+			return 0, ErrMalformed
+		}
+	}
+	sorted := make([]string, len(methods))
+	copy(sorted, methods)
+	sort.Strings(sorted)
+
+	w := writer{w: dest}
+	for i, m := range sorted {
+		if i > 0 && m == sorted[i-1] {
+			continue
+		}
+		if i > 0 {
+			w.write(comma)
+		}
+		w.write([]byte(m))
+	}
+	return w.result()
+}
+
+func containsString(list []string, p []byte) bool {
+	for _, s := range list {
+		if s == string(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package httphead
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseAllow(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		known []string
+		exp   []string
+		ok    bool
+	}{
+		{"GET, HEAD, PUT", nil, []string{"GET", "HEAD", "PUT"}, true},
+		{"", nil, nil, true},
+		{" ", KnownMethods, nil, true},
+		{"GET, PROPFIND", nil, []string{"GET", "PROPFIND"}, true},
+		{"GET, PROPFIND", KnownMethods, []string{"GET"}, false},
+		{"GET, get", KnownMethods, []string{"GET"}, false},
+		{"GET, HE@D", nil, nil, false},
+		{"GET POST", nil, nil, false},
+		{"GET POST", KnownMethods, nil, false},
+	} {
+		act, ok := ParseAllow([]byte(test.in), nil, test.known)
+		if ok != test.ok || !reflect.DeepEqual(stringsOf(act), test.exp) {
+			t.Errorf("ParseAllow(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestWriteAllow(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := WriteAllow(&buf, []string{"POST", "GET", "HEAD", "GET"}); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := buf.String(), "GET,HEAD,POST"; act != exp {
+		t.Errorf("WriteAllow() = %q; want %q", act, exp)
+	}
+	for _, m := range []string{"GET\r\nX-Injected: 1", "GET,POST", "GET POST", ""} {
+		buf.Reset()
+		if _, err := WriteAllow(&buf, []string{"HEAD", m}); err != ErrMalformed {
+			t.Errorf("WriteAllow(%q) error is %v; want %v", m, err, ErrMalformed)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("WriteAllow(%q) wrote %q; want nothing", m, buf.String())
+		}
+	}
+}
+
+func TestParseMethods(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{"GET, HEAD, PUT", []string{"GET", "HEAD", "PUT"}, true},
+		{"PROPFIND", []string{"PROPFIND"}, true},
+		{"", nil, true},
+		{",GET,,", []string{"GET"}, true},
+		{"GET POST", nil, false},
+		{"GET, HE@D", nil, false},
+		{`GET, "POST"`, nil, false},
+	} {
+		act, ok := ParseMethods([]byte(test.in), nil)
+		if ok != test.ok || !reflect.DeepEqual(stringsOf(act), test.exp) {
+			t.Errorf("ParseMethods(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestContainsMethod(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		method string
+		exp    bool
+	}{
+		{"GET, HEAD, PUT", "PUT", true},
+		{"GET, HEAD, PUT", "put", false},
+		{"GET, HEAD", "PUT", false},
+		{"", "GET", false},
+		{"GET, @, PUT", "PUT", false},
+	} {
+		if act := ContainsMethod([]byte(test.in), test.method); act != test.exp {
+			t.Errorf("ContainsMethod(%q, %q) = %v; want %v", test.in, test.method, act, test.exp)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package httphead
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// AltSvc represents single alternative service of Alt-Svc header value.
+// See https://tools.ietf.org/html/rfc7838#section-3
+type AltSvc struct {
+	// ProtocolID is the ALPN protocol name, such as "h3". It is written
+	// percent-encoded if it contains characters not allowed in token.
+	ProtocolID []byte
+
+	// Authority is the alternative authority in the "[ uri-host ] : port"
+	// form, such as ":443" or "alt.example.com:8443".
+	Authority []byte
+
+	// MaxAge is the freshness lifetime of the alternative in seconds. It is
+	// written as "ma" parameter if it is positive.
+	MaxAge int64
+
+	// Persist reports whether alternative should not be cleared on network
+	// configuration changes. It is written as "persist=1" parameter.
+	Persist bool
+
+	// Parameters contains additional parameters of the alternative.
+	Parameters Parameters
+}
+
+// WriteAltSvc writes alternative services list to the dest:
+//
+// Alt-Svc       = clear / 1#alt-value
+// clear         = %s"clear"
+// alt-value     = alternative *( OWS ";" OWS parameter )
+// alternative   = protocol-id "=" alt-authority
+// protocol-id   = token
+// alt-authority = quoted-string
+//
+// If alts is empty, it writes "clear", which invalidates all alternatives.
+// See https://tools.ietf.org/html/rfc7838#section-3
+func WriteAltSvc(dest io.Writer, alts []AltSvc) (n int, err error) {
+	w := writer{w: dest}
+	if len(alts) == 0 {
+		w.write(altSvcClear)
+		return w.result()
+	}
+	for i, alt := range alts {
+		if i > 0 {
+			w.write(comma)
+		}
+		writeProtocolID(&w, alt.ProtocolID)
+		w.write(equality)
+		writeQuoted(&w, alt.Authority)
+		if alt.MaxAge > 0 {
+			w.write(semicolon)
+			w.write(altSvcMaxAge)
+			w.write(equality)
+			w.write(strconv.AppendInt(nil, alt.MaxAge, 10))
+		}
+		if alt.Persist {
+			w.write(semicolon)
+			w.write(altSvcPersist)
+		}
+		for _, p := range alt.Parameters.data() {
+			w.write(semicolon)
+			writeTokenSanitized(&w, p.key)
+			w.write(equality)
+			writeTokenSanitized(&w, p.value)
+		}
+	}
+	return w.result()
+}
+
+// AppendAltSvc appends alternative services list written in the same form as
+// WriteAltSvc() does to dst and returns the extended slice.
+func AppendAltSvc(dst []byte, alts []AltSvc) []byte {
+	buf := bytes.NewBuffer(dst)
+	_, _ = WriteAltSvc(buf, alts)
+	return buf.Bytes()
+}
+
+var (
+	altSvcClear   = []byte("clear")
+	altSvcMaxAge  = []byte("ma")
+	altSvcPersist = []byte("persist=1")
+)
+
+// writeProtocolID writes ALPN protocol name percent-encoding octets which are
+// not allowed in token, as well as "%" itself.
+// See https://tools.ietf.org/html/rfc7838#section-3.1
+func writeProtocolID(bw *writer, id []byte) {
+	const hex = "0123456789ABCDEF"
+	var pos int
+	for i, c := range id {
+		if OctetTypes[c].IsToken() && c != '%' {
+			continue
+		}
+		bw.write(id[pos:i])
+		bw.write([]byte{'%', hex[c>>4], hex[c&0xf]})
+		pos = i + 1
+	}
+	bw.write(id[pos:])
+}
@@ -0,0 +1,56 @@
+package httphead
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteAltSvc(t *testing.T) {
+	for _, test := range []struct {
+		alts []AltSvc
+		exp  string
+	}{
+		{
+			alts: nil,
+			exp:  `clear`,
+		},
+		{
+			alts: []AltSvc{{
+				ProtocolID: []byte("h3"),
+				Authority:  []byte(":443"),
+				MaxAge:     86400,
+			}},
+			exp: `h3=":443";ma=86400`,
+		},
+		{
+			alts: []AltSvc{
+				{
+					ProtocolID: []byte("h2"),
+					Authority:  []byte("alt.example.com:8443"),
+					Persist:    true,
+				},
+				{
+					ProtocolID: []byte("w=x:y#z"),
+					Authority:  []byte(`"bad":1`),
+				},
+				{
+					ProtocolID: []byte("h3-%"),
+					Authority:  []byte(":443"),
+					Parameters: authParams("x", "a b"),
+				},
+			},
+			exp: `h2="alt.example.com:8443";persist=1,w%3Dx%3Ay#z="\"bad\":1",h3-%25=":443";x="a b"`,
+		},
+	} {
+		var buf bytes.Buffer
+		if _, err := WriteAltSvc(&buf, test.alts); err != nil {
+			t.Fatal(err)
+		}
+		if act := buf.String(); act != test.exp {
+			t.Errorf("WriteAltSvc() = %s; want %s", act, test.exp)
+		}
+		if act := string(AppendAltSvc([]byte("x"), test.alts)); act != "x"+test.exp {
+			t.Errorf("AppendAltSvc() = %s; want %s", act, "x"+test.exp)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package httphead
+
+import (
+	"bytes"
+	"io"
+)
+
+// Credentials represents parsed value of Authorization or Proxy-Authorization
+// header.
+// See https://tools.ietf.org/html/rfc9110#section-11.4
+type Credentials struct {
+	// Scheme is the authentication scheme, such as "Basic" or "Digest".
+	Scheme []byte
+
+	// Token68 is set if credentials are in token68 form, such as used by
+	// "Basic" and "Bearer" schemes.
+	Token68 []byte
+
+	// Params contains auth-params if credentials are in that form. Note that
+	// parameter names are case-insensitive, but kept as is.
+	Params Parameters
+}
+
+// ParseAuthorization parses Authorization or Proxy-Authorization header value:
+//
+// credentials = auth-scheme [ 1*SP ( token68 / #auth-param ) ]
+// auth-param  = token BWS "=" BWS ( token / quoted-string )
+//
+// Note that returned credentials consist of subslices of data, except
+// quoted-string values with escaped characters. It returns false if data is
+// malformed.
+// See https://tools.ietf.org/html/rfc9110#section-11.6.2
+func ParseAuthorization(data []byte) (c Credentials, ok bool) {
+	var n int
+	ok = scanAuth(data, func(scheme, token68 []byte, params *Parameters) bool {
+		if n++; n > 1 {
+			return false
+		}
+		c = Credentials{
+			Scheme:  scheme,
+			Token68: token68,
+			Params:  *params,
+		}
+		return true
+	})
+	if !ok || n != 1 {
+		return Credentials{}, false
+	}
+	return c, true
+}
+
+// Challenge represents single challenge of WWW-Authenticate or
+// Proxy-Authenticate header.
+// See https://tools.ietf.org/html/rfc9110#section-11.3
+type Challenge struct {
+	// Scheme is the authentication scheme, such as "Basic" or "Digest".
+	Scheme []byte
+
+	// Token68 is set if challenge is in token68 form.
+	Token68 []byte
+
+	// Params contains auth-params of the challenge, such as "realm". Note
+	// that parameter names are case-insensitive, but kept as is.
+	Params Parameters
+}
+
+// ScanChallenges scans WWW-Authenticate or Proxy-Authenticate header value:
+//
+// WWW-Authenticate = #challenge
+// challenge        = auth-scheme [ 1*SP ( token68 / #auth-param ) ]
+//
+// It calls it for every scanned challenge. If it returns false, scanning
+// stops. Unlike ScanOptions(), it distinguishes commas separating challenges
+// from commas separating auth-params of a single challenge.
+//
+// Note that challenges consist of subslices of data, except quoted-string
+// values with escaped characters. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-11.6.1
+func ScanChallenges(data []byte, it func(Challenge) bool) bool {
+	return scanAuth(data, func(scheme, token68 []byte, params *Parameters) bool {
+		return it(Challenge{
+			Scheme:  scheme,
+			Token68: token68,
+			Params:  *params,
+		})
+	})
+}
+
+// ParseChallenges is like ScanChallenges() but appends scanned challenges to
+// the given slice.
+func ParseChallenges(data []byte, challenges []Challenge) ([]Challenge, bool) {
+	ok := ScanChallenges(data, func(c Challenge) bool {
+		challenges = append(challenges, c)
+		return true
+	})
+	return challenges, ok
+}
+
+// WriteChallenge writes challenge to the dest in the form of WWW-Authenticate
+// header value. Parameter values are written as tokens if possible, except
+// "realm", which is always written as quoted-string as RFC9110 requires.
+//
+// It returns ErrMalformed without writing anything if scheme is not a token
+// or token68 is not valid, since they could not be quoted.
+func WriteChallenge(dest io.Writer, c Challenge) (n int, err error) {
+	return writeAuth(dest, c.Scheme, c.Token68, &c.Params)
+}
+
+// WriteCredentials writes credentials to the dest in the form of
+// Authorization header value. It follows the same rules as WriteChallenge().
+func WriteCredentials(dest io.Writer, c Credentials) (n int, err error) {
+	return writeAuth(dest, c.Scheme, c.Token68, &c.Params)
+}
+
+func writeAuth(dest io.Writer, scheme, token68 []byte, params *Parameters) (n int, err error) {
+	if n, t := ScanToken(scheme); t != ItemToken || n != len(scheme) {
+		return 0, ErrMalformed
+	}
+	if token68 != nil && ScanToken68(token68) != len(token68) {
+		return 0, ErrMalformed
+	}
+	w := writer{w: dest}
+	w.write(scheme)
+	if token68 != nil {
+		w.write(space)
+		w.write(token68)
+		return w.result()
+	}
+	for i, p := range params.data() {
+		if i == 0 {
+			w.write(space)
+		} else {
+			w.write(comma)
+		}
+		writeTokenSanitized(&w, p.key)
+		w.write(equality)
+		// Note that empty value must be quoted, since auth-param value could
+		// not be empty token.
+		if len(p.value) == 0 || bytes.EqualFold(p.key, paramRealm) {
+			writeQuoted(&w, p.value)
+		} else {
+			writeTokenSanitized(&w, p.value)
+		}
+	}
+	return w.result()
+}
+
+var paramRealm = []byte("realm")
+
+// scanAuth scans comma separated list of challenges or credentials, which
+// have the same grammar. It calls it for every scanned element. If it returns
+// false, scanning stops.
+//
+// Since elements and their auth-params are separated by commas both,
+// element boundary is detected by looking ahead for token not followed by
+// "=".
+func scanAuth(data []byte, it func(scheme, token68 []byte, params *Parameters) bool) bool {
+	if exceedsLimit(data) {
+		return false
+	}
+	var (
+		scheme  []byte
+		token68 []byte
+		params  Parameters
+
+		// expectParam reports whether current element could have more
+		// auth-params.
+		expectParam bool
+	)
+	p := data
+	for {
+		// Skip OWS and empty list elements.
+		var comma bool
+		for len(p) > 0 && (p[0] == ',' || OctetTypes[p[0]].IsSpace()) {
+			comma = comma || p[0] == ','
+			p = p[1:]
+		}
+		if len(p) == 0 {
+			break
+		}
+		n, t := ScanToken(p)
+		if t != ItemToken {
+			return false
+		}
+		if expectParam {
+			q := p[n:]
+			q = q[SkipSpace(q):]
+			if len(q) > 0 && q[0] == '=' {
+				q = q[1:]
+				q = q[SkipSpace(q):]
+				value, m, ok := scanAuthParamValue(q)
+				if !ok {
+					return false
+				}
+				params.Set(p[:n], value)
+				p = q[m:]
+				p = p[SkipSpace(p):]
+				if len(p) > 0 && p[0] != ',' {
+					return false
+				}
+				continue
+			}
+		}
+		if scheme != nil && !comma {
+			// Elements must be separated by comma, such that "Basic foo bar"
+			// is not treated as three challenges.
+			return false
+		}
+		if scheme != nil && !it(scheme, token68, &params) {
+			return true
+		}
+
+		scheme, token68 = p[:n], nil
+		params = Parameters{}
+		expectParam = true
+
+		p = p[n:]
+		m := SkipSpace(p)
+		if m == 0 {
+			if len(p) > 0 && p[0] != ',' {
+				return false
+			}
+			continue
+		}
+		p = p[m:]
+		if k := ScanToken68(p); k > 0 {
+			q := p[k:]
+			q = q[SkipSpace(q):]
+			if len(q) == 0 || q[0] == ',' {
+				token68 = p[:k]
+				expectParam = false
+				p = q
+			}
+		}
+	}
+	if scheme != nil {
+		it(scheme, token68, &params)
+	}
+	return true
+}
+
+// scanAuthParamValue scans token or quoted-string at the beginning of p. It
+// returns unquoted value and number of bytes scanned.
+func scanAuthParamValue(p []byte) (value []byte, n int, ok bool) {
+	if len(p) > 0 && p[0] == '"' {
+		i := ScanUntil(p[1:], '"')
+		if i == -1 {
+			return nil, 0, false
+		}
+		return RemoveByte(p[1:i+1], '\\'), i + 2, true
+	}
+	n, t := ScanToken(p)
+	if t != ItemToken {
+		return nil, 0, false
+	}
+	return p[:n], n, true
+}
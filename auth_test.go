@@ -0,0 +1,255 @@
+package httphead
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseAuthorization(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		scheme  string
+		token68 string
+		params  string
+		ok      bool
+	}{
+		{
+			in:      `Basic QWxhZGRpbjpvcGVuIHNlc2FtZQ==`,
+			scheme:  "Basic",
+			token68: "QWxhZGRpbjpvcGVuIHNlc2FtZQ==",
+			ok:      true,
+		},
+		{
+			in:      `Bearer mF_9.B5f-4.1JqM `,
+			scheme:  "Bearer",
+			token68: "mF_9.B5f-4.1JqM",
+			ok:      true,
+		},
+		{
+			in:     `Digest username="Mufasa", realm="http-auth@example.org", nc=00000001 , qop=auth`,
+			scheme: "Digest",
+			params: `[username:Mufasa realm:http-auth@example.org nc:00000001 qop:auth]`,
+			ok:     true,
+		},
+		{
+			in:     `Custom a = "x \"y\" z",,b=c`,
+			scheme: "Custom",
+			params: `[a:x "y" z b:c]`,
+			ok:     true,
+		},
+		{
+			in:     `Negotiate`,
+			scheme: "Negotiate",
+			ok:     true,
+		},
+		{in: ``},
+		{in: ` `},
+		{in: `Basic abc def`},
+		{in: `Basic abc, Bearer def`},
+		{in: `Digest realm="x`},
+		{in: `Digest a=b, realm=`},
+		{in: `Digest realm=x y`},
+		{in: `=abc`},
+		{in: `B@sic abc`},
+	} {
+		c, ok := ParseAuthorization([]byte(test.in))
+		if ok != test.ok {
+			t.Errorf("ParseAuthorization(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if string(c.Scheme) != test.scheme || string(c.Token68) != test.token68 {
+			t.Errorf(
+				"ParseAuthorization(%q) = %q, %q; want %q, %q",
+				test.in, c.Scheme, c.Token68, test.scheme, test.token68,
+			)
+		}
+		if act := dumpParams(&c.Params); act != test.params {
+			t.Errorf("ParseAuthorization(%q) params = %s; want %s", test.in, act, test.params)
+		}
+	}
+}
+
+func dumpParams(p *Parameters) string {
+	if p.Size() == 0 {
+		return ""
+	}
+	return p.String()
+}
+
+func TestParseChallenges(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{
+			in:  `Basic realm="simple"`,
+			exp: []string{`Basic  [realm:simple]`},
+			ok:  true,
+		},
+		{
+			in: `Newauth realm="apps", type=1, title="Login to apps", Basic realm="simple"`,
+			exp: []string{
+				`Newauth  [realm:apps type:1 title:Login to apps]`,
+				`Basic  [realm:simple]`,
+			},
+			ok: true,
+		},
+		{
+			in: `Negotiate, NTLM,, Bearer realm=example , charset="UTF-8"`,
+			exp: []string{
+				`Negotiate  `,
+				`NTLM  `,
+				`Bearer  [realm:example charset:UTF-8]`,
+			},
+			ok: true,
+		},
+		{
+			in: `Negotiate YIIB/w==, Basic realm=x`,
+			exp: []string{
+				`Negotiate YIIB/w== `,
+				`Basic  [realm:x]`,
+			},
+			ok: true,
+		},
+		{
+			in: `Private token=, Basic`,
+			exp: []string{
+				`Private token= `,
+				`Basic  `,
+			},
+			ok: true,
+		},
+		{in: `Basic realm="x`},
+		{in: `Basic realm=x y`},
+		{in: `Basic YIIB/w== x`},
+		{in: `Basic foo bar`},
+		{in: `Basic foo bar, Bearer`},
+		{in: `, realm=x`},
+	} {
+		var act []string
+		cs, ok := ParseChallenges([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseChallenges(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for _, c := range cs {
+			act = append(act, string(c.Scheme)+" "+string(c.Token68)+" "+dumpParams(&c.Params))
+		}
+		if !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseChallenges(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestScanChallengesBreak(t *testing.T) {
+	var n int
+	ok := ScanChallenges([]byte(`A, B, C`), func(c Challenge) bool {
+		n++
+		return string(c.Scheme) != "B"
+	})
+	if !ok || n != 2 {
+		t.Errorf("ScanChallenges() = %v, %d calls; want true, 2 calls", ok, n)
+	}
+}
+
+func TestWriteChallenge(t *testing.T) {
+	for _, test := range []struct {
+		challenge Challenge
+		exp       string
+		err       error
+	}{
+		{
+			challenge: Challenge{
+				Scheme: []byte("Basic"),
+				Params: authParams("realm", "simple", "charset", "UTF-8"),
+			},
+			exp: `Basic realm="simple",charset=UTF-8`,
+		},
+		{
+			challenge: Challenge{
+				Scheme: []byte("Newauth"),
+				Params: authParams("Realm", `a "b" c`, "title", "Login to apps"),
+			},
+			exp: `Newauth Realm="a \"b\" c",title="Login to apps"`,
+		},
+		{
+			challenge: Challenge{
+				Scheme:  []byte("Negotiate"),
+				Token68: []byte("YIIB/w=="),
+			},
+			exp: `Negotiate YIIB/w==`,
+		},
+		{
+			challenge: Challenge{
+				Scheme: []byte("NTLM"),
+			},
+			exp: `NTLM`,
+		},
+		{
+			challenge: Challenge{
+				Scheme: []byte("Newauth"),
+				Params: authParams("realm", "", "type", ""),
+			},
+			exp: `Newauth realm="",type=""`,
+		},
+		{
+			challenge: Challenge{
+				Scheme: []byte("Basic realm"),
+			},
+			err: ErrMalformed,
+		},
+		{
+			challenge: Challenge{
+				Scheme:  []byte("Negotiate"),
+				Token68: []byte("a=b"),
+			},
+			err: ErrMalformed,
+		},
+	} {
+		var buf bytes.Buffer
+		_, err := WriteChallenge(&buf, test.challenge)
+		if err != test.err {
+			t.Errorf("WriteChallenge(%s) error is %v; want %v", test.exp, err, test.err)
+			continue
+		}
+		if act := buf.String(); act != test.exp {
+			t.Errorf("WriteChallenge() = %s; want %s", act, test.exp)
+		}
+		if err != nil {
+			continue
+		}
+		cs, ok := ParseChallenges(buf.Bytes(), nil)
+		if !ok || len(cs) != 1 || !cs[0].Params.Equal(test.challenge.Params) {
+			t.Errorf("ParseChallenges(%s) does not match written challenge", test.exp)
+		}
+	}
+}
+
+func TestWriteCredentials(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := WriteCredentials(&buf, Credentials{
+		Scheme: []byte("Digest"),
+		Params: authParams("username", "Mufasa", "realm", "http-auth@example.org", "nc", "00000001"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := buf.String(), `Digest username=Mufasa,realm="http-auth@example.org",nc=00000001`; act != exp {
+		t.Errorf("WriteCredentials() = %s; want %s", act, exp)
+	}
+}
+
+func authParams(kv ...string) (p Parameters) {
+	for i := 0; i < len(kv); i += 2 {
+		p.Set([]byte(kv[i]), []byte(kv[i+1]))
+	}
+	return p
+}
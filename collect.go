@@ -0,0 +1,47 @@
+package httphead
+
+import "net/url"
+
+// CollectMap returns callback which could be passed to ScanCookie() or
+// CookieScanner.Scan(). It appends copy of each scanned value to m under the
+// copy of its name.
+func CollectMap(m map[string][]string) func(name, value []byte) bool {
+	return func(name, value []byte) bool {
+		k := string(name)
+		m[k] = append(m[k], string(value))
+		return true
+	}
+}
+
+// CollectValues is the same as CollectMap() but collects pairs into v.
+func CollectValues(v url.Values) func(name, value []byte) bool {
+	return CollectMap(v)
+}
+
+// CollectOptions returns callback which could be passed to ScanOptions() or
+// ListScanner.ScanOptions(). It appends each scanned option to the slice
+// pointed by dst. Unlike ParseOptions(), appended options are copies and do
+// not refer to the scanned data.
+func CollectOptions(dst *[]Option) func(index int, option, attribute, value []byte) Control {
+	index := -1
+	return func(i int, option, attribute, value []byte) Control {
+		if i != index {
+			index = i
+			*dst = append(*dst, Option{Name: copyBytes(option)})
+		}
+		if attribute != nil {
+			opt := &(*dst)[len(*dst)-1]
+			opt.Parameters.Set(copyBytes(attribute), copyBytes(value))
+		}
+		return ControlContinue
+	}
+}
+
+func copyBytes(p []byte) []byte {
+	if p == nil {
+		return nil
+	}
+	r := make([]byte, len(p))
+	copy(r, p)
+	return r
+}
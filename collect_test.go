@@ -0,0 +1,43 @@
+package httphead
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCollectMap(t *testing.T) {
+	m := map[string][]string{}
+	ScanCookie([]byte(`foo=1; bar=2; foo=3`), CollectMap(m))
+	exp := map[string][]string{
+		"foo": {"1", "3"},
+		"bar": {"2"},
+	}
+	if !reflect.DeepEqual(m, exp) {
+		t.Errorf("CollectMap() collected %v; want %v", m, exp)
+	}
+}
+
+func TestCollectValues(t *testing.T) {
+	v := url.Values{}
+	ScanCookie([]byte(`foo=1; bar=2`), CollectValues(v))
+	if act := v.Encode(); act != "bar=2&foo=1" {
+		t.Errorf("CollectValues() collected %q; want %q", act, "bar=2&foo=1")
+	}
+}
+
+func TestCollectOptions(t *testing.T) {
+	var opts []Option
+	data := []byte(`foo;a=1;b,bar`)
+	if !ScanOptions(data, CollectOptions(&opts)) {
+		t.Fatalf("ScanOptions() wellformed sign is false; want true")
+	}
+	memset(data, 'x')
+	foo := Option{Name: []byte("foo")}
+	foo.Parameters.Set([]byte("a"), []byte("1"))
+	foo.Parameters.Set([]byte("b"), nil)
+	exp := []Option{foo, NewOption("bar", nil)}
+	if !optionsEqual(opts, exp) {
+		t.Errorf("CollectOptions() collected %v; want %v", opts, exp)
+	}
+}
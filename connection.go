@@ -0,0 +1,97 @@
+package httphead
+
+import "bytes"
+
+// ParseConnection parses Connection header value and appends connection
+// options to given slice in order of their appearance:
+//
+// Connection        = #connection-option
+// connection-option = token
+//
+// Note that appended options are subslices of data. It returns false if data
+// is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-7.6.1
+func ParseConnection(data []byte, options [][]byte) ([][]byte, bool) {
+	ok := ScanTokens(data, func(opt []byte) bool {
+		options = append(options, opt)
+		return true
+	})
+	return options, ok
+}
+
+// HasConnectionToken reports whether Connection header value data contains
+// given token, such as "close" or "upgrade". Tokens are compared
+// case-insensitively. It returns false if data is malformed.
+func HasConnectionToken(data []byte, token string) bool {
+	var has bool
+	ok := ScanTokens(data, func(opt []byte) bool {
+		has = bytes.EqualFold(opt, []byte(token))
+		return !has
+	})
+	return ok && has
+}
+
+// HopByHopHeaders contains names of header fields which are hop-by-hop
+// regardless of the Connection header value. Proxies must not forward them.
+// See https://tools.ietf.org/html/rfc9110#section-7.6.1
+var HopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Connection",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"TE",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// ScanHopByHop calls it for every header field name which is hop-by-hop and
+// must be removed by proxy before forwarding the message. That is, for names
+// listed in HopByHopHeaders and names listed in Connection header values.
+//
+// Header fields are iterated by headers function, which must call given
+// callback for every header field and stop if callback returns false. It
+// is called twice: first to collect Connection options and then to report
+// hop-by-hop names. Names are compared case-insensitively.
+//
+// It returns false if some of Connection header values is malformed. In that
+// case it is not called.
+func ScanHopByHop(headers func(func(name, value []byte) bool), it func(name []byte) bool) bool {
+	var (
+		options [][]byte
+		ok      = true
+	)
+	headers(func(name, value []byte) bool {
+		if bytes.EqualFold(name, headerConnection) {
+			options, ok = ParseConnection(value, options)
+		}
+		return ok
+	})
+	if !ok {
+		return false
+	}
+	headers(func(name, value []byte) bool {
+		if !isHopByHop(name, options) {
+			return true
+		}
+		return it(name)
+	})
+	return true
+}
+
+var headerConnection = []byte("Connection")
+
+func isHopByHop(name []byte, options [][]byte) bool {
+	for _, h := range HopByHopHeaders {
+		if bytes.EqualFold(name, []byte(h)) {
+			return true
+		}
+	}
+	for _, opt := range options {
+		if bytes.EqualFold(name, opt) {
+			return true
+		}
+	}
+	return false
+}
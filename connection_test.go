@@ -0,0 +1,74 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConnection(t *testing.T) {
+	opts, ok := ParseConnection([]byte(`keep-alive, Upgrade,, X-Foo`), nil)
+	if !ok {
+		t.Fatalf("ParseConnection() wellformed sign is false; want true")
+	}
+	if act, exp := stringsOf(opts), []string{"keep-alive", "Upgrade", "X-Foo"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("ParseConnection() = %q; want %q", act, exp)
+	}
+	if _, ok := ParseConnection([]byte(`keep-alive, "x"`), nil); ok {
+		t.Errorf("ParseConnection() wellformed sign is true; want false")
+	}
+}
+
+func TestHasConnectionToken(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		token string
+		exp   bool
+	}{
+		{`close`, "close", true},
+		{`Keep-Alive, CLOSE`, "close", true},
+		{`keep-alive, upgrade`, "close", false},
+		{`closed`, "close", false},
+		{`close, @`, "close", true},
+		{`@, close`, "close", false},
+	} {
+		if act := HasConnectionToken([]byte(test.in), test.token); act != test.exp {
+			t.Errorf("HasConnectionToken(%q, %q) = %v; want %v", test.in, test.token, act, test.exp)
+		}
+	}
+}
+
+func TestScanHopByHop(t *testing.T) {
+	fields := [][2]string{
+		{"Host", "example.com"},
+		{"Connection", "keep-alive, X-Foo"},
+		{"keep-alive", "timeout=5"},
+		{"X-Foo", "1"},
+		{"X-Bar", "2"},
+		{"Transfer-Encoding", "chunked"},
+		{"connection", "x-bar"},
+	}
+	headers := func(it func(name, value []byte) bool) {
+		for _, f := range fields {
+			if !it([]byte(f[0]), []byte(f[1])) {
+				return
+			}
+		}
+	}
+	var act []string
+	ok := ScanHopByHop(headers, func(name []byte) bool {
+		act = append(act, string(name))
+		return true
+	})
+	if !ok {
+		t.Fatalf("ScanHopByHop() wellformed sign is false; want true")
+	}
+	exp := []string{"Connection", "keep-alive", "X-Foo", "X-Bar", "Transfer-Encoding", "connection"}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("ScanHopByHop() = %q; want %q", act, exp)
+	}
+
+	fields = append(fields, [2]string{"Connection", "a b\x00"})
+	if ScanHopByHop(headers, func([]byte) bool { return true }) {
+		t.Errorf("ScanHopByHop() wellformed sign is true; want false")
+	}
+}
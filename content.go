@@ -0,0 +1,113 @@
+package httphead
+
+import "io"
+
+// ParseContentEncoding parses Content-Encoding header value and appends
+// content codings to given slice in order of their appearance:
+//
+// Content-Encoding = #content-coding
+// content-coding   = token
+//
+// Note that appended codings are subslices of data. It returns false if data
+// is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-8.4
+func ParseContentEncoding(data []byte, codings [][]byte) ([][]byte, bool) {
+	ok := ScanTokens(data, func(coding []byte) bool {
+		codings = append(codings, coding)
+		return true
+	})
+	return codings, ok
+}
+
+// KnownContentCodings contains content codings registered in the HTTP Content
+// Coding Registry which are widely supported for decompression. It could be
+// passed to EndsWithKnownCoding().
+// See https://www.iana.org/assignments/http-parameters
+var KnownContentCodings = []string{
+	"gzip",
+	"x-gzip",
+	"deflate",
+	"compress",
+	"x-compress",
+	"br",
+	"zstd",
+}
+
+// EndsWithKnownCoding reports whether the last content coding of codings, as
+// returned by ParseContentEncoding(), is present in known. Codings are
+// compared case-insensitively.
+//
+// Since codings are listed in order they were applied, the last one must be
+// decoded first. That is, decoder of stacked codings such as "gzip, br" could
+// check the last coding, remove it and repeat while codings are not empty.
+func EndsWithKnownCoding(codings [][]byte, known []string) bool {
+	if len(codings) == 0 {
+		return false
+	}
+	last := codings[len(codings)-1]
+	for _, k := range known {
+		if equalFoldString(last, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseContentLanguage parses Content-Language header value and appends
+// language tags to given slice in order of their appearance:
+//
+// Content-Language = #language-tag
+//
+// Each language tag is checked to be well-formed, see ValidLanguageTag(), and
+// converted to canonical case, see CanonicalLanguageTag(). That is, "EN-us"
+// is appended as "en-US". Note that tags which are already in canonical case
+// are appended as subslices of data, while others are copied. It returns false
+// if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-8.5
+func ParseContentLanguage(data []byte, tags [][]byte) ([][]byte, bool) {
+	var valid bool
+	ok := ScanTokens(data, func(tag []byte) bool {
+		if valid = ValidLanguageTag(tag); !valid {
+			return false
+		}
+		tags = append(tags, CanonicalLanguageTag(tag))
+		return true
+	})
+	return tags, ok && valid
+}
+
+// WriteContentEncoding writes content codings list to the dest. It returns
+// ErrMalformed without writing anything if some of codings is not a valid
+// token.
+func WriteContentEncoding(dest io.Writer, codings [][]byte) (n int, err error) {
+	return writeList(dest, codings, isToken)
+}
+
+// WriteContentLanguage writes language tags list to the dest. It returns
+// ErrMalformed without writing anything if some of tags is not a well-formed
+// language tag, see ValidLanguageTag().
+func WriteContentLanguage(dest io.Writer, tags [][]byte) (n int, err error) {
+	return writeList(dest, tags, ValidLanguageTag)
+}
+
+// writeList writes comma separated list of elements which are checked with
+// valid before writing.
+func writeList(dest io.Writer, list [][]byte, valid func([]byte) bool) (n int, err error) {
+	for _, v := range list {
+		if !valid(v) {
+			return 0, ErrMalformed
+		}
+	}
+	w := writer{w: dest}
+	for i, v := range list {
+		if i > 0 {
+			w.write(comma)
+		}
+		w.write(v)
+	}
+	return w.result()
+}
+
+func isAlpha(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
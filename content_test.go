@@ -0,0 +1,105 @@
+package httphead
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseContentEncoding(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{"gzip", []string{"gzip"}, true},
+		{"deflate, gzip", []string{"deflate", "gzip"}, true},
+		{"gzip;q=1", []string{"gzip"}, false},
+		{"", nil, false},
+	} {
+		act, ok := ParseContentEncoding([]byte(test.in), nil)
+		if ok != test.ok || !reflect.DeepEqual(stringsOf(act), test.exp) {
+			t.Errorf("ParseContentEncoding(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestEndsWithKnownCoding(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp bool
+	}{
+		{"gzip", true},
+		{"gzip, BR", true},
+		{"br, custom", false},
+		{"identity", false},
+	} {
+		codings, _ := ParseContentEncoding([]byte(test.in), nil)
+		if act := EndsWithKnownCoding(codings, KnownContentCodings); act != test.exp {
+			t.Errorf("EndsWithKnownCoding(%q) = %v; want %v", test.in, act, test.exp)
+		}
+	}
+	if EndsWithKnownCoding(nil, KnownContentCodings) {
+		t.Errorf("EndsWithKnownCoding(nil) = true; want false")
+	}
+}
+
+func TestParseContentLanguage(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{"da", []string{"da"}, true},
+		{"mi, en", []string{"mi", "en"}, true},
+		{"en-US, zh-Hant-TW, de-CH-1901", []string{"en-US", "zh-Hant-TW", "de-CH-1901"}, true},
+		{"x-private", []string{"x-private"}, true},
+		{"EN-us, ZH-hant-tw, X-Private", []string{"en-US", "zh-Hant-TW", "x-private"}, true},
+		{"en, 1en", []string{"en"}, false},
+		{"en--US", nil, false},
+		{"en-", nil, false},
+		{"en-abcdefghi", nil, false},
+		{"en_US", nil, false},
+	} {
+		act, ok := ParseContentLanguage([]byte(test.in), nil)
+		if ok != test.ok || !reflect.DeepEqual(stringsOf(act), test.exp) {
+			t.Errorf("ParseContentLanguage(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestWriteContentEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	codings := [][]byte{[]byte("deflate"), []byte("gzip")}
+	if _, err := WriteContentEncoding(&buf, codings); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := buf.String(), "deflate,gzip"; act != exp {
+		t.Errorf("WriteContentEncoding() = %q; want %q", act, exp)
+	}
+	buf.Reset()
+	if _, err := WriteContentLanguage(&buf, [][]byte{[]byte("en-US")}); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := buf.String(), "en-US"; act != exp {
+		t.Errorf("WriteContentLanguage() = %q; want %q", act, exp)
+	}
+	for _, v := range []string{"gzip\r\nX-Injected: 1", "gzip, br", "a b", ""} {
+		buf.Reset()
+		list := [][]byte{[]byte("deflate"), []byte(v)}
+		if _, err := WriteContentEncoding(&buf, list); err != ErrMalformed || buf.Len() != 0 {
+			t.Errorf("WriteContentEncoding(%q) = %q, %v; want nothing, %v", v, buf.String(), err, ErrMalformed)
+		}
+		if _, err := WriteContentLanguage(&buf, list); err != ErrMalformed || buf.Len() != 0 {
+			t.Errorf("WriteContentLanguage(%q) = %q, %v; want nothing, %v", v, buf.String(), err, ErrMalformed)
+		}
+	}
+}
+
+func stringsOf(list [][]byte) []string {
+	var ret []string
+	for _, v := range list {
+		ret = append(ret, string(v))
+	}
+	return ret
+}
@@ -22,6 +22,11 @@ type CookieScanner struct {
 	// only RFC2616 "tokens" are accepted.
 	DisableNameValidation bool
 
+	// NameMode defines how names which are not RFC2616 "tokens" are handled
+	// when name validation is enabled. By default such pairs are treated
+	// as invalid.
+	NameMode CookieNameMode
+
 	// DisableValueValidation disables value validation of a cookie. If false,
 	// only RFC6265 "cookie-octet" characters are accepted.
 	//
@@ -42,11 +47,25 @@ type CookieScanner struct {
 	// If false, it is intended to bring the same behavior as
 	// http.Request.Cookies().
 	Strict bool
+
+	// Space defines how whitespace after ";" separating cookie pairs is
+	// handled. RFC6265 requires exactly one SP there.
+	// If Space is SpaceAuto, it is required only if Strict is true.
+	Space SpaceMode
+
+	// ValidateUTF8 causes scanner to treat pairs with value which is not a
+	// valid UTF-8 text as invalid. It is useful only when
+	// DisableValueValidation is true, since otherwise only ASCII values are
+	// accepted.
+	ValidateUTF8 bool
 }
 
 // Scan maps data to name and value pairs. Usually data represents value of the
 // Cookie header.
 func (c CookieScanner) Scan(data []byte, it func(name, value []byte) bool) bool {
+	if exceedsLimit(data) {
+		return false
+	}
 	lexer := &Scanner{data: data}
 
 	const (
@@ -74,7 +93,7 @@ func (c CookieScanner) Scan(data []byte, it func(name, value []byte) bool) bool
 			advance := 1
 			if b == ' ' {
 				advance++
-			} else if c.Strict {
+			} else if c.space() == SpaceRequired {
 				return false
 			}
 
@@ -100,11 +119,14 @@ func (c CookieScanner) Scan(data []byte, it func(name, value []byte) bool) bool
 			if !c.Strict {
 				trimLeft(name)
 			}
-			if !c.DisableNameValidation && !ValidCookieName(name) {
-				if !c.BreakOnPairError {
-					goto nextPair
+			if !c.DisableNameValidation {
+				var ok bool
+				if name, ok = c.NameMode.name(name); !ok {
+					if !c.BreakOnPairError {
+						goto nextPair
+					}
+					return false
 				}
-				return false
 			}
 
 			if !c.Strict {
@@ -117,6 +139,12 @@ func (c CookieScanner) Scan(data []byte, it func(name, value []byte) bool) bool
 				}
 				return false
 			}
+			if c.ValidateUTF8 && IndexInvalidUTF8(value) != -1 {
+				if !c.BreakOnPairError {
+					goto nextPair
+				}
+				return false
+			}
 
 			if !it(name, value) {
 				return true
@@ -130,6 +158,64 @@ func (c CookieScanner) Scan(data []byte, it func(name, value []byte) bool) bool
 	return true
 }
 
+func (c CookieScanner) space() SpaceMode {
+	switch {
+	case c.Space != SpaceAuto:
+		return c.Space
+	case c.Strict:
+		return SpaceRequired
+	default:
+		return SpaceOptional
+	}
+}
+
+// CookieNameMode describes how CookieScanner handles cookie names which are
+// not RFC2616 "tokens", such as UTF-8 names seen in the wild.
+type CookieNameMode byte
+
+const (
+	// CookieNameReject treats pairs with non-token names as invalid.
+	CookieNameReject CookieNameMode = iota
+
+	// CookieNameVerbatim accepts non-token names as is, unless they contain
+	// control characters or whitespace.
+	CookieNameVerbatim
+
+	// CookieNamePercentDecode accepts only token names, but decodes
+	// percent-encoded bytes in them. Decoded name must be a valid UTF-8
+	// text without control characters.
+	// Note that name is copied if it contains percent-encoded bytes.
+	CookieNamePercentDecode
+)
+
+func (m CookieNameMode) name(name []byte) ([]byte, bool) {
+	switch m {
+	case CookieNameVerbatim:
+		for _, c := range name {
+			if c <= 0x20 || c == 0x7f {
+				return nil, false
+			}
+		}
+		return name, len(name) > 0
+
+	case CookieNamePercentDecode:
+		if !ValidCookieName(name) {
+			return nil, false
+		}
+		if bytes.IndexByte(name, '%') == -1 {
+			return name, true
+		}
+		name, ok := percentDecode(nil, name)
+		if !ok || indexControl(name) != -1 || IndexInvalidUTF8(name) != -1 {
+			return nil, false
+		}
+		return name, true
+
+	default:
+		return name, ValidCookieName(name)
+	}
+}
+
 // ValidCookieValue reports whether given value is a valid RFC6265
 // "cookie-octet" bytes.
 //
@@ -146,6 +146,30 @@ var cookieCases = []struct {
 			Strict: true,
 		},
 	},
+	{
+		label: "want space between required",
+		in:    []byte(`foo=bar;bar=baz`),
+		ok:    false,
+		exp: []cookieTuple{
+			{[]byte(`foo`), []byte(`bar`)},
+		},
+		c: CookieScanner{
+			Space: SpaceRequired,
+		},
+	},
+	{
+		label: "want space between strict optional",
+		in:    []byte(`foo=bar;bar=baz`),
+		ok:    true,
+		exp: []cookieTuple{
+			{[]byte(`foo`), []byte(`bar`)},
+			{[]byte(`bar`), []byte(`baz`)},
+		},
+		c: CookieScanner{
+			Strict: true,
+			Space:  SpaceOptional,
+		},
+	},
 	{
 		label: "value single dquote",
 		in:    []byte(`foo="bar`),
@@ -275,6 +299,56 @@ var cookieCases = []struct {
 			Strict: true,
 		},
 	},
+	{
+		label: "utf8 name",
+		in:    []byte("\xe2\x82\xac=1; foo@bar=2; b\x01r=3"),
+		ok:    true,
+		exp: []cookieTuple{
+			{[]byte("€"), []byte(`1`)},
+			{[]byte(`foo@bar`), []byte(`2`)},
+		},
+		c: CookieScanner{
+			NameMode: CookieNameVerbatim,
+		},
+	},
+	{
+		label: "percent encoded name",
+		in:    []byte("%E2%82%AC=1; %zz=2; \xe2\x82\xac=3; %00=4; foo=5"),
+		ok:    true,
+		exp: []cookieTuple{
+			{[]byte("€"), []byte(`1`)},
+			{[]byte(`foo`), []byte(`5`)},
+		},
+		c: CookieScanner{
+			NameMode: CookieNamePercentDecode,
+		},
+	},
+	{
+		label: "utf8 value",
+		in:    []byte("foo=\xe2\x82\xac; bar=\xe2\x82; baz=1"),
+		ok:    true,
+		exp: []cookieTuple{
+			{[]byte(`foo`), []byte("€")},
+			{[]byte(`baz`), []byte(`1`)},
+		},
+		c: CookieScanner{
+			DisableValueValidation: true,
+			ValidateUTF8:           true,
+		},
+	},
+	{
+		label: "utf8 value",
+		in:    []byte("foo=\xe2\x82\xac; bar=\xe2\x82; baz=1"),
+		ok:    false,
+		exp: []cookieTuple{
+			{[]byte(`foo`), []byte("€")},
+		},
+		c: CookieScanner{
+			DisableValueValidation: true,
+			ValidateUTF8:           true,
+			BreakOnPairError:       true,
+		},
+	},
 }
 
 func TestScanCookie(t *testing.T) {
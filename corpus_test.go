@@ -0,0 +1,222 @@
+package httphead
+
+import (
+	"testing"
+
+	"github.com/gobwas/httphead/internal/corpus"
+	"github.com/gobwas/httphead/sfv"
+)
+
+// Note that corpora are shared with the httpheadtest package, which exports
+// them for libraries wrapping httphead.
+
+func TestCorpusTokens(t *testing.T) {
+	for _, test := range corpus.Tokens {
+		t.Run(test.Label, func(t *testing.T) {
+			var act []string
+			ok := ScanTokens([]byte(test.In), func(v []byte) bool {
+				act = append(act, string(v))
+				return true
+			})
+			if ok != test.OK {
+				t.Errorf("ScanTokens(%q) wellformed sign is %v; want %v", test.In, ok, test.OK)
+			}
+			if len(act) != len(test.Exp) {
+				t.Fatalf("ScanTokens(%q) = %q; want %q", test.In, act, test.Exp)
+			}
+			for i := range act {
+				if act[i] != test.Exp[i] {
+					t.Errorf("ScanTokens(%q) = %q; want %q", test.In, act, test.Exp)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCorpusOptions(t *testing.T) {
+	for _, test := range corpus.Options {
+		t.Run(test.Label, func(t *testing.T) {
+			act, ok := ParseOptions([]byte(test.In), nil)
+			if ok != test.OK {
+				t.Errorf("ParseOptions(%q) wellformed sign is %v; want %v", test.In, ok, test.OK)
+			}
+			if !ok || !test.OK {
+				return
+			}
+			if len(act) != len(test.Exp) {
+				t.Fatalf("ParseOptions(%q) = %v; want %v", test.In, act, test.Exp)
+			}
+			for i, opt := range test.Exp {
+				if exp := NewOption(opt.Name, opt.Params); !act[i].Equal(exp) {
+					t.Errorf("ParseOptions(%q)[%d] = %v; want %v", test.In, i, act[i], exp)
+				}
+			}
+		})
+	}
+}
+
+func TestCorpusCookies(t *testing.T) {
+	for _, test := range corpus.Cookies {
+		t.Run(test.Label, func(t *testing.T) {
+			var act []corpus.Cookie
+			ok := ScanCookie([]byte(test.In), func(name, value []byte) bool {
+				act = append(act, corpus.Cookie{
+					Name:  string(name),
+					Value: string(value),
+				})
+				return true
+			})
+			if ok != test.OK {
+				t.Errorf("ScanCookie(%q) wellformed sign is %v; want %v", test.In, ok, test.OK)
+			}
+			if len(act) != len(test.Exp) {
+				t.Fatalf("ScanCookie(%q) = %q; want %q", test.In, act, test.Exp)
+			}
+			for i := range act {
+				if act[i] != test.Exp[i] {
+					t.Errorf("ScanCookie(%q) = %q; want %q", test.In, act, test.Exp)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCorpusQuotedStrings(t *testing.T) {
+	for _, test := range corpus.QuotedStrings {
+		t.Run(test.Label, func(t *testing.T) {
+			s := NewScanner([]byte(test.In))
+			ok := s.Next() && s.Type() == ItemString
+			if ok != test.OK {
+				t.Errorf("Next(%q) wellformed sign is %v; want %v", test.In, ok, test.OK)
+			}
+			if act := string(s.Bytes()); ok && act != test.Out {
+				t.Errorf("Bytes(%q) = %q; want %q", test.In, act, test.Out)
+			}
+		})
+	}
+}
+
+func TestCorpusAdversarial(t *testing.T) {
+	for i, in := range corpus.Adversarial {
+		func() {
+			defer func() {
+				if err := recover(); err != nil {
+					t.Errorf("#%d: parsing of %q panicked: %v", i, in, err)
+				}
+			}()
+			parseAll([]byte(in))
+		}()
+	}
+}
+
+// parseAll passes data to each parser of the package.
+func parseAll(data []byte) {
+	var (
+		bytesTrue = func([]byte) bool { return true }
+		pairTrue  = func(_, _ []byte) bool { return true }
+		pairCont  = func(_, _ []byte) Control { return ControlContinue }
+		optCont   = func(int, []byte, []byte, []byte) Control { return ControlContinue }
+	)
+
+	s := NewScanner(data)
+	for s.Next() {
+	}
+	s = NewScannerFlags(data, ScanStrict|ScanValidUTF8|ScanUnfoldLWS)
+	s.SetBuffer(make([]byte, 0, 16))
+	s.SetLimits(8, 8, 2)
+	for s.Next() {
+	}
+
+	ScanTokens(data, bytesTrue)
+	ScanOptions(data, optCont)
+	ListScanner{Flags: ScanBareSemicolons | ScanEmptyValues | ScanExtValues}.ScanOptions(data, optCont)
+	ListScanner{Flags: ScanMediaTypes | ScanRawValues}.ScanOptions(data, optCont)
+	ScanOptionEvents(data, func(OptionEvent, int, []byte, []byte, []byte) Control {
+		return ControlContinue
+	})
+	ParseOptions(data, nil)
+	ParseOptionsFlags(data, nil, ParseMerge)
+	ScanCookie(data, pairTrue)
+	ScanSetCookie(data, pairTrue)
+	ParseSetCookie(data)
+	ScanDirectives(data, pairCont)
+	ScanPairs(data, pairCont)
+	ScanList(data, ',', func([]byte) Control { return ControlContinue })
+	ScanList(data, ';', func([]byte) Control { return ControlContinue })
+	for g := GrammarTokens; g <= GrammarStructuredDictionary; g++ {
+		Validate(data, g)
+	}
+	Normalize(nil, data, 0)
+	UnfoldLWS(nil, data)
+	UnescapeQuotedString(data)
+
+	ParseAccept(data, nil)
+	ParseAcceptCH(data, nil)
+	ParseAcceptEncoding(data, nil)
+	ParseAcceptRanges(data, nil)
+	ParseAccessControlAllowHeaders(data, nil)
+	ParseAccessControlExposeHeaders(data, nil)
+	ParseAccessControlMaxAge(data)
+	ParseAccessControlRequestHeaders(data, nil)
+	ParseAllow(data, nil, nil)
+	ParseAuthorization(data)
+	ParseChallenges(data, nil)
+	ParseConnection(data, nil)
+	ParseContentDisposition(data)
+	ParseContentEncoding(data, nil)
+	ParseContentLanguage(data, nil)
+	ParseContentLength(data)
+	_, _ = ParseContentLengthList(data)
+	ParseDeltaSeconds(data)
+	ParseETag(data)
+	ScanETagList(data, func(ETag) bool { return true })
+	ParseEarlyData(data)
+	ParseExpect(data, nil)
+	ParseExpectCT(data)
+	ParseExtValue(data)
+	ParseHTTPDate(data)
+	ParseHeaderLine(data)
+	ParseHost(data)
+	ParseIfRange(data)
+	ParseMaxForwards(data)
+	ParseMediaType(data)
+	ParseMethods(data, nil)
+	ParseNEL(data)
+	ParsePrefer(data, nil)
+	ParsePreferenceApplied(data, nil)
+	_, _ = ParseQuality(data)
+	ParseRange(data, nil)
+	ParseRateLimit(data)
+	ParseRateLimitPolicy(data, nil)
+	ParseRequestLine(data)
+	ParseResponseLine(data)
+	ParseSaveData(data)
+	ParseSecCHUA(data, nil)
+	ParseSecCHUAMobile(data)
+	ParseSecCHUAPlatform(data)
+	ParseServer(data, nil)
+	ParseTE(data, nil)
+	ParseTrailer(data, nil)
+	ParseTransferEncoding(data, nil)
+	ParseUserAgent(data, nil)
+	ParseVariantKey(data, nil)
+	ParseVariants(data, nil)
+	ParseVary(data, nil)
+	ScanXForwardedFor(data, bytesTrue)
+	LeftmostForwardedFor(data, bytesTrue)
+	RightmostForwardedFor(data, bytesTrue)
+	ValidForwardedNode(data)
+	ScanDigest(data, func(Digest) bool { return true })
+	ScanContentDigest(data, func(Digest) bool { return true })
+	ScanWantDigest(data, func([]byte, uint16) bool { return true })
+	ScanWantContentDigest(data, func([]byte, int) bool { return true })
+	Negotiate(data, []string{"text/html", "application/json"})
+	Negotiate(data, []string{"gzip", "br"})
+	NegotiateEncoding(data, []string{"gzip", "br"})
+
+	sfv.ParseItem(data)
+	sfv.ParseList(data, nil)
+	sfv.ParseDictionary(data, nil)
+}
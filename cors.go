@@ -0,0 +1,139 @@
+package httphead
+
+import "bytes"
+
+// ParseAccessControlRequestHeaders parses Access-Control-Request-Headers
+// header value and appends field names to given slice in order of their
+// appearance:
+//
+// Access-Control-Request-Headers = #field-name
+//
+// Empty value is valid. Appended names are subslices of data. It returns false
+// if data is malformed. It does not allocate if names has enough capacity.
+// See https://fetch.spec.whatwg.org/#http-requests
+func ParseAccessControlRequestHeaders(data []byte, names [][]byte) ([][]byte, bool) {
+	names, _, ok := parseFieldNames(data, names, false)
+	return names, ok
+}
+
+// ParseAccessControlAllowHeaders parses Access-Control-Allow-Headers header
+// value and appends field names to given slice in order of their appearance:
+//
+// Access-Control-Allow-Headers = #field-name
+//
+// Field name "*" is not appended, but reported as wildcard. Note that "*" is a
+// wildcard only for requests without credentials, and even then it does not
+// cover Authorization header. Appended names are subslices of data.
+//
+// It returns false if data is malformed.
+// See https://fetch.spec.whatwg.org/#http-responses
+func ParseAccessControlAllowHeaders(data []byte, names [][]byte) (_ [][]byte, wildcard, ok bool) {
+	return parseFieldNames(data, names, true)
+}
+
+// ParseAccessControlExposeHeaders parses Access-Control-Expose-Headers header
+// value. It follows the same rules as ParseAccessControlAllowHeaders(). Note
+// that "*" is a wildcard only for requests without credentials.
+func ParseAccessControlExposeHeaders(data []byte, names [][]byte) (_ [][]byte, wildcard, ok bool) {
+	return parseFieldNames(data, names, true)
+}
+
+// parseFieldNames appends field names of comma separated list to names. If
+// allowWildcard is true, "*" is reported as wildcard instead of being
+// appended.
+func parseFieldNames(data []byte, names [][]byte, allowWildcard bool) (_ [][]byte, wildcard, ok bool) {
+	n := len(names)
+	ok = scanTokenList(data, func(name []byte) bool {
+		if allowWildcard && isWildcard(name) {
+			wildcard = true
+		} else {
+			names = append(names, name)
+		}
+		return true
+	})
+	if !ok {
+		return names[:n], false, false
+	}
+	return names, wildcard, true
+}
+
+// AllowsHeaders reports whether all field names listed in requested, such as
+// Access-Control-Request-Headers header value, are listed in allowed, such as
+// Access-Control-Allow-Headers header value. Names are compared
+// case-insensitively.
+//
+// If credentials is false, "*" in allowed matches any name except
+// Authorization. It returns false if any of values is malformed. It does not
+// allocate.
+func AllowsHeaders(allowed, requested []byte, credentials bool) bool {
+	if !scanTokenList(allowed, func([]byte) bool { return true }) {
+		return false
+	}
+	ok := true
+	valid := scanTokenList(requested, func(name []byte) bool {
+		ok = containsToken(allowed, func(a []byte) bool {
+			if !credentials && isWildcard(a) {
+				return !bytes.EqualFold(name, headerAuthorization)
+			}
+			return bytes.EqualFold(a, name)
+		})
+		return ok
+	})
+	return valid && ok
+}
+
+var headerAuthorization = []byte("Authorization")
+
+// ParseAccessControlMaxAge parses Access-Control-Max-Age header value, which
+// is the number of seconds preflight request results could be cached:
+//
+// Access-Control-Max-Age = delta-seconds
+//
+// Surrounding whitespace is ignored. It returns false if p is malformed.
+// See https://fetch.spec.whatwg.org/#http-responses
+func ParseAccessControlMaxAge(p []byte) (n int64, ok bool) {
+	return ParseDeltaSeconds(trim(p))
+}
+
+// AppendFieldNames appends comma separated list of field names to dst, such
+// as value of Access-Control-Allow-Headers or Access-Control-Expose-Headers
+// header. It returns false without appending anything if some name is not a
+// token.
+func AppendFieldNames(dst []byte, names ...[]byte) ([]byte, bool) {
+	for _, name := range names {
+		if n, t := ScanToken(name); t != ItemToken || n != len(name) {
+			return dst, false
+		}
+	}
+	for i, name := range names {
+		if i > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = append(dst, name...)
+	}
+	return dst, true
+}
+
+// ValidOrigin reports whether p is a valid serialized origin, such as value of
+// Origin header:
+//
+// origin = scheme "://" host [ ":" port ] / "null"
+// scheme = ALPHA *( ALPHA / DIGIT / "+" / "-" / "." )
+//
+// Note that origin could not have path, query or fragment.
+// See https://tools.ietf.org/html/rfc6454#section-7
+func ValidOrigin(p []byte) bool {
+	if isNullOrigin(p) {
+		return true
+	}
+	scheme, _, _, ok := splitOrigin(p)
+	if !ok || !isAlpha(scheme[0]) {
+		return false
+	}
+	for _, c := range scheme[1:] {
+		if !isAlphaNum(c) && c != '+' && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
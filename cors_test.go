@@ -0,0 +1,140 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAccessControlRequestHeaders(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{"content-type,x-request-id", []string{"content-type", "x-request-id"}, true},
+		{" Content-Type , , X-Id ", []string{"Content-Type", "X-Id"}, true},
+		{"", nil, true},
+		{"content-type x-id", nil, false},
+		{`content-type, "x"`, nil, false},
+	} {
+		act, ok := ParseAccessControlRequestHeaders([]byte(test.in), nil)
+		if ok != test.ok || !reflect.DeepEqual(stringsOf(act), test.exp) {
+			t.Errorf("ParseAccessControlRequestHeaders(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseAccessControlAllowHeaders(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		any bool
+		ok  bool
+	}{
+		{"Content-Type, X-Id", []string{"Content-Type", "X-Id"}, false, true},
+		{"*, Authorization", []string{"Authorization"}, true, true},
+		{"*", nil, true, true},
+		{"X-Id; foo", nil, false, false},
+	} {
+		act, wildcard, ok := ParseAccessControlAllowHeaders([]byte(test.in), nil)
+		if ok != test.ok || wildcard != test.any || !reflect.DeepEqual(stringsOf(act), test.exp) {
+			t.Errorf(
+				"ParseAccessControlAllowHeaders(%q) = %q, %v, %v; want %q, %v, %v",
+				test.in, act, wildcard, ok, test.exp, test.any, test.ok,
+			)
+		}
+	}
+}
+
+func TestAllowsHeaders(t *testing.T) {
+	for _, test := range []struct {
+		allowed     string
+		requested   string
+		credentials bool
+		exp         bool
+	}{
+		{"Content-Type, X-Id", "content-type,x-id", false, true},
+		{"Content-Type", "content-type,x-id", false, false},
+		{"Content-Type", "", false, true},
+		{"*", "content-type,x-id", false, true},
+		{"*", "content-type,x-id", true, false},
+		{"*", "authorization", false, false},
+		{"*, Authorization", "authorization", false, true},
+		{"Content-Type, @", "content-type", false, false},
+		{"Content-Type", "content-type; x", false, false},
+	} {
+		act := AllowsHeaders([]byte(test.allowed), []byte(test.requested), test.credentials)
+		if act != test.exp {
+			t.Errorf(
+				"AllowsHeaders(%q, %q, %v) = %v; want %v",
+				test.allowed, test.requested, test.credentials, act, test.exp,
+			)
+		}
+	}
+}
+
+func TestAllowsHeadersAllocs(t *testing.T) {
+	allowed := []byte(`Content-Type, X-Request-Id, Authorization`)
+	requested := []byte(`authorization,content-type,x-request-id`)
+	allocs := testing.AllocsPerRun(100, func() {
+		AllowsHeaders(allowed, requested, true)
+	})
+	if allocs != 0 {
+		t.Errorf("AllowsHeaders() allocates %v times; want 0", allocs)
+	}
+}
+
+func TestParseAccessControlMaxAge(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp int64
+		ok  bool
+	}{
+		{"600", 600, true},
+		{" 86400 ", 86400, true},
+		{"-1", 0, false},
+		{"", 0, false},
+		{"1.5", 0, false},
+	} {
+		act, ok := ParseAccessControlMaxAge([]byte(test.in))
+		if act != test.exp || ok != test.ok {
+			t.Errorf("ParseAccessControlMaxAge(%q) = %v, %v; want %v, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestAppendFieldNames(t *testing.T) {
+	act, ok := AppendFieldNames(nil, []byte("Content-Type"), []byte("X-Id"))
+	if exp := "Content-Type, X-Id"; !ok || string(act) != exp {
+		t.Errorf("AppendFieldNames() = %q, %v; want %q, true", act, ok, exp)
+	}
+	act, ok = AppendFieldNames([]byte("x"), []byte("X-Id"), []byte("bad name"))
+	if ok || string(act) != "x" {
+		t.Errorf("AppendFieldNames() = %q, %v; want %q, false", act, ok, "x")
+	}
+}
+
+func TestValidOrigin(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp bool
+	}{
+		{"https://example.com", true},
+		{"http://example.com:8080", true},
+		{"chrome-extension://abc", true},
+		{"http://[::1]:8080", true},
+		{"null", true},
+		{"https://example.com/", false},
+		{"https://example.com?a", false},
+		{"https://user@example.com", false},
+		{"https://example.com:http", false},
+		{"1http://example.com", false},
+		{"ht_tp://example.com", false},
+		{"example.com", false},
+		{"", false},
+	} {
+		if act := ValidOrigin([]byte(test.in)); act != test.exp {
+			t.Errorf("ValidOrigin(%q) = %v; want %v", test.in, act, test.exp)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package httphead
+
+import "time"
+
+// httpDateLayouts contains layouts of IMF-fixdate, rfc850-date and
+// asctime-date respectively.
+var httpDateLayouts = [...]string{
+	"Mon, 02 Jan 2006 15:04:05 GMT",
+	"Monday, 02-Jan-06 15:04:05 GMT",
+	"Mon Jan _2 15:04:05 2006",
+}
+
+// ParseHTTPDate parses RFC9110 HTTP-date from data. It accepts preferred
+// IMF-fixdate format as well as obsolete rfc850-date and asctime-date formats.
+// Surrounding whitespace is ignored.
+//
+// It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-5.6.7
+func ParseHTTPDate(data []byte) (t time.Time, ok bool) {
+	s := string(trim(data))
+	for _, layout := range httpDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,32 @@
+package httphead
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHTTPDate(t *testing.T) {
+	exp := time.Date(1994, time.November, 6, 8, 49, 37, 0, time.UTC)
+	for _, test := range []struct {
+		in string
+		ok bool
+	}{
+		{"Sun, 06 Nov 1994 08:49:37 GMT", true},
+		{"Sunday, 06-Nov-94 08:49:37 GMT", true},
+		{"Sun Nov  6 08:49:37 1994", true},
+		{" Sun, 06 Nov 1994 08:49:37 GMT ", true},
+		{"Sun, 06 Nov 1994 08:49:37 PST", false},
+		{"1994-11-06T08:49:37Z", false},
+		{"", false},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			act, ok := ParseHTTPDate([]byte(test.in))
+			if ok != test.ok {
+				t.Fatalf("ParseHTTPDate(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			}
+			if ok && !act.Equal(exp) {
+				t.Errorf("ParseHTTPDate(%q) = %v; want %v", test.in, act, exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package httphead
+
+import "bytes"
+
+// PerMessageDeflate represents parameters of the "permessage-deflate"
+// WebSocket extension.
+// See https://tools.ietf.org/html/rfc7692#section-7.1
+type PerMessageDeflate struct {
+	// ServerNoContextTakeover reports whether "server_no_context_takeover"
+	// parameter is present.
+	ServerNoContextTakeover bool
+
+	// ClientNoContextTakeover reports whether "client_no_context_takeover"
+	// parameter is present.
+	ClientNoContextTakeover bool
+
+	// ServerMaxWindowBits is the value of "server_max_window_bits"
+	// parameter. It is zero if parameter is missing.
+	ServerMaxWindowBits int
+
+	// ClientMaxWindowBits is the value of "client_max_window_bits"
+	// parameter. It is zero if parameter is missing or has no value.
+	ClientMaxWindowBits int
+
+	// HasClientMaxWindowBits reports whether "client_max_window_bits"
+	// parameter is present. In client's offer it could be present without
+	// value, which means that client supports the parameter.
+	HasClientMaxWindowBits bool
+}
+
+// ExtensionPerMessageDeflate is the name of the "permessage-deflate"
+// WebSocket extension.
+const ExtensionPerMessageDeflate = "permessage-deflate"
+
+// ParsePerMessageDeflate parses and validates parameters of the
+// "permessage-deflate" extension from opt, which is usually an element of
+// Sec-WebSocket-Extensions header value parsed with ParseOptions().
+//
+// It returns false if opt is not the "permessage-deflate" extension or its
+// parameters are invalid, that is, some parameter is unknown, duplicated or
+// has invalid value. Such offers must be declined and such responses must
+// fail the connection.
+// See https://tools.ietf.org/html/rfc7692#section-7.1
+func ParsePerMessageDeflate(opt Option) (p PerMessageDeflate, ok bool) {
+	if !bytes.EqualFold(opt.Name, []byte(ExtensionPerMessageDeflate)) {
+		return p, false
+	}
+	var seen [4]bool
+	for _, param := range opt.Parameters.data() {
+		var i int
+		switch string(param.key) {
+		case "server_no_context_takeover":
+			i = 0
+			ok = len(param.value) == 0
+			p.ServerNoContextTakeover = true
+		case "client_no_context_takeover":
+			i = 1
+			ok = len(param.value) == 0
+			p.ClientNoContextTakeover = true
+		case "server_max_window_bits":
+			i = 2
+			p.ServerMaxWindowBits, ok = parseWindowBits(param.value)
+		case "client_max_window_bits":
+			i = 3
+			p.HasClientMaxWindowBits = true
+			if ok = len(param.value) == 0; !ok {
+				p.ClientMaxWindowBits, ok = parseWindowBits(param.value)
+			}
+		default:
+			return PerMessageDeflate{}, false
+		}
+		if !ok || seen[i] {
+			return PerMessageDeflate{}, false
+		}
+		seen[i] = true
+	}
+	return p, true
+}
+
+// parseWindowBits parses LZ77 sliding window size exponent, which must be
+// a decimal integer in range from 8 to 15 without leading zeros.
+func parseWindowBits(p []byte) (int, bool) {
+	switch {
+	case len(p) == 1 && '8' <= p[0] && p[0] <= '9':
+		return int(p[0] - '0'), true
+	case len(p) == 2 && p[0] == '1' && '0' <= p[1] && p[1] <= '5':
+		return 10 + int(p[1]-'0'), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,67 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePerMessageDeflate(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp PerMessageDeflate
+		ok  bool
+	}{
+		{
+			in: `permessage-deflate`,
+			ok: true,
+		},
+		{
+			in: `permessage-deflate; client_max_window_bits`,
+			exp: PerMessageDeflate{
+				HasClientMaxWindowBits: true,
+			},
+			ok: true,
+		},
+		{
+			in: `permessage-deflate; server_no_context_takeover; client_no_context_takeover; server_max_window_bits=10; client_max_window_bits="8"`,
+			exp: PerMessageDeflate{
+				ServerNoContextTakeover: true,
+				ClientNoContextTakeover: true,
+				ServerMaxWindowBits:     10,
+				ClientMaxWindowBits:     8,
+				HasClientMaxWindowBits:  true,
+			},
+			ok: true,
+		},
+		{
+			in: `Permessage-Deflate; server_max_window_bits=15`,
+			exp: PerMessageDeflate{
+				ServerMaxWindowBits: 15,
+			},
+			ok: true,
+		},
+		{in: `x-webkit-deflate-frame`},
+		{in: `permessage-deflate; server_max_window_bits`},
+		{in: `permessage-deflate; server_max_window_bits=7`},
+		{in: `permessage-deflate; server_max_window_bits=16`},
+		{in: `permessage-deflate; server_max_window_bits=010`},
+		{in: `permessage-deflate; client_max_window_bits=x`},
+		{in: `permessage-deflate; server_no_context_takeover=1`},
+		{in: `permessage-deflate; server_no_context_takeover; server_no_context_takeover`},
+		{in: `permessage-deflate; client_max_window_bits; client_max_window_bits=9`},
+		{in: `permessage-deflate; foo`},
+	} {
+		opts, ok := ParseOptions([]byte(test.in), nil)
+		if !ok || len(opts) != 1 {
+			t.Fatalf("ParseOptions(%q) = %v, %v; want single option", test.in, opts, ok)
+		}
+		p, ok := ParsePerMessageDeflate(opts[0])
+		if ok != test.ok {
+			t.Errorf("ParsePerMessageDeflate(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !reflect.DeepEqual(p, test.exp) {
+			t.Errorf("ParsePerMessageDeflate(%q) = %+v; want %+v", test.in, p, test.exp)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package httphead
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+)
+
+// ScanWantDigest scans Want-Digest header value and calls it for each digest
+// algorithm with its quality value (in thousandths, see ParseQuality()):
+//
+// Want-Digest = 1#want-digest-value
+// want-digest-value = digest-algorithm [ ";" "q" "=" qvalue ]
+//
+// Algorithms without quality value are reported with quality of 1000. Other
+// parameters are ignored. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc3230#section-4.3.1
+func ScanWantDigest(data []byte, it func(alg []byte, q uint16) bool) bool {
+	return scanWeighted(data, it)
+}
+
+// SelectDigest returns the most preferred digest algorithm from Want-Digest
+// header value data which is also present in supported list. Algorithm names
+// are compared case-insensitively and returned as they are in supported.
+// Algorithms with zero quality are never selected. If multiple algorithms
+// have the same quality, the first one in data is selected.
+//
+// It returns false if data is malformed or there is no acceptable algorithm.
+func SelectDigest(data []byte, supported []string) (alg string, ok bool) {
+	var best uint16
+	wellformed := ScanWantDigest(data, func(name []byte, q uint16) bool {
+		if q <= best {
+			return true
+		}
+		for _, s := range supported {
+			if bytes.EqualFold([]byte(s), name) {
+				alg, best = s, q
+				break
+			}
+		}
+		return true
+	})
+	if !wellformed || best == 0 {
+		return "", false
+	}
+	return alg, true
+}
+
+// Digest represents single digest of Digest, Content-Digest or Repr-Digest
+// header value.
+type Digest struct {
+	// Algorithm is the digest algorithm, such as "sha-256".
+	Algorithm []byte
+
+	// Value is the base64-encoded digest value.
+	Value []byte
+}
+
+// Decode appends decoded digest value to dst. It returns false if value is
+// not a valid base64 text.
+func (d Digest) Decode(dst []byte) ([]byte, bool) {
+	n := len(dst)
+	m := base64.StdEncoding.DecodedLen(len(d.Value))
+	if cap(dst)-n < m {
+		grow := make([]byte, n, n+m)
+		copy(grow, dst)
+		dst = grow
+	}
+	m, err := base64.StdEncoding.Decode(dst[n:n+m], d.Value)
+	if err != nil {
+		return dst[:n], false
+	}
+	return dst[:n+m], true
+}
+
+// ScanDigest scans legacy Digest header value and calls it for each digest:
+//
+// Digest          = 1#instance-digest
+// instance-digest = digest-algorithm "=" <encoded digest output>
+//
+// Note that digests are subslices of data. It returns false if data is
+// malformed.
+// See https://tools.ietf.org/html/rfc3230#section-4.3.2
+func ScanDigest(data []byte, it func(Digest) bool) bool {
+	if exceedsLimit(data) {
+		return false
+	}
+	var ok bool
+	for len(data) > 0 {
+		v := data
+		if i := bytes.IndexByte(data, ','); i != -1 {
+			v, data = data[:i], data[i+1:]
+		} else {
+			data = nil
+		}
+		if v = trim(v); len(v) == 0 {
+			continue
+		}
+		i := bytes.IndexByte(v, '=')
+		if i == -1 {
+			return false
+		}
+		d := Digest{
+			Algorithm: v[:i],
+			Value:     v[i+1:],
+		}
+		if !isToken(d.Algorithm) || !isBase64(d.Value) {
+			return false
+		}
+		ok = true
+		if !it(d) {
+			break
+		}
+	}
+	return ok
+}
+
+// ScanContentDigest scans Content-Digest or Repr-Digest header value, which
+// is a structured field dictionary of byte sequences, and calls it for each
+// digest:
+//
+// Content-Digest = sf-dictionary
+// member         = key "=" ":" *base64 ":" parameters
+//
+// Parameters of members are ignored. Note that digests are subslices of data.
+// It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9530#section-2
+func ScanContentDigest(data []byte, it func(Digest) bool) bool {
+	valid := true
+	ok := scanDictionary(data, func(key, value []byte) bool {
+		n := len(value)
+		if n < 2 || value[0] != ':' || value[n-1] != ':' || !isBase64(value[1:n-1]) {
+			valid = false
+			return false
+		}
+		return it(Digest{
+			Algorithm: key,
+			Value:     value[1 : n-1],
+		})
+	})
+	return ok && valid
+}
+
+// ScanWantContentDigest scans Want-Content-Digest or Want-Repr-Digest header
+// value and calls it for each digest algorithm with its preference, which is
+// an integer in range from 0 to 10. Zero preference means that algorithm is
+// not acceptable.
+//
+// It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9530#section-4
+func ScanWantContentDigest(data []byte, it func(alg []byte, pref int) bool) bool {
+	valid := true
+	ok := scanDictionary(data, func(key, value []byte) bool {
+		n, ok := ParseDigits(value)
+		if !ok || n > 10 {
+			valid = false
+			return false
+		}
+		return it(key, int(n))
+	})
+	return ok && valid
+}
+
+// WriteDigest writes digests to the dest in the form of legacy Digest header
+// value. It returns ErrMalformed without writing anything if some of
+// algorithms is not a valid token or some of values is not a valid base64
+// text.
+func WriteDigest(dest io.Writer, digests []Digest) (n int, err error) {
+	if !validDigests(digests, isToken) {
+		return 0, ErrMalformed
+	}
+	w := writer{w: dest}
+	for i, d := range digests {
+		if i > 0 {
+			w.write(comma)
+		}
+		w.write(d.Algorithm)
+		w.write(equality)
+		w.write(d.Value)
+	}
+	return w.result()
+}
+
+// WriteContentDigest writes digests to the dest in the form of
+// Content-Digest or Repr-Digest header value. It returns ErrMalformed without
+// writing anything if some of algorithms is not a valid structured field key
+// or some of values is not a valid base64 text.
+func WriteContentDigest(dest io.Writer, digests []Digest) (n int, err error) {
+	if !validDigests(digests, isKey) {
+		return 0, ErrMalformed
+	}
+	w := writer{w: dest}
+	for i, d := range digests {
+		if i > 0 {
+			w.write(comma)
+		}
+		w.write(d.Algorithm)
+		w.write(equality)
+		w.write(colon)
+		w.write(d.Value)
+		w.write(colon)
+	}
+	return w.result()
+}
+
+var colon = []byte{':'}
+
+func validDigests(digests []Digest, validAlgorithm func([]byte) bool) bool {
+	for _, d := range digests {
+		if !validAlgorithm(d.Algorithm) || !isBase64(d.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// scanDictionary scans members of structured field dictionary which values
+// are bare items without inner lists, calling it for each key and value.
+// Member parameters are skipped. If it returns false, scanning stops.
+// See https://tools.ietf.org/html/rfc8941#section-3.2
+func scanDictionary(data []byte, it func(key, value []byte) bool) bool {
+	if exceedsLimit(data) {
+		return false
+	}
+	data = trim(data)
+	for len(data) > 0 {
+		if c := data[0]; (c < 'a' || c > 'z') && c != '*' {
+			return false
+		}
+		i := 1
+		for i < len(data) && isKeyChar(data[i]) {
+			i++
+		}
+		if i == len(data) || data[i] != '=' {
+			return false
+		}
+		key := data[:i]
+		data = data[i+1:]
+
+		i = 0
+		for i < len(data) && data[i] != ',' && data[i] != ';' && !isSpace(data[i]) {
+			i++
+		}
+		value := data[:i]
+		data = data[i:]
+		data = data[SkipSpace(data):]
+		if len(data) > 0 && data[0] == ';' {
+			// Skip member parameters.
+			if i = bytes.IndexByte(data, ','); i == -1 {
+				i = len(data)
+			}
+			data = data[i:]
+		}
+		if len(data) > 0 {
+			if data[0] != ',' {
+				return false
+			}
+			if data = trim(data[1:]); len(data) == 0 {
+				return false
+			}
+		}
+		if !it(key, value) {
+			break
+		}
+	}
+	return true
+}
+
+func isKeyChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		return true
+	}
+	return c == '_' || c == '-' || c == '.' || c == '*'
+}
+
+// isKey reports whether p is a structured field key.
+// See https://tools.ietf.org/html/rfc8941#section-3.1.2
+func isKey(p []byte) bool {
+	if len(p) == 0 || (p[0] < 'a' || p[0] > 'z') && p[0] != '*' {
+		return false
+	}
+	for _, c := range p[1:] {
+		if !isKeyChar(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isBase64(p []byte) bool {
+	var pad bool
+	for i, c := range p {
+		switch {
+		case c == '=' && len(p)-i <= 2:
+			pad = true
+		case pad:
+			return false
+		case isAlphaNum(c), c == '+', c == '/':
+		default:
+			return false
+		}
+	}
+	return len(p)%4 == 0
+}
+
+func isToken(p []byte) bool {
+	n, t := ScanToken(p)
+	return t == ItemToken && n == len(p)
+}
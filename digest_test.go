@@ -0,0 +1,215 @@
+package httphead
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestScanWantDigest(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		q   []uint16
+		ok  bool
+	}{
+		{
+			in:  "SHA-512;q=0.3, sha-256;q=1, md5;q=0",
+			exp: []string{"SHA-512", "sha-256", "md5"},
+			q:   []uint16{300, 1000, 0},
+			ok:  true,
+		},
+		{
+			in:  "sha-256",
+			exp: []string{"sha-256"},
+			q:   []uint16{1000},
+			ok:  true,
+		},
+		{
+			in: "sha-256;q=2",
+			ok: false,
+		},
+		{
+			in: "",
+			ok: false,
+		},
+	} {
+		var (
+			act []string
+			q   []uint16
+		)
+		ok := ScanWantDigest([]byte(test.in), func(alg []byte, v uint16) bool {
+			act = append(act, string(alg))
+			q = append(q, v)
+			return true
+		})
+		if ok != test.ok || !reflect.DeepEqual(act, test.exp) || !reflect.DeepEqual(q, test.q) {
+			t.Errorf("ScanWantDigest(%q) = %q, %v, %v; want %q, %v, %v", test.in, act, q, ok, test.exp, test.q, test.ok)
+		}
+	}
+}
+
+func TestSelectDigest(t *testing.T) {
+	supported := []string{"sha-256", "sha-512"}
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{"SHA-512;q=0.3, sha-256;q=1, md5;q=0", "sha-256", true},
+		{"md5, SHA-512;q=0.5", "sha-512", true},
+		{"sha-512, sha-256", "sha-512", true},
+		{"md5", "", false},
+		{"sha-256;q=0", "", false},
+		{"sha-256;q=x", "", false},
+	} {
+		act, ok := SelectDigest([]byte(test.in), supported)
+		if act != test.exp || ok != test.ok {
+			t.Errorf("SelectDigest(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestScanDigest(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{
+			in:  `SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=`,
+			exp: []string{`SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=`},
+			ok:  true,
+		},
+		{
+			in:  `md5=HUXZLQLMuI/KZ5KDcJPcOA==, ,sha=thvDyvhfIqlvFe+A9MYgxAfm1q5=`,
+			exp: []string{`md5=HUXZLQLMuI/KZ5KDcJPcOA==`, `sha=thvDyvhfIqlvFe+A9MYgxAfm1q5=`},
+			ok:  true,
+		},
+		{in: ``},
+		{in: `md5`},
+		{in: `md5=abc`},
+		{in: `md5=ab=c`},
+		{in: `m@5=abcd`},
+	} {
+		var act []string
+		ok := ScanDigest([]byte(test.in), func(d Digest) bool {
+			act = append(act, string(d.Algorithm)+"="+string(d.Value))
+			return true
+		})
+		if ok != test.ok {
+			t.Errorf("ScanDigest(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ScanDigest(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestScanContentDigest(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{
+			in:  `sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:`,
+			exp: []string{`sha-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=`},
+			ok:  true,
+		},
+		{
+			in:  `sha-256=:YQ==:;foo=bar , sha-512=::`,
+			exp: []string{`sha-256=YQ==`, `sha-512=`},
+			ok:  true,
+		},
+		{in: `SHA-256=:YQ==:`},
+		{in: `sha-256=YQ==`},
+		{in: `sha-256=:YQ=:`},
+		{in: `sha-256=:YQ==: x`},
+		{in: `sha-256=:YQ==:,`},
+		{in: `sha-256`},
+	} {
+		var act []string
+		ok := ScanContentDigest([]byte(test.in), func(d Digest) bool {
+			act = append(act, string(d.Algorithm)+"="+string(d.Value))
+			return true
+		})
+		if ok != test.ok {
+			t.Errorf("ScanContentDigest(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ScanContentDigest(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestScanWantContentDigest(t *testing.T) {
+	var act []string
+	ok := ScanWantContentDigest([]byte(`sha-512=3, sha-256=10, unixsum=0`), func(alg []byte, pref int) bool {
+		act = append(act, string(alg)+"="+strconv.Itoa(pref))
+		return true
+	})
+	if exp := []string{"sha-512=3", "sha-256=10", "unixsum=0"}; !ok || !reflect.DeepEqual(act, exp) {
+		t.Errorf("ScanWantContentDigest() = %q, %v; want %q, true", act, ok, exp)
+	}
+	for _, in := range []string{`sha-256=11`, `sha-256=-1`, `sha-256`} {
+		if ScanWantContentDigest([]byte(in), func([]byte, int) bool { return true }) {
+			t.Errorf("ScanWantContentDigest(%q) wellformed sign is true; want false", in)
+		}
+	}
+}
+
+func TestDigestDecode(t *testing.T) {
+	d := Digest{Value: []byte("aGVsbG8=")}
+	act, ok := d.Decode([]byte("x"))
+	if !ok || string(act) != "xhello" {
+		t.Errorf("Decode() = %q, %v; want %q, true", act, ok, "xhello")
+	}
+	d = Digest{Value: []byte("a")}
+	if act, ok := d.Decode(nil); ok || len(act) != 0 {
+		t.Errorf("Decode() = %q, %v; want empty, false", act, ok)
+	}
+}
+
+func TestWriteDigest(t *testing.T) {
+	digests := []Digest{
+		{Algorithm: []byte("sha-256"), Value: []byte("YQ==")},
+		{Algorithm: []byte("sha-512"), Value: []byte("Yg==")},
+	}
+	var buf bytes.Buffer
+	if _, err := WriteDigest(&buf, digests); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := buf.String(), `sha-256=YQ==,sha-512=Yg==`; act != exp {
+		t.Errorf("WriteDigest() = %s; want %s", act, exp)
+	}
+	buf.Reset()
+	if _, err := WriteContentDigest(&buf, digests); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := buf.String(), `sha-256=:YQ==:,sha-512=:Yg==:`; act != exp {
+		t.Errorf("WriteContentDigest() = %s; want %s", act, exp)
+	}
+	for _, d := range []Digest{
+		{Algorithm: []byte("sha-256"), Value: []byte("YQ==\r\nX-Injected: 1")},
+		{Algorithm: []byte("sha-256\r\nX-Injected: 1"), Value: []byte("YQ==")},
+		{Algorithm: []byte("sha-256"), Value: []byte("YQ=,")},
+		{Algorithm: []byte(""), Value: []byte("YQ==")},
+	} {
+		buf.Reset()
+		if _, err := WriteDigest(&buf, []Digest{d}); err != ErrMalformed || buf.Len() != 0 {
+			t.Errorf("WriteDigest(%q) = %q, %v; want nothing, %v", d, buf.String(), err, ErrMalformed)
+		}
+		if _, err := WriteContentDigest(&buf, []Digest{d}); err != ErrMalformed || buf.Len() != 0 {
+			t.Errorf("WriteContentDigest(%q) = %q, %v; want nothing, %v", d, buf.String(), err, ErrMalformed)
+		}
+	}
+	buf.Reset()
+	upper := []Digest{{Algorithm: []byte("SHA-256"), Value: []byte("YQ==")}}
+	if _, err := WriteContentDigest(&buf, upper); err != ErrMalformed {
+		t.Errorf("WriteContentDigest(%q) error is %v; want %v", upper, err, ErrMalformed)
+	}
+}
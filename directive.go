@@ -0,0 +1,148 @@
+package httphead
+
+import "io"
+
+// ScanDirectives parses data in this form:
+//
+// directives = 1#directive
+// directive  = token [ "=" ( token / quoted-string ) ]
+//
+// Such grammar is used by Cache-Control, X-Robots-Tag and many other headers.
+// It calls given callback with directive name and its value (which is nil if
+// directive has no value). Given callback should return one of the defined
+// Control* values. ControlBreak means that no more directives should be
+// scanned. Note that ControlSkip has the same effect as ControlContinue, since
+// directive has no parts to skip.
+//
+// It returns false if data is malformed.
+func ScanDirectives(data []byte, it func(name, value []byte) Control) bool {
+	return DefaultListScanner.ScanDirectives(data, it)
+}
+
+// ScanDirectives is the same as ScanDirectives() function, but respects
+// scanner configuration.
+func (s ListScanner) ScanDirectives(data []byte, it func(name, value []byte) Control) bool {
+	lexer := newScanner(data, s.Flags)
+	lexer.SetLimits(s.MaxItems, s.MaxTokenLength, s.MaxCommentDepth)
+
+	const (
+		stateName = iota
+		stateAfterName
+		stateValue
+		stateAfterValue
+	)
+	var (
+		state int
+		name  []byte
+		n     int
+	)
+	call := func(value []byte) bool {
+		switch it(name, value) {
+		case ControlBreak:
+			return false
+		case ControlContinue, ControlSkip:
+			return true
+		default:
+			panic("unexpected control value")
+		}
+	}
+	for lexer.Next() {
+		t := lexer.Type()
+		v := lexer.Bytes()
+		switch {
+		case t == ItemToken && state == stateName:
+			if n++; s.Max > 0 && n > s.Max {
+				return false
+			}
+			name = v
+			state = stateAfterName
+
+		case t == ItemToken && state == stateValue,
+			t == ItemString && state == stateValue:
+			state = stateAfterValue
+			if !call(v) {
+				return true
+			}
+
+		case t == ItemSeparator && isEquality(v) && state == stateAfterName:
+			state = stateValue
+
+		case t == ItemSeparator && isComma(v) && state != stateValue:
+			if state == stateName && s.Flags&ScanRejectEmpty != 0 {
+				return false
+			}
+			if state == stateAfterName && !call(nil) {
+				return true
+			}
+			state = stateName
+			if s.Flags&ScanRejectEmpty != 0 && lexer.Buffered() == SkipSpace(data[lexer.pos:]) {
+				// Trailing comma.
+				return false
+			}
+
+		default:
+			return false
+		}
+	}
+	if lexer.err != nil || state == stateValue {
+		return false
+	}
+	if state == stateAfterName && !call(nil) {
+		return true
+	}
+	return n > 0 && n >= s.Min
+}
+
+// ScanPairs parses data in this form:
+//
+// pairs = 1#pair
+// pair  = token "=" ( token / quoted-string )
+//
+// Such grammar is used by Keep-Alive and similar headers. Unlike
+// ScanDirectives(), each element must have a value; unlike ScanOptions(),
+// the first token of element is not treated as option name. It calls given
+// callback with key and unquoted value of each pair. ControlBreak means that
+// no more pairs should be scanned.
+//
+// It returns false if data is malformed.
+func ScanPairs(data []byte, it func(key, value []byte) Control) bool {
+	return DefaultListScanner.ScanPairs(data, it)
+}
+
+// ScanPairs is the same as ScanPairs() function, but respects scanner
+// configuration.
+func (s ListScanner) ScanPairs(data []byte, it func(key, value []byte) Control) bool {
+	valid := true
+	ok := s.ScanDirectives(data, func(key, value []byte) Control {
+		if value == nil {
+			valid = false
+			return ControlBreak
+		}
+		return it(key, value)
+	})
+	return ok && valid
+}
+
+// WriteDirectives writes directives list to the dest. Each parameter of
+// directives is written as directive name and its value. Parameters with nil
+// value are written without "=" sign. Values are wrapped into quoted-string if
+// they contain any non-token characters.
+func WriteDirectives(dest io.Writer, directives Parameters) (n int, err error) {
+	w := writer{w: dest}
+	for i, p := range directives.data() {
+		if i > 0 {
+			w.write(comma)
+		}
+		writeTokenSanitized(&w, p.key)
+		if p.value != nil {
+			w.write(equality)
+			if len(p.value) == 0 {
+				w.write(quote)
+				w.write(quote)
+			} else {
+				writeTokenSanitized(&w, p.value)
+			}
+		}
+	}
+	return w.result()
+}
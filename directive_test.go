@@ -0,0 +1,169 @@
+package httphead
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestScanDirectives(t *testing.T) {
+	for _, test := range []struct {
+		label string
+		in    string
+		s     ListScanner
+		exp   string
+		ok    bool
+	}{
+		{
+			label: "simple",
+			in:    `no-cache, max-age=60, private="Set-Cookie, Foo"`,
+			exp:   `[no-cache:<nil> max-age:60 private:Set-Cookie, Foo]`,
+			ok:    true,
+		},
+		{
+			label: "noindex",
+			in:    `noindex,nofollow`,
+			exp:   `[noindex:<nil> nofollow:<nil>]`,
+			ok:    true,
+		},
+		{
+			label: "empty_elements",
+			in:    `, a,, b ,`,
+			exp:   `[a:<nil> b:<nil>]`,
+			ok:    true,
+		},
+		{
+			label: "reject_empty",
+			in:    `a,, b`,
+			s:     ListScanner{Flags: ScanRejectEmpty},
+			exp:   `[a:<nil>]`,
+			ok:    false,
+		},
+		{
+			label: "reject_empty",
+			in:    `a, b, `,
+			s:     ListScanner{Flags: ScanRejectEmpty},
+			exp:   `[a:<nil> b:<nil>]`,
+			ok:    false,
+		},
+		{
+			label: "params",
+			in:    `a=1;b=2`,
+			exp:   `[a:1]`,
+			ok:    false,
+		},
+		{
+			label: "no_value",
+			in:    `a=`,
+			exp:   `[]`,
+			ok:    false,
+		},
+		{
+			label: "empty",
+			in:    ` `,
+			exp:   `[]`,
+			ok:    false,
+		},
+		{
+			label: "break",
+			in:    `a, stop, b`,
+			exp:   `[a:<nil> stop:<nil>]`,
+			ok:    true,
+		},
+	} {
+		t.Run(test.label, func(t *testing.T) {
+			act := []string{}
+			ok := test.s.ScanDirectives([]byte(test.in), func(name, value []byte) Control {
+				v := "<nil>"
+				if value != nil {
+					v = string(value)
+				}
+				act = append(act, string(name)+":"+v)
+				if string(name) == "stop" {
+					return ControlBreak
+				}
+				return ControlContinue
+			})
+			if ok != test.ok {
+				t.Errorf("ScanDirectives(%q) = %v; want %v", test.in, ok, test.ok)
+			}
+			if fmt.Sprint(act) != test.exp {
+				t.Errorf("ScanDirectives(%q) scanned %v; want %v", test.in, act, test.exp)
+			}
+		})
+	}
+}
+
+func TestScanPairs(t *testing.T) {
+	for _, test := range []struct {
+		label string
+		in    string
+		exp   string
+		ok    bool
+	}{
+		{
+			label: "keep_alive",
+			in:    `timeout=5, max=1000`,
+			exp:   `[timeout:5 max:1000]`,
+			ok:    true,
+		},
+		{
+			label: "quoted",
+			in:    `a = "x, y" ,, b=c`,
+			exp:   `[a:x, y b:c]`,
+			ok:    true,
+		},
+		{
+			label: "no_value",
+			in:    `a=1, b, c=2`,
+			exp:   `[a:1]`,
+			ok:    false,
+		},
+		{
+			label: "option_like",
+			in:    `foo;a=1`,
+			exp:   `[]`,
+			ok:    false,
+		},
+		{
+			label: "break",
+			in:    `a=1, stop=1, b=2`,
+			exp:   `[a:1 stop:1]`,
+			ok:    true,
+		},
+	} {
+		t.Run(test.label, func(t *testing.T) {
+			act := []string{}
+			ok := ScanPairs([]byte(test.in), func(key, value []byte) Control {
+				act = append(act, string(key)+":"+string(value))
+				if string(key) == "stop" {
+					return ControlBreak
+				}
+				return ControlContinue
+			})
+			if ok != test.ok {
+				t.Errorf("ScanPairs(%q) = %v; want %v", test.in, ok, test.ok)
+			}
+			if fmt.Sprint(act) != test.exp {
+				t.Errorf("ScanPairs(%q) scanned %v; want %v", test.in, act, test.exp)
+			}
+		})
+	}
+}
+
+func TestWriteDirectives(t *testing.T) {
+	var p Parameters
+	p.Set([]byte("no-cache"), nil)
+	p.Set([]byte("max-age"), []byte("60"))
+	p.Set([]byte("private"), []byte("Set-Cookie, Foo"))
+	p.Set([]byte("x"), []byte{})
+
+	var buf bytes.Buffer
+	if _, err := WriteDirectives(&buf, p); err != nil {
+		t.Fatal(err)
+	}
+	exp := `no-cache,max-age=60,private="Set-Cookie, Foo",x=""`
+	if act := buf.String(); act != exp {
+		t.Errorf("WriteDirectives() = %q; want %q", act, exp)
+	}
+}
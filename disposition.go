@@ -0,0 +1,85 @@
+package httphead
+
+import "bytes"
+
+// ContentDisposition represents parsed value of Content-Disposition header.
+// See https://tools.ietf.org/html/rfc6266
+type ContentDisposition struct {
+	// Type is the disposition type, such as "inline" or "attachment".
+	Type []byte
+
+	// Parameters contains disposition parameters with their raw values. That
+	// is, extended parameters such as "filename*" are not decoded.
+	Parameters Parameters
+}
+
+// ParseContentDisposition parses Content-Disposition header value:
+//
+// content-disposition = "Content-Disposition" ":" disposition-type *( ";" disposition-parm )
+// disposition-parm    = filename-parm / disp-ext-parm
+// filename-parm       = "filename" "=" value / "filename*" "=" ext-value
+//
+// Quoted values are scanned and unescaped as RFC9110 defines it, in the same
+// way as UnescapeQuotedString() does, such that Windows paths like
+// "C:\\dir\\a.txt" keep their backslashes.
+//
+// Note that returned value consists of subslices of data, except quoted
+// values with escaped characters. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc6266#section-4.1
+func ParseContentDisposition(data []byte) (d ContentDisposition, ok bool) {
+	valid := true
+	s := ListScanner{Flags: ScanStrict, Max: 1}
+	ok = s.ScanOptions(data, func(_ int, name, attr, val []byte) Control {
+		d.Type = name
+		if attr == nil {
+			return ControlContinue
+		}
+		if val == nil {
+			valid = false
+			return ControlBreak
+		}
+		d.Parameters.Set(attr, val)
+		return ControlContinue
+	})
+	if !ok || !valid {
+		return ContentDisposition{}, false
+	}
+	return d, true
+}
+
+// IsAttachment reports whether disposition type is "attachment". Note that
+// unknown disposition types must be handled as "attachment" too.
+func (d ContentDisposition) IsAttachment() bool {
+	return !bytes.EqualFold(d.Type, dispositionInline)
+}
+
+// Filename returns the value of "filename" parameter. If there is a
+// "filename*" parameter with valid RFC8187 ext-value in "UTF-8" or
+// "ISO-8859-1" charset, it takes precedence and its decoded value is
+// returned. Parameter names are matched case-insensitively.
+//
+// Note that returned value is not sanitized in any way and must not be used as
+// a file path as is.
+// See https://tools.ietf.org/html/rfc6266#section-4.3
+func (d ContentDisposition) Filename() (filename []byte, ok bool) {
+	return d.Param("filename")
+}
+
+// Param returns the value of disposition parameter with given name, respecting
+// extended parameter in the same way as Filename() does.
+func (d ContentDisposition) Param(name string) (value []byte, ok bool) {
+	var plain []byte
+	for _, p := range d.Parameters.data() {
+		switch {
+		case len(p.key) == len(name)+1 && isExtName(p.key) && bytes.EqualFold(p.key[:len(name)], []byte(name)):
+			if v, valid := extValue(p.value); valid {
+				return v, true
+			}
+		case !ok && bytes.EqualFold(p.key, []byte(name)):
+			plain, ok = p.value, true
+		}
+	}
+	return plain, ok
+}
+
+var dispositionInline = []byte("inline")
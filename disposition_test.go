@@ -0,0 +1,117 @@
+package httphead
+
+import "testing"
+
+func TestParseContentDisposition(t *testing.T) {
+	for _, test := range []struct {
+		in         string
+		typ        string
+		attachment bool
+		filename   string
+		noFilename bool
+		ok         bool
+	}{
+		{
+			in:         `inline`,
+			typ:        "inline",
+			noFilename: true,
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename="foo.html"`,
+			typ:        "attachment",
+			attachment: true,
+			filename:   "foo.html",
+			ok:         true,
+		},
+		{
+			in:         `Attachment; FILENAME=foo.html`,
+			typ:        "Attachment",
+			attachment: true,
+			filename:   "foo.html",
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename="a\\b.txt"`,
+			typ:        "attachment",
+			attachment: true,
+			filename:   `a\b.txt`,
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename="C:\\dir\\"`,
+			typ:        "attachment",
+			attachment: true,
+			filename:   `C:\dir\`,
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename="say \"hi\".txt"`,
+			typ:        "attachment",
+			attachment: true,
+			filename:   `say "hi".txt`,
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename*=UTF-8''%e2%82%ac%20rates`,
+			typ:        "attachment",
+			attachment: true,
+			filename:   "€ rates",
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename*=iso-8859-1'en'%A3%20rates`,
+			typ:        "attachment",
+			attachment: true,
+			filename:   "£ rates",
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename="EURO rates"; filename*=utf-8''%e2%82%ac%20rates`,
+			typ:        "attachment",
+			attachment: true,
+			filename:   "€ rates",
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename*=koi8-r''%c5; filename="fallback"`,
+			typ:        "attachment",
+			attachment: true,
+			filename:   "fallback",
+			ok:         true,
+		},
+		{
+			in:         `attachment; filename*=UTF-8''%zz`,
+			typ:        "attachment",
+			attachment: true,
+			noFilename: true,
+			ok:         true,
+		},
+		{in: ``},
+		{in: `attachment; filename`},
+		{in: `attachment; filename="a", inline`},
+		{in: `attachment; filename="a`},
+	} {
+		d, ok := ParseContentDisposition([]byte(test.in))
+		if ok != test.ok {
+			t.Errorf("ParseContentDisposition(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if string(d.Type) != test.typ {
+			t.Errorf("ParseContentDisposition(%q) type is %q; want %q", test.in, d.Type, test.typ)
+		}
+		if act := d.IsAttachment(); act != test.attachment {
+			t.Errorf("ParseContentDisposition(%q).IsAttachment() = %v; want %v", test.in, act, test.attachment)
+		}
+		filename, has := d.Filename()
+		if has != !test.noFilename || string(filename) != test.filename {
+			t.Errorf(
+				"ParseContentDisposition(%q).Filename() = %q, %v; want %q, %v",
+				test.in, filename, has, test.filename, !test.noFilename,
+			)
+		}
+	}
+}
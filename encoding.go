@@ -0,0 +1,101 @@
+package httphead
+
+import "bytes"
+
+// AcceptEncoding represents single item of Accept-Encoding header value.
+type AcceptEncoding struct {
+	// Coding is the content coding, such as "gzip", "identity" or "*".
+	Coding []byte
+
+	// Quality is the weight of coding in thousandths (see ParseQuality()).
+	Quality uint16
+}
+
+// ParseAcceptEncoding parses Accept-Encoding header value and appends codings
+// to given slice in order of their appearance:
+//
+// Accept-Encoding = #( codings [ weight ] )
+// codings         = content-coding / "identity" / "*"
+//
+// Unlike other list headers, empty value is valid and means that no content
+// coding is acceptable except "identity". Note that appended codings are
+// subslices of data. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-12.5.3
+func ParseAcceptEncoding(data []byte, encs []AcceptEncoding) ([]AcceptEncoding, bool) {
+	if len(trim(data)) == 0 {
+		return encs, true
+	}
+	ok := scanWeighted(data, func(coding []byte, q uint16) bool {
+		encs = append(encs, AcceptEncoding{
+			Coding:  coding,
+			Quality: q,
+		})
+		return true
+	})
+	return encs, ok
+}
+
+// NegotiateEncoding selects the most preferred content coding of available
+// ones according to Accept-Encoding header value data. Codings are compared
+// case-insensitively. If multiple codings have the same quality, the first
+// one in available is selected.
+//
+// It follows RFC9110 rules: "*" matches any coding which is not listed
+// explicitly; "identity" coding is acceptable unless it is excluded by
+// "identity;q=0" or "*;q=0", but it is the least preferred one if not listed.
+// Note that "identity" is returned if it is acceptable and no coding from
+// available is acceptable, even if available does not contain it.
+//
+// It returns false if data is malformed or no coding is acceptable. Note that
+// absence of Accept-Encoding header means that any coding is acceptable and
+// must be handled by caller.
+// See https://tools.ietf.org/html/rfc9110#section-12.5.3
+func NegotiateEncoding(data []byte, available []string) (string, bool) {
+	encs, ok := ParseAcceptEncoding(data, nil)
+	if !ok {
+		return "", false
+	}
+	var (
+		best  = -1
+		bestQ uint16
+	)
+	for i, a := range available {
+		if q := encodingQuality(encs, []byte(a)); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best != -1 {
+		return available[best], true
+	}
+	if encodingQuality(encs, codingIdentity) > 0 {
+		return string(codingIdentity), true
+	}
+	return "", false
+}
+
+var codingIdentity = []byte("identity")
+
+// encodingQuality returns quality of coding according to encs.
+func encodingQuality(encs []AcceptEncoding, coding []byte) uint16 {
+	var (
+		q        uint16
+		wildcard = -1
+	)
+	for _, e := range encs {
+		switch {
+		case bytes.EqualFold(e.Coding, coding):
+			return e.Quality
+		case isWildcard(e.Coding) && wildcard == -1:
+			wildcard = int(e.Quality)
+		}
+	}
+	switch {
+	case wildcard != -1:
+		q = uint16(wildcard)
+	case bytes.EqualFold(coding, codingIdentity):
+		// Identity is acceptable unless excluded explicitly, but with the
+		// lowest preference.
+		q = 1
+	}
+	return q
+}
@@ -0,0 +1,75 @@
+package httphead
+
+import "testing"
+
+func TestParseAcceptEncoding(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []AcceptEncoding
+		ok  bool
+	}{
+		{
+			in: `gzip;q=1.0, identity; q=0.5, *;q=0`,
+			exp: []AcceptEncoding{
+				{[]byte("gzip"), 1000},
+				{[]byte("identity"), 500},
+				{[]byte("*"), 0},
+			},
+			ok: true,
+		},
+		{
+			in: ``,
+			ok: true,
+		},
+		{
+			in: `gzip;q=2`,
+			ok: false,
+		},
+	} {
+		act, ok := ParseAcceptEncoding([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseAcceptEncoding(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if len(act) != len(test.exp) {
+			t.Errorf("ParseAcceptEncoding(%q) = %q; want %q", test.in, act, test.exp)
+			continue
+		}
+		for i := range act {
+			if string(act[i].Coding) != string(test.exp[i].Coding) || act[i].Quality != test.exp[i].Quality {
+				t.Errorf("ParseAcceptEncoding(%q) = %q; want %q", test.in, act, test.exp)
+				break
+			}
+		}
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for _, test := range []struct {
+		in        string
+		available []string
+		exp       string
+		ok        bool
+	}{
+		{`gzip, br`, []string{"br", "gzip"}, "br", true},
+		{`gzip;q=0.5, br`, []string{"gzip", "br"}, "br", true},
+		{`GZIP`, []string{"gzip"}, "gzip", true},
+		{`compress`, []string{"gzip", "br"}, "identity", true},
+		{`compress`, []string{"gzip", "identity"}, "identity", true},
+		{``, []string{"gzip"}, "identity", true},
+		{`*`, []string{"gzip", "br"}, "gzip", true},
+		{`br;q=0, *`, []string{"br", "gzip"}, "gzip", true},
+		{`*;q=0`, []string{"gzip"}, "", false},
+		{`identity;q=0`, []string{"gzip"}, "", false},
+		{`*;q=0, identity`, []string{"gzip"}, "identity", true},
+		{`gzip;q=x`, []string{"gzip"}, "", false},
+	} {
+		act, ok := NegotiateEncoding([]byte(test.in), test.available)
+		if act != test.exp || ok != test.ok {
+			t.Errorf("NegotiateEncoding(%q, %q) = %q, %v; want %q, %v", test.in, test.available, act, ok, test.exp, test.ok)
+		}
+	}
+}
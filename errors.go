@@ -0,0 +1,85 @@
+package httphead
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	// ErrMalformed is returned when scanned data does not match the expected
+	// grammar.
+	ErrMalformed = errors.New("httphead: malformed header value")
+
+	// ErrTruncated is returned when scanned data ended in the middle of some
+	// construction, such as quoted-string or comment. Unlike ErrMalformed,
+	// it means that data could be valid if more bytes were available.
+	ErrTruncated = errors.New("httphead: unexpected end of header value")
+
+	// ErrHeaderInjection is returned when scanned data contains control
+	// characters which are not allowed in header field values. Such
+	// characters (bare CR or LF especially) could be used to inject header
+	// fields.
+	ErrHeaderInjection = errors.New("httphead: control character in header value")
+
+	// ErrInvalidUTF8 is returned when scanned data is expected to be a valid
+	// UTF-8 text, but it is not.
+	ErrInvalidUTF8 = errors.New("httphead: invalid utf-8 sequence")
+
+	// ErrLimitExceeded is returned when scanned data exceeds configured
+	// limits, such as MaxValueLength.
+	ErrLimitExceeded = errors.New("httphead: limit exceeded")
+
+	// ErrCardinality is returned when number of list elements is out of
+	// configured range. See ListScanner for details.
+	ErrCardinality = errors.New("httphead: unexpected number of list elements")
+
+	// ErrContentLengthMismatch is returned when Content-Length header
+	// contains different values. Such messages could be an attempt of request
+	// smuggling and must be rejected.
+	// See https://tools.ietf.org/html/rfc9110#section-8.6
+	ErrContentLengthMismatch = errors.New("httphead: conflicting content-length values")
+
+	// ErrControl is returned when scanning callback returns unknown Control
+	// value.
+	ErrControl = errors.New("httphead: unexpected control value")
+
+	// ErrQuality is returned when quality value is out of 0..1 range or has
+	// more than three digits after the decimal point.
+	ErrQuality = errors.New("httphead: malformed quality value")
+)
+
+// errDuplicate is returned by parseOptions() when data contains duplicate
+// options and ParseRejectDuplicates flag is set.
+var errDuplicate = errors.New("httphead: duplicate option")
+
+// SyntaxError describes an error at some position of the scanned data.
+type SyntaxError struct {
+	// Offset is the index of the byte in scanned data where error occurred.
+	Offset int
+
+	// Byte is the offending byte at Offset. It is zero if error occurred at
+	// the end of the scanned data.
+	Byte byte
+
+	// Err is the reason of error, such as ErrHeaderInjection.
+	Err error
+
+	// Expected describes input which would be legal at Offset, such as
+	// "token or '\"' after '='". It is empty if there is no such input or it
+	// is not known.
+	Expected string
+}
+
+// Error implements error interface.
+func (e *SyntaxError) Error() string {
+	s := e.Err.Error() + " at offset " + strconv.Itoa(e.Offset)
+	if e.Expected != "" {
+		s += ": expected " + e.Expected
+	}
+	return s
+}
+
+// Unwrap returns the reason of error.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
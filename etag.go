@@ -0,0 +1,136 @@
+package httphead
+
+import "bytes"
+
+// ETag represents RFC9110 entity-tag.
+// See https://tools.ietf.org/html/rfc9110#section-8.8.3
+type ETag struct {
+	// Tag contains opaque-tag without surrounding double quotes.
+	Tag []byte
+
+	// Weak reports whether entity-tag has weakness indicator "W/".
+	Weak bool
+}
+
+// StrongMatch reports whether e and b match using strong comparison, that
+// is, both entity-tags are not weak and their opaque-tags are equal.
+// See https://tools.ietf.org/html/rfc9110#section-8.8.3.2
+func (e ETag) StrongMatch(b ETag) bool {
+	return !e.Weak && !b.Weak && e.WeakMatch(b)
+}
+
+// WeakMatch reports whether e and b match using weak comparison, that is,
+// their opaque-tags are equal regardless of weakness indicators.
+// See https://tools.ietf.org/html/rfc9110#section-8.8.3.2
+func (e ETag) WeakMatch(b ETag) bool {
+	return e.Tag != nil && b.Tag != nil && bytes.Equal(e.Tag, b.Tag)
+}
+
+// ParseETag parses entity-tag from data:
+//
+// entity-tag = [ weak ] opaque-tag
+// weak       = %s"W/"
+// opaque-tag = DQUOTE *etagc DQUOTE
+// etagc      = %x21 / %x23-7E / obs-text
+//
+// Surrounding whitespace is ignored. Note that returned tag is a subslice of
+// data. It returns false if data is malformed.
+func ParseETag(data []byte) (etag ETag, ok bool) {
+	data = trim(data)
+	etag, n := scanETag(data)
+	if n == -1 || n != len(data) {
+		return ETag{}, false
+	}
+	return etag, true
+}
+
+// ScanETagList scans list of entity-tags, such as value of If-Match or
+// If-None-Match header:
+//
+// If-Match = "*" / #entity-tag
+//
+// It calls it for every scanned entity-tag. If it returns false, scanning
+// stops. If data is "*", it is not called and returned wildcard is true.
+//
+// Note that tags are subslices of data. It returns false if data is
+// malformed.
+// See https://tools.ietf.org/html/rfc9110#section-13.1.1
+func ScanETagList(data []byte, it func(ETag) bool) (wildcard, ok bool) {
+	if exceedsLimit(data) {
+		return false, false
+	}
+	data = trim(data)
+	if len(data) == 1 && data[0] == '*' {
+		return true, true
+	}
+	for len(data) > 0 {
+		if data[0] == ',' || OctetTypes[data[0]].IsSpace() {
+			data = data[1:]
+			continue
+		}
+		etag, n := scanETag(data)
+		if n == -1 {
+			return false, false
+		}
+		data = data[n:]
+		data = data[SkipSpace(data):]
+		if len(data) > 0 && data[0] != ',' {
+			return false, false
+		}
+		if !it(etag) {
+			break
+		}
+	}
+	return false, true
+}
+
+// MatchETagList reports whether etag matches some of entity-tags listed in
+// data, which is a value of If-Match or If-None-Match header. If weak is
+// true, weak comparison is used, as If-None-Match requires. Otherwise strong
+// comparison is used, as If-Match requires. Note that "*" matches any etag.
+//
+// It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-13.1
+func MatchETagList(data []byte, etag ETag, weak bool) (match, ok bool) {
+	wildcard, ok := ScanETagList(data, func(e ETag) bool {
+		if weak {
+			match = e.WeakMatch(etag)
+		} else {
+			match = e.StrongMatch(etag)
+		}
+		return !match
+	})
+	if !ok {
+		return false, false
+	}
+	return wildcard || match, true
+}
+
+// scanETag scans entity-tag at the beginning of p. It returns number of bytes
+// scanned or -1 if p does not start with valid entity-tag.
+func scanETag(p []byte) (etag ETag, n int) {
+	if bytes.HasPrefix(p, weakPrefix) {
+		etag.Weak = true
+		n = len(weakPrefix)
+	}
+	if n == len(p) || p[n] != '"' {
+		return ETag{}, -1
+	}
+	start := n + 1
+	for n = start; n < len(p) && p[n] != '"'; n++ {
+		if !isETagChar(p[n]) {
+			return ETag{}, -1
+		}
+	}
+	if n == len(p) {
+		return ETag{}, -1
+	}
+	etag.Tag = p[start:n]
+	return etag, n + 1
+}
+
+var weakPrefix = []byte("W/")
+
+func isETagChar(c byte) bool {
+	return c == 0x21 || 0x23 <= c && c != 0x7f
+}
@@ -0,0 +1,123 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseETag(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		tag  string
+		weak bool
+		ok   bool
+	}{
+		{`"xyzzy"`, "xyzzy", false, true},
+		{`W/"xyzzy"`, "xyzzy", true, true},
+		{` ""`, "", false, true},
+		{"\"\xe2\x82\xac\"", "\xe2\x82\xac", false, true},
+		{`xyzzy`, "", false, false},
+		{`w/"xyzzy"`, "", false, false},
+		{`"xy"zy"`, "", false, false},
+		{`"xyzzy`, "", false, false},
+		{`W/`, "", false, false},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			etag, ok := ParseETag([]byte(test.in))
+			if ok != test.ok {
+				t.Fatalf("ParseETag(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			}
+			if string(etag.Tag) != test.tag || etag.Weak != test.weak {
+				t.Errorf("ParseETag(%q) = %q, %v; want %q, %v", test.in, etag.Tag, etag.Weak, test.tag, test.weak)
+			}
+		})
+	}
+}
+
+func TestETagCompare(t *testing.T) {
+	for _, test := range []struct {
+		a, b   string
+		strong bool
+		weak   bool
+	}{
+		{`W/"1"`, `W/"1"`, false, true},
+		{`W/"1"`, `W/"2"`, false, false},
+		{`W/"1"`, `"1"`, false, true},
+		{`"1"`, `"1"`, true, true},
+		{`"1"`, `"2"`, false, false},
+	} {
+		a, _ := ParseETag([]byte(test.a))
+		b, _ := ParseETag([]byte(test.b))
+		if act := a.StrongMatch(b); act != test.strong {
+			t.Errorf("%s.StrongMatch(%s) = %v; want %v", test.a, test.b, act, test.strong)
+		}
+		if act := a.WeakMatch(b); act != test.weak {
+			t.Errorf("%s.WeakMatch(%s) = %v; want %v", test.a, test.b, act, test.weak)
+		}
+	}
+	if (ETag{}).WeakMatch(ETag{}) {
+		t.Errorf("zero ETag matches zero ETag")
+	}
+}
+
+func TestScanETagList(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		any bool
+		ok  bool
+	}{
+		{in: ` * `, any: true, ok: true},
+		{in: `"xyzzy"`, exp: []string{`"xyzzy"`}, ok: true},
+		{
+			in:  `"xyzzy", W/"r2d2xxxx",, "c3piozzzz",""`,
+			exp: []string{`"xyzzy"`, `W/"r2d2xxxx"`, `"c3piozzzz"`, `""`},
+			ok:  true,
+		},
+		{in: `"a,b", "c"`, exp: []string{`"a,b"`, `"c"`}, ok: true},
+		{in: ``, ok: true},
+		{in: `*, "a"`},
+		{in: `"a" "b"`},
+		{in: `"a`},
+		{in: `a`},
+	} {
+		var act []string
+		wildcard, ok := ScanETagList([]byte(test.in), func(e ETag) bool {
+			s := `"` + string(e.Tag) + `"`
+			if e.Weak {
+				s = "W/" + s
+			}
+			act = append(act, s)
+			return true
+		})
+		if ok != test.ok || wildcard != test.any {
+			t.Errorf("ScanETagList(%q) = %v, %v; want %v, %v", test.in, wildcard, ok, test.any, test.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ScanETagList(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestMatchETagList(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		etag  string
+		weak  bool
+		match bool
+	}{
+		{`*`, `"a"`, false, true},
+		{`"a", "b"`, `"b"`, false, true},
+		{`W/"a", "b"`, `"a"`, false, false},
+		{`W/"a", "b"`, `"a"`, true, true},
+		{`"a", "b"`, `W/"b"`, true, true},
+		{`"a", "b"`, `"c"`, true, false},
+	} {
+		etag, _ := ParseETag([]byte(test.etag))
+		match, ok := MatchETagList([]byte(test.in), etag, test.weak)
+		if !ok || match != test.match {
+			t.Errorf("MatchETagList(%q, %s, %v) = %v, %v; want %v, true", test.in, test.etag, test.weak, match, ok, test.match)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package httphead
+
+import "bytes"
+
+// ExpectCT represents Expect-CT header value.
+// See https://tools.ietf.org/html/rfc9163#section-2.1
+type ExpectCT struct {
+	// MaxAge is the number of seconds the host should be regarded as a known
+	// Expect-CT host.
+	MaxAge int64
+
+	// Enforce reports whether user agent should refuse connections which
+	// violate Certificate Transparency policy.
+	Enforce bool
+
+	// ReportURI is the URI to report failures to. It is nil if not present.
+	ReportURI []byte
+}
+
+// ParseExpectCT parses Expect-CT header value:
+//
+// Expect-CT           = #expect-ct-directive
+// expect-ct-directive = directive-name [ "=" directive-value ]
+// directive-name      = token
+// directive-value     = token / quoted-string
+//
+// Directive names are case-insensitive. The max-age directive is required.
+// Unknown directives are ignored. Note that returned ReportURI is a subslice
+// of data, unless it contains escaped characters.
+//
+// It returns false if data is malformed, max-age is missing or some known
+// directive appears more than once.
+// See https://tools.ietf.org/html/rfc9163#section-2.1
+func ParseExpectCT(data []byte) (ct ExpectCT, ok bool) {
+	const (
+		hasMaxAge = 1 << iota
+		hasEnforce
+		hasReportURI
+	)
+	var (
+		has   int
+		valid = true
+	)
+	ok = ScanDirectives(data, func(name, value []byte) Control {
+		var bit int
+		switch {
+		case bytes.EqualFold(name, directiveMaxAge):
+			bit = hasMaxAge
+			ct.MaxAge, valid = ParseDeltaSeconds(value)
+		case bytes.EqualFold(name, directiveEnforce):
+			bit = hasEnforce
+			ct.Enforce, valid = true, value == nil
+		case bytes.EqualFold(name, directiveReportURI):
+			bit = hasReportURI
+			ct.ReportURI, valid = value, len(value) > 0
+		default:
+			return ControlContinue
+		}
+		if has&bit != 0 {
+			valid = false
+		}
+		if !valid {
+			return ControlBreak
+		}
+		has |= bit
+		return ControlContinue
+	})
+	if !ok || !valid || has&hasMaxAge == 0 {
+		return ExpectCT{}, false
+	}
+	return ct, true
+}
+
+var (
+	directiveMaxAge    = []byte("max-age")
+	directiveEnforce   = []byte("enforce")
+	directiveReportURI = []byte("report-uri")
+)
@@ -0,0 +1,42 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExpectCT(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp ExpectCT
+		ok  bool
+	}{
+		{
+			in:  `max-age=86400, enforce, report-uri="https://example.com/report"`,
+			exp: ExpectCT{MaxAge: 86400, Enforce: true, ReportURI: []byte("https://example.com/report")},
+			ok:  true,
+		},
+		{
+			in:  `Max-Age=0`,
+			exp: ExpectCT{},
+			ok:  true,
+		},
+		{
+			in:  `max-age=60, foo=bar`,
+			exp: ExpectCT{MaxAge: 60},
+			ok:  true,
+		},
+		{in: `enforce`},
+		{in: `max-age=abc`},
+		{in: `max-age=1, max-age=2`},
+		{in: `max-age=1, enforce=1`},
+		{in: `max-age=1, report-uri=""`},
+		{in: `max-age=1, report-uri="x`},
+		{in: ``},
+	} {
+		act, ok := ParseExpectCT([]byte(test.in))
+		if ok != test.ok || !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseExpectCT(%q) = %+v, %v; want %+v, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package httphead
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// ParseExtValue splits RFC8187 ext-value into its charset, language and
+// percent-encoded value parts without decoding:
+//
+// ext-value   = charset  "'" [ language ] "'" value-chars
+// charset     = "UTF-8" / "ISO-8859-1" / mime-charset
+// value-chars = *( pct-encoded / attr-char )
+//
+// It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc8187#section-3.2
+func ParseExtValue(data []byte) (charset, lang, value []byte, ok bool) {
+	i := bytes.IndexByte(data, '\'')
+	if i <= 0 {
+		return nil, nil, nil, false
+	}
+	j := bytes.IndexByte(data[i+1:], '\'')
+	if j == -1 {
+		return nil, nil, nil, false
+	}
+	j += i + 1
+
+	charset = data[:i]
+	lang = data[i+1 : j]
+	value = data[j+1:]
+
+	for _, c := range charset {
+		if !OctetTypes[c].IsToken() || c == '\'' || c == '%' || c == '*' {
+			return nil, nil, nil, false
+		}
+	}
+	for _, c := range lang {
+		if !isAlphaNum(c) && c != '-' {
+			return nil, nil, nil, false
+		}
+	}
+	for k := 0; k < len(value); k++ {
+		switch c := value[k]; {
+		case c == '%':
+			if k+2 >= len(value) {
+				return nil, nil, nil, false
+			}
+			if unhex(value[k+1]) < 0 || unhex(value[k+2]) < 0 {
+				return nil, nil, nil, false
+			}
+			k += 2
+		case !isAttrChar(c):
+			return nil, nil, nil, false
+		}
+	}
+
+	return charset, lang, value, true
+}
+
+// DecodeExtValue decodes RFC8187 ext-value and appends resulting UTF-8 bytes
+// to dst. Only "UTF-8" and "ISO-8859-1" charsets are supported.
+//
+// It returns false if data is malformed or its charset is not supported.
+func DecodeExtValue(dst, data []byte) ([]byte, bool) {
+	charset, _, value, ok := ParseExtValue(data)
+	if !ok {
+		return dst, false
+	}
+	return decodeExtValue(dst, charset, value)
+}
+
+// AppendExtValue appends RFC8187 ext-value to dst, percent-encoding bytes of
+// value which are not attr-char. That is, for "UTF-8" charset, empty language
+// and "€ rates" value it appends "UTF-8''%E2%82%AC%20rates".
+//
+// Note that value is not converted to the given charset, that is, it must be
+// already encoded in it. Charset and language are appended as is.
+// See https://tools.ietf.org/html/rfc8187#section-3.2
+func AppendExtValue(dst []byte, charset, lang string, value []byte) []byte {
+	w := writer{buf: dst}
+	writeExtValue(&w, charset, lang, value)
+	return w.buf
+}
+
+func writeExtValue(w *writer, charset, lang string, value []byte) {
+	const hex = "0123456789ABCDEF"
+	w.writeString(charset)
+	w.write(apostrophe)
+	w.writeString(lang)
+	w.write(apostrophe)
+	var pos int
+	for i, c := range value {
+		if isAttrChar(c) {
+			continue
+		}
+		w.write(value[pos:i])
+		w.write([]byte{'%', hex[c>>4], hex[c&0xf]})
+		pos = i + 1
+	}
+	w.write(value[pos:])
+}
+
+var apostrophe = []byte{'\''}
+
+// extValue is like DecodeExtValue() but avoids allocation when value does not
+// contain percent-encoded bytes.
+func extValue(data []byte) ([]byte, bool) {
+	charset, _, value, ok := ParseExtValue(data)
+	if !ok {
+		return nil, false
+	}
+	if bytes.IndexByte(value, '%') == -1 && isCharsetUTF8(charset) {
+		return value, true
+	}
+	return decodeExtValue(nil, charset, value)
+}
+
+func decodeExtValue(dst, charset, value []byte) ([]byte, bool) {
+	var latin1 bool
+	switch {
+	case isCharsetUTF8(charset):
+	case bytes.EqualFold(charset, charsetLatin1):
+		latin1 = true
+	default:
+		return dst, false
+	}
+	n := len(dst)
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '%' {
+			c = byte(unhex(value[i+1])<<4 | unhex(value[i+2]))
+			i += 2
+		}
+		if latin1 && c >= utf8.RuneSelf {
+			dst = append(dst, 0xc0|c>>6, 0x80|c&0x3f)
+		} else {
+			dst = append(dst, c)
+		}
+	}
+	if !latin1 && !utf8.Valid(dst[n:]) {
+		return dst[:n], false
+	}
+	return dst, true
+}
+
+// percentDecode appends to dst percent-decoded p. It returns false if p
+// contains malformed percent-encoded bytes.
+func percentDecode(dst, p []byte) ([]byte, bool) {
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if c == '%' {
+			if i+2 >= len(p) {
+				return dst, false
+			}
+			a, b := unhex(p[i+1]), unhex(p[i+2])
+			if a < 0 || b < 0 {
+				return dst, false
+			}
+			c = byte(a<<4 | b)
+			i += 2
+		}
+		dst = append(dst, c)
+	}
+	return dst, true
+}
+
+var (
+	charsetUTF8   = []byte("UTF-8")
+	charsetLatin1 = []byte("ISO-8859-1")
+)
+
+func isCharsetUTF8(charset []byte) bool {
+	return bytes.EqualFold(charset, charsetUTF8)
+}
+
+// isExtName reports whether given parameter name is RFC8187 extended
+// parameter name, that is, it ends with "*".
+func isExtName(name []byte) bool {
+	return len(name) > 1 && name[len(name)-1] == '*'
+}
+
+// isAttrChar reports whether c is RFC8187 attr-char:
+//
+// attr-char = ALPHA / DIGIT / "!" / "#" / "$" / "&" / "+" / "-" / "." / "^" / "_" / "`" / "|" / "~"
+func isAttrChar(c byte) bool {
+	switch c {
+	case '!', '#', '$', '&', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+	return isAlphaNum(c)
+}
+
+func isAlphaNum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func unhex(c byte) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'a' <= c && c <= 'f':
+		return int(c-'a') + 10
+	case 'A' <= c && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return -1
+}
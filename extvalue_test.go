@@ -0,0 +1,142 @@
+package httphead
+
+import (
+	"bytes"
+	"testing"
+)
+
+var extValueCases = []struct {
+	label string
+	in    []byte
+	out   []byte
+	ok    bool
+}{
+	{
+		label: "utf8",
+		in:    []byte(`UTF-8''%e2%82%ac%20rates`),
+		out:   []byte("€ rates"),
+		ok:    true,
+	},
+	{
+		label: "utf8_lang",
+		in:    []byte(`utf-8'en'plain.txt`),
+		out:   []byte("plain.txt"),
+		ok:    true,
+	},
+	{
+		label: "latin1",
+		in:    []byte(`iso-8859-1'en'%A3%20rates`),
+		out:   []byte("£ rates"),
+		ok:    true,
+	},
+	{
+		label: "no_charset",
+		in:    []byte(`''foo`),
+	},
+	{
+		label: "no_lang_delimiter",
+		in:    []byte(`UTF-8'foo`),
+	},
+	{
+		label: "bad_pct",
+		in:    []byte(`UTF-8''%e2%8`),
+	},
+	{
+		label: "bad_char",
+		in:    []byte(`UTF-8''foo bar`),
+	},
+	{
+		label: "bad_utf8",
+		in:    []byte(`UTF-8''%ff`),
+	},
+	{
+		label: "unknown_charset",
+		in:    []byte(`KOI8-R''%c1`),
+	},
+}
+
+func TestDecodeExtValue(t *testing.T) {
+	for _, test := range extValueCases {
+		t.Run(test.label, func(t *testing.T) {
+			act, ok := DecodeExtValue(nil, test.in)
+			if ok != test.ok {
+				t.Fatalf("DecodeExtValue(%q) ok is %v; want %v", test.in, ok, test.ok)
+			}
+			if !bytes.Equal(act, test.out) {
+				t.Errorf("DecodeExtValue(%q) = %q; want %q", test.in, act, test.out)
+			}
+		})
+	}
+}
+
+func TestParametersGetExt(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{
+			in:  `attachment; filename="EURO rates"; filename*=utf-8''%e2%82%ac%20rates`,
+			exp: "€ rates",
+			ok:  true,
+		},
+		{
+			in:  `attachment; filename*=utf-8''%e2%82%ac%20rates; filename="EURO rates"`,
+			exp: "€ rates",
+			ok:  true,
+		},
+		{
+			in:  `attachment; filename*=utf-8''%e2%82; filename="EURO rates"`,
+			exp: "EURO rates",
+			ok:  true,
+		},
+		{
+			in: `attachment`,
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			opts, ok := ParseOptions([]byte(test.in), nil)
+			if !ok || len(opts) != 1 {
+				t.Fatalf("can not parse options from %q", test.in)
+			}
+			act, ok := opts[0].Parameters.GetExt("filename")
+			if ok != test.ok || string(act) != test.exp {
+				t.Errorf("GetExt() = %q, %v; want %q, %v", act, ok, test.exp, test.ok)
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeExtValue(b *testing.B) {
+	for _, bench := range extValueCases {
+		b.Run(bench.label, func(b *testing.B) {
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf, _ = DecodeExtValue(buf[:0], bench.in)
+			}
+		})
+	}
+}
+
+func TestAppendExtValue(t *testing.T) {
+	for _, test := range []struct {
+		charset string
+		lang    string
+		in      string
+		exp     string
+	}{
+		{"UTF-8", "", "€ rates", "UTF-8''%E2%82%AC%20rates"},
+		{"UTF-8", "en", "plain.txt", "UTF-8'en'plain.txt"},
+		{"UTF-8", "", `a'b%c"`, "UTF-8''a%27b%25c%22"},
+		{"ISO-8859-1", "", "\xa3", "ISO-8859-1''%A3"},
+	} {
+		act := AppendExtValue(nil, test.charset, test.lang, []byte(test.in))
+		if string(act) != test.exp {
+			t.Errorf("AppendExtValue(%q) = %q; want %q", test.in, act, test.exp)
+		}
+		dec, ok := DecodeExtValue(nil, act)
+		if exp := test.in; test.charset == "UTF-8" && (!ok || string(dec) != exp) {
+			t.Errorf("DecodeExtValue(%q) = %q, %v; want %q, true", act, dec, ok, exp)
+		}
+	}
+}
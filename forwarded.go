@@ -0,0 +1,218 @@
+package httphead
+
+import "bytes"
+
+// ScanXForwardedFor scans X-Forwarded-For header value using
+// DefaultForwardedForScanner.Scan() method.
+func ScanXForwardedFor(data []byte, it func(addr []byte) bool) bool {
+	return DefaultForwardedForScanner.Scan(data, it)
+}
+
+// DefaultForwardedForScanner is a ForwardedForScanner which is used by
+// ScanXForwardedFor().
+var DefaultForwardedForScanner = ForwardedForScanner{}
+
+// ForwardedForScanner contains options for scanning X-Forwarded-For header
+// value.
+type ForwardedForScanner struct {
+	// ValidateAddr causes scanner to treat data as malformed if some of its
+	// elements is not a valid node name. See ValidForwardedNode() for
+	// details.
+	ValidateAddr bool
+}
+
+// Scan scans comma separated list of addresses from data, calling it for
+// every non-empty element with surrounding whitespace trimmed. If it returns
+// false, scanning stops.
+//
+// Note that addresses are subslices of data. It returns false if data is
+// malformed.
+func (s ForwardedForScanner) Scan(data []byte, it func(addr []byte) bool) bool {
+	if exceedsLimit(data) {
+		return false
+	}
+	for len(data) > 0 {
+		addr := data
+		if i := bytes.IndexByte(data, ','); i != -1 {
+			addr, data = data[:i], data[i+1:]
+		} else {
+			data = nil
+		}
+		if addr = trim(addr); len(addr) == 0 {
+			continue
+		}
+		if s.ValidateAddr && !ValidForwardedNode(addr) {
+			return false
+		}
+		if !it(addr) {
+			break
+		}
+	}
+	return true
+}
+
+// LeftmostForwardedFor returns the left-most address of X-Forwarded-For
+// header value for which trusted returns false. That is, trusted reports
+// whether addr is a known proxy which must be skipped. If trusted is nil, the
+// left-most address is returned. Elements are validated in the same way as
+// ForwardedForScanner with ValidateAddr does.
+//
+// Note that the left-most address is set by the client and could be forged.
+// It returns false if data is malformed or there is no such address.
+func LeftmostForwardedFor(data []byte, trusted func(addr []byte) bool) (addr []byte, ok bool) {
+	s := ForwardedForScanner{ValidateAddr: true}
+	valid := s.Scan(data, func(v []byte) bool {
+		if trusted == nil || !trusted(v) {
+			addr = v
+			return false
+		}
+		return true
+	})
+	if !valid || addr == nil {
+		return nil, false
+	}
+	return addr, true
+}
+
+// RightmostForwardedFor returns the right-most address of X-Forwarded-For
+// header value for which trusted returns false, that is, the address of the
+// first untrusted hop when trusted proxies are skipped from the right. If
+// trusted is nil, the right-most address is returned. Elements are validated
+// in the same way as ForwardedForScanner with ValidateAddr does.
+//
+// It returns false if data is malformed or there is no such address.
+func RightmostForwardedFor(data []byte, trusted func(addr []byte) bool) (addr []byte, ok bool) {
+	s := ForwardedForScanner{ValidateAddr: true}
+	valid := s.Scan(data, func(v []byte) bool {
+		if trusted == nil || !trusted(v) {
+			addr = v
+		}
+		return true
+	})
+	if !valid || addr == nil {
+		return nil, false
+	}
+	return addr, true
+}
+
+// ValidForwardedNode reports whether p is a valid node name, as it is used in
+// X-Forwarded-For and Forwarded headers, without port:
+//
+// nodename = IPv4address / "[" IPv6address "]" / "unknown" / obfnode
+// obfnode  = "_" 1*( ALPHA / DIGIT / "." / "_" / "-")
+//
+// IPv6 addresses without brackets are also accepted, since X-Forwarded-For
+// usually contains them in that form.
+// See https://tools.ietf.org/html/rfc7239#section-6
+func ValidForwardedNode(p []byte) bool {
+	switch {
+	case len(p) == 0:
+		return false
+	case bytes.EqualFold(p, nodeUnknown):
+		return true
+	case p[0] == '_':
+		return validObfNode(p[1:])
+	case p[0] == '[' && p[len(p)-1] == ']':
+		return validIPv6(p[1 : len(p)-1])
+	case bytes.IndexByte(p, ':') != -1:
+		return validIPv6(p)
+	default:
+		return validIPv4(p)
+	}
+}
+
+var nodeUnknown = []byte("unknown")
+
+func validObfNode(p []byte) bool {
+	if len(p) == 0 {
+		return false
+	}
+	for _, c := range p {
+		if !isAlphaNum(c) && c != '.' && c != '_' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func validIPv4(p []byte) bool {
+	var (
+		parts  int
+		digits int
+		n      int
+	)
+	for i := 0; i <= len(p); i++ {
+		if i == len(p) || p[i] == '.' {
+			if digits == 0 || n > 255 {
+				return false
+			}
+			parts++
+			digits, n = 0, 0
+			continue
+		}
+		c := p[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		if digits > 0 && n == 0 {
+			// Leading zeros are ambiguous.
+			return false
+		}
+		if digits++; digits > 3 {
+			return false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return parts == 4
+}
+
+// validIPv6 reports whether p is a valid IPv6 address text representation,
+// that is, eight groups of 1 to 4 hex digits separated by ":", where the last
+// two groups could be an IPv4 address and one or more zero groups could be
+// replaced by "::". It does not allocate, unlike net.ParseIP().
+// See https://tools.ietf.org/html/rfc4291#section-2.2
+func validIPv6(p []byte) bool {
+	var (
+		groups   int
+		ellipsis bool
+	)
+	if len(p) >= 2 && p[0] == ':' && p[1] == ':' {
+		ellipsis = true
+		p = p[2:]
+	}
+	for len(p) > 0 {
+		n := 0
+		for n < len(p) && unhex(p[n]) != -1 {
+			n++
+		}
+		if n < len(p) && p[n] == '.' {
+			// Embedded IPv4 address must be the last part.
+			if !validIPv4(p) {
+				return false
+			}
+			groups += 2
+			break
+		}
+		if n == 0 || n > 4 {
+			return false
+		}
+		groups++
+		if p = p[n:]; len(p) == 0 {
+			break
+		}
+		if p[0] != ':' || len(p) == 1 {
+			return false
+		}
+		if p = p[1:]; p[0] == ':' {
+			if ellipsis {
+				return false
+			}
+			ellipsis = true
+			p = p[1:]
+		}
+	}
+	if ellipsis {
+		return groups <= 7
+	}
+	return groups == 8
+}
@@ -0,0 +1,140 @@
+package httphead
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanXForwardedFor(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		validate bool
+		exp      []string
+		ok       bool
+	}{
+		{
+			in:  `203.0.113.195, 70.41.3.18, 150.172.238.178`,
+			exp: []string{"203.0.113.195", "70.41.3.18", "150.172.238.178"},
+			ok:  true,
+		},
+		{
+			in:       `2001:db8:85a3:8d3:1319:8a2e:370:7348 ,, [::1], unknown, _hidden`,
+			validate: true,
+			exp:      []string{"2001:db8:85a3:8d3:1319:8a2e:370:7348", "[::1]", "unknown", "_hidden"},
+			ok:       true,
+		},
+		{
+			in:  `foo, bar`,
+			exp: []string{"foo", "bar"},
+			ok:  true,
+		},
+		{
+			in:       `203.0.113.195, foo`,
+			validate: true,
+			exp:      []string{"203.0.113.195"},
+		},
+		{in: `256.0.0.1`, validate: true},
+		{in: `1.2.3`, validate: true},
+		{in: `1.2.3.4.5`, validate: true},
+		{in: `01.2.3.4`, validate: true},
+		{in: `1.2.3.4:80`, validate: true},
+		{in: `::g`, validate: true},
+		{in: `_`, validate: true},
+		{in: `[1.2.3.4]`, validate: true},
+	} {
+		var act []string
+		s := ForwardedForScanner{ValidateAddr: test.validate}
+		ok := s.Scan([]byte(test.in), func(addr []byte) bool {
+			act = append(act, string(addr))
+			return true
+		})
+		if ok != test.ok {
+			t.Errorf("Scan(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+		}
+		if !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("Scan(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestForwardedForTrusted(t *testing.T) {
+	isProxy := func(addr []byte) bool {
+		return strings.HasPrefix(string(addr), "10.")
+	}
+	for _, test := range []struct {
+		in        string
+		trusted   func([]byte) bool
+		leftmost  string
+		rightmost string
+	}{
+		{
+			in:        `203.0.113.195, 70.41.3.18, 10.0.0.1`,
+			leftmost:  "203.0.113.195",
+			rightmost: "10.0.0.1",
+		},
+		{
+			in:        `10.0.0.2, 203.0.113.195, 70.41.3.18, 10.0.0.1`,
+			trusted:   isProxy,
+			leftmost:  "203.0.113.195",
+			rightmost: "70.41.3.18",
+		},
+		{
+			in:      `10.0.0.2, 10.0.0.1`,
+			trusted: isProxy,
+		},
+		{
+			in: `bogus, 203.0.113.195`,
+		},
+		{
+			in: ``,
+		},
+	} {
+		addr, ok := LeftmostForwardedFor([]byte(test.in), test.trusted)
+		if string(addr) != test.leftmost || ok != (test.leftmost != "") {
+			t.Errorf("LeftmostForwardedFor(%q) = %q, %v; want %q", test.in, addr, ok, test.leftmost)
+		}
+		addr, ok = RightmostForwardedFor([]byte(test.in), test.trusted)
+		if string(addr) != test.rightmost || ok != (test.rightmost != "") {
+			t.Errorf("RightmostForwardedFor(%q) = %q, %v; want %q", test.in, addr, ok, test.rightmost)
+		}
+	}
+}
+
+func TestValidIPv6(t *testing.T) {
+	for _, test := range []struct {
+		in string
+		ok bool
+	}{
+		{"::", true},
+		{"::1", true},
+		{"1::", true},
+		{"2001:db8::ff00:42:8329", true},
+		{"2001:db8:85a3:8d3:1319:8a2e:370:7348", true},
+		{"::ffff:192.0.2.128", true},
+		{"64:ff9b::192.0.2.33", true},
+		{"1:2:3:4:5:6:1.2.3.4", true},
+		{"", false},
+		{":", false},
+		{":::", false},
+		{"1:", false},
+		{":1", false},
+		{"1::2::3", false},
+		{"1:2:3:4:5:6:7", false},
+		{"1:2:3:4:5:6:7:8:9", false},
+		{"1:2:3:4::5:6:7:8", false},
+		{"12345::", false},
+		{"::1.2.3", false},
+		{"::1.2.3.4:1", false},
+		{"1:2:3:4:5:6:7:1.2.3.4", false},
+		{"fe80::1%eth0", false},
+	} {
+		if act := validIPv6([]byte(test.in)); act != test.ok {
+			t.Errorf("validIPv6(%q) = %v; want %v", test.in, act, test.ok)
+		}
+	}
+	p := []byte("2001:db8::ff00:42:8329")
+	if n := testing.AllocsPerRun(10, func() { validIPv6(p) }); n != 0 {
+		t.Errorf("validIPv6() allocates %v times; want 0", n)
+	}
+}
@@ -0,0 +1,142 @@
+package httphead
+
+import (
+	"bytes"
+
+	"github.com/gobwas/httphead/sfv"
+)
+
+// ParseEarlyData parses Early-Data header value:
+//
+// Early-Data = "1"
+//
+// It returns true if request was sent in TLS early data. It returns false ok
+// if data is malformed.
+// See https://tools.ietf.org/html/rfc8470#section-5.1
+func ParseEarlyData(data []byte) (early, ok bool) {
+	data = trim(data)
+	if len(data) == 1 && data[0] == '1' {
+		return true, true
+	}
+	return false, false
+}
+
+// ParseSaveData parses Save-Data client hint value. It accepts structured
+// field boolean ("?1" or "?0", parameters are ignored) as well as legacy "on"
+// token.
+//
+// It returns true if client prefers reduced data usage. It returns false ok
+// if data is malformed.
+// See https://wicg.github.io/savedata/#save-data-request-header-field
+func ParseSaveData(data []byte) (on, ok bool) {
+	data = trim(data)
+	if i := bytes.IndexByte(data, ';'); i != -1 {
+		data = data[:i]
+	}
+	switch {
+	case string(data) == "?1":
+		return true, true
+	case string(data) == "?0":
+		return false, true
+	case bytes.EqualFold(data, []byte("on")):
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// Brand represents single brand of Sec-CH-UA or Sec-CH-UA-Full-Version-List
+// header value.
+type Brand struct {
+	// Brand is the brand name, such as "Chromium".
+	Brand []byte
+
+	// Version is the brand version taken from "v" parameter. It is nil if
+	// parameter is not present.
+	Version []byte
+}
+
+// ParseSecCHUA parses Sec-CH-UA header value and appends brands to given
+// slice in order of their appearance. The value is a structured field list of
+// strings with "v" parameter:
+//
+// Sec-CH-UA: "Chromium";v="120", "Not?A_Brand";v="24"
+//
+// Note that appended brands consist of subslices of data, unless they contain
+// escaped characters. It returns false if data is malformed or some member is
+// not a string.
+// See https://wicg.github.io/ua-client-hints/#sec-ch-ua
+func ParseSecCHUA(data []byte, brands []Brand) ([]Brand, bool) {
+	list, ok := sfv.ParseList(data, nil)
+	if !ok {
+		return brands, false
+	}
+	n := len(brands)
+	for _, m := range list {
+		if m.IsInnerList || m.Type != sfv.TypeString {
+			return brands[:n], false
+		}
+		b := Brand{Brand: m.Bytes}
+		if v, has := m.Params.Get("v"); has {
+			if v.Type != sfv.TypeString {
+				return brands[:n], false
+			}
+			b.Version = v.Bytes
+		}
+		brands = append(brands, b)
+	}
+	return brands, true
+}
+
+// ParseSecCHUAMobile parses Sec-CH-UA-Mobile header value, which is a
+// structured field boolean, such as "?1". Parameters are ignored.
+//
+// It returns true if user agent prefers mobile experience. It returns false ok
+// if data is malformed.
+// See https://wicg.github.io/ua-client-hints/#sec-ch-ua-mobile
+func ParseSecCHUAMobile(data []byte) (mobile, ok bool) {
+	item, ok := sfv.ParseItem(data)
+	if !ok || item.Type != sfv.TypeBoolean {
+		return false, false
+	}
+	return item.Bool, true
+}
+
+// ParseSecCHUAPlatform parses Sec-CH-UA-Platform header value, which is a
+// structured field string, such as "Linux". It also could be used for other
+// string client hints, such as Sec-CH-UA-Platform-Version or Sec-CH-UA-Model.
+//
+// Note that returned platform is a subslice of data, unless it contains
+// escaped characters. It returns false if data is malformed.
+// See https://wicg.github.io/ua-client-hints/#sec-ch-ua-platform
+func ParseSecCHUAPlatform(data []byte) (platform []byte, ok bool) {
+	item, ok := sfv.ParseItem(data)
+	if !ok || item.Type != sfv.TypeString {
+		return nil, false
+	}
+	return item.Bytes, true
+}
+
+// ParseAcceptCH parses Accept-CH header value and appends client hint names
+// to given slice in order of their appearance. The value is a structured
+// field list of tokens:
+//
+// Accept-CH: Sec-CH-UA-Model, Sec-CH-UA-Platform-Version
+//
+// Parameters are ignored. Note that appended names are subslices of data. It
+// returns false if data is malformed or some member is not a token.
+// See https://tools.ietf.org/html/rfc8942#section-3.1
+func ParseAcceptCH(data []byte, hints [][]byte) ([][]byte, bool) {
+	list, ok := sfv.ParseList(data, nil)
+	if !ok {
+		return hints, false
+	}
+	n := len(hints)
+	for _, m := range list {
+		if m.IsInnerList || m.Type != sfv.TypeToken {
+			return hints[:n], false
+		}
+		hints = append(hints, m.Bytes)
+	}
+	return hints, true
+}
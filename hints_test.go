@@ -0,0 +1,131 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEarlyData(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		early bool
+		ok    bool
+	}{
+		{"1", true, true},
+		{" 1 ", true, true},
+		{"0", false, false},
+		{"1, 1", false, false},
+		{"", false, false},
+	} {
+		early, ok := ParseEarlyData([]byte(test.in))
+		if early != test.early || ok != test.ok {
+			t.Errorf("ParseEarlyData(%q) = %v, %v; want %v, %v", test.in, early, ok, test.early, test.ok)
+		}
+	}
+}
+
+func TestParseSaveData(t *testing.T) {
+	for _, test := range []struct {
+		in string
+		on bool
+		ok bool
+	}{
+		{"?1", true, true},
+		{"?0", false, true},
+		{"?1;a=1", true, true},
+		{"on", true, true},
+		{"On", true, true},
+		{"off", false, false},
+		{"1", false, false},
+		{"", false, false},
+	} {
+		on, ok := ParseSaveData([]byte(test.in))
+		if on != test.on || ok != test.ok {
+			t.Errorf("ParseSaveData(%q) = %v, %v; want %v, %v", test.in, on, ok, test.on, test.ok)
+		}
+	}
+}
+
+func TestParseSecCHUA(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []Brand
+		ok  bool
+	}{
+		{
+			in: `"Chromium";v="120", "Not?A_Brand";v="24", "Google Chrome"`,
+			exp: []Brand{
+				{Brand: []byte("Chromium"), Version: []byte("120")},
+				{Brand: []byte("Not?A_Brand"), Version: []byte("24")},
+				{Brand: []byte("Google Chrome")},
+			},
+			ok: true,
+		},
+		{in: ``, ok: true},
+		{in: `Chromium;v="120"`, ok: false},
+		{in: `"Chromium";v=120`, ok: false},
+		{in: `("Chromium")`, ok: false},
+		{in: `"Chromium`, ok: false},
+	} {
+		act, ok := ParseSecCHUA([]byte(test.in), nil)
+		if ok != test.ok || !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseSecCHUA(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseSecCHUAMobile(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		mobile bool
+		ok     bool
+	}{
+		{"?1", true, true},
+		{"?0", false, true},
+		{"?1;foo", true, true},
+		{"1", false, false},
+		{`"?1"`, false, false},
+		{"", false, false},
+	} {
+		mobile, ok := ParseSecCHUAMobile([]byte(test.in))
+		if mobile != test.mobile || ok != test.ok {
+			t.Errorf("ParseSecCHUAMobile(%q) = %v, %v; want %v, %v", test.in, mobile, ok, test.mobile, test.ok)
+		}
+	}
+}
+
+func TestParseSecCHUAPlatform(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{`"Linux"`, "Linux", true},
+		{`""`, "", true},
+		{`Linux`, "", false},
+		{`"Linux", "Windows"`, "", false},
+	} {
+		act, ok := ParseSecCHUAPlatform([]byte(test.in))
+		if string(act) != test.exp || ok != test.ok {
+			t.Errorf("ParseSecCHUAPlatform(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseAcceptCH(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{"Sec-CH-UA-Model, Sec-CH-UA-Platform-Version", []string{"Sec-CH-UA-Model", "Sec-CH-UA-Platform-Version"}, true},
+		{"", nil, true},
+		{`"Sec-CH-UA-Model"`, nil, false},
+		{"Sec-CH-UA-Model,", nil, false},
+	} {
+		act, ok := ParseAcceptCH([]byte(test.in), nil)
+		if ok != test.ok || !reflect.DeepEqual(stringsOf(act), test.exp) {
+			t.Errorf("ParseAcceptCH(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package httphead
+
+import "bytes"
+
+// ParseHost parses Host header value or authority of the request target:
+//
+// Host       = uri-host [ ":" port ]
+// uri-host   = IP-literal / IPv4address / reg-name
+// IP-literal = "[" ( IPv6address / IPvFuture ) "]"
+// reg-name   = *( unreserved / pct-encoded / sub-delims )
+// port       = *DIGIT
+//
+// Returned host is a subslice of p without brackets of IP-literal, such that
+// it could be compared against host names and addresses. Port is nil if it is
+// not present and empty if p ends with ":". Note that empty host is valid, as
+// it is used for targets without authority. Host names are not converted to
+// lower case.
+//
+// It returns false if p is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-7.2
+func ParseHost(p []byte) (host, port []byte, ok bool) {
+	rest := p
+	if len(p) > 0 && p[0] == '[' {
+		i := bytes.IndexByte(p, ']')
+		if i == -1 {
+			return nil, nil, false
+		}
+		host, rest = p[1:i], p[i+1:]
+		if !validIPv6(host) && !validIPvFuture(host) {
+			return nil, nil, false
+		}
+	} else {
+		i := bytes.IndexByte(p, ':')
+		if i == -1 {
+			i = len(p)
+		}
+		host, rest = p[:i], p[i:]
+		if !validRegName(host) {
+			return nil, nil, false
+		}
+	}
+	if len(rest) == 0 {
+		return host, nil, true
+	}
+	if rest[0] != ':' {
+		return nil, nil, false
+	}
+	port = rest[1:]
+	for _, c := range port {
+		if !isDigit(c) {
+			return nil, nil, false
+		}
+	}
+	return host, port, true
+}
+
+// validIPvFuture reports whether p is a valid IPvFuture:
+//
+// IPvFuture = "v" 1*HEXDIG "." 1*( unreserved / sub-delims / ":" )
+//
+// See https://tools.ietf.org/html/rfc3986#section-3.2.2
+func validIPvFuture(p []byte) bool {
+	if len(p) == 0 || p[0]|toLower != 'v' {
+		return false
+	}
+	p = p[1:]
+	i := bytes.IndexByte(p, '.')
+	if i <= 0 || i == len(p)-1 {
+		return false
+	}
+	for _, c := range p[:i] {
+		if unhex(c) == -1 {
+			return false
+		}
+	}
+	for _, c := range p[i+1:] {
+		if !isUnreserved(c) && !isSubDelim(c) && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// validRegName reports whether p is a valid reg-name. Note that IPv4address
+// is a valid reg-name as well.
+func validRegName(p []byte) bool {
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case isUnreserved(c) || isSubDelim(c):
+		case c == '%' && i+2 < len(p) && unhex(p[i+1]) != -1 && unhex(p[i+2]) != -1:
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isUnreserved(c byte) bool {
+	return isAlphaNum(c) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isSubDelim(c byte) bool {
+	switch c {
+	case '!', '$', '&', '\'', '(', ')', '*', '+', ',', ';', '=':
+		return true
+	}
+	return false
+}
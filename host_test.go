@@ -0,0 +1,48 @@
+package httphead
+
+import "testing"
+
+func TestParseHost(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		host string
+		port string
+		ok   bool
+	}{
+		{"example.com", "example.com", "", true},
+		{"Example.COM:8080", "Example.COM", "8080", true},
+		{"127.0.0.1:80", "127.0.0.1", "80", true},
+		{"[::1]", "::1", "", true},
+		{"[2001:db8::1]:443", "2001:db8::1", "443", true},
+		{"[v1.fe80::a+en1]", "v1.fe80::a+en1", "", true},
+		{"ex%41mple.com", "ex%41mple.com", "", true},
+		{"example.com:", "example.com", "", true},
+		{"", "", "", true},
+
+		{"example.com:80:80", "", "", false},
+		{"example.com:http", "", "", false},
+		{"exa mple.com", "", "", false},
+		{"user@example.com", "", "", false},
+		{"example.com/path", "", "", false},
+		{"ex%4", "", "", false},
+		{"::1", "", "", false},
+		{"[::1", "", "", false},
+		{"[::1]x", "", "", false},
+		{"[example.com]", "", "", false},
+		{"[v.x]", "", "", false},
+	} {
+		host, port, ok := ParseHost([]byte(test.in))
+		if string(host) != test.host || string(port) != test.port || ok != test.ok {
+			t.Errorf(
+				"ParseHost(%q) = %q, %q, %v; want %q, %q, %v",
+				test.in, host, port, ok, test.host, test.port, test.ok,
+			)
+		}
+	}
+	if _, port, _ := ParseHost([]byte("example.com")); port != nil {
+		t.Errorf("ParseHost() returned non-nil port for host without port")
+	}
+	if _, port, _ := ParseHost([]byte("example.com:")); port == nil {
+		t.Errorf("ParseHost() returned nil port for host with empty port")
+	}
+}
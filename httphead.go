@@ -16,26 +16,57 @@ import (
 //
 // It returns false if data is malformed.
 func ScanTokens(data []byte, it func([]byte) bool) bool {
-	lexer := &Scanner{data: data}
+	return DefaultListScanner.ScanTokens(data, it)
+}
 
-	var ok bool
-	for lexer.Next() {
-		switch lexer.Type() {
-		case ItemToken:
-			ok = true
-			if !it(lexer.Bytes()) {
-				return true
-			}
-		case ItemSeparator:
-			if !isComma(lexer.Bytes()) {
-				return false
-			}
-		default:
+// ScanTokensControl is the same as ScanTokens() but given callback returns
+// one of the defined Control* values.
+// ControlSkip means that the rest of current list element must be skipped up
+// to the next comma, even if it contains malformed bytes. That is, it could be
+// used to recover from junk in the real world token lists.
+// ControlBreak means that no more tokens should be scanned.
+// ControlContinue means that caller want to receive next token.
+func ScanTokensControl(data []byte, it func([]byte) Control) bool {
+	return DefaultListScanner.ScanTokensControl(data, it)
+}
+
+// scanTokenList is like ScanTokens(), but does not allocate. Empty list is
+// valid. It returns false if data is malformed before it returned false.
+func scanTokenList(data []byte, it func([]byte) bool) bool {
+	if exceedsLimit(data) {
+		return false
+	}
+	p := data
+	for {
+		for len(p) > 0 && (p[0] == ',' || OctetTypes[p[0]].IsSpace()) {
+			p = p[1:]
+		}
+		if len(p) == 0 {
+			return true
+		}
+		n, t := ScanToken(p)
+		if t != ItemToken {
+			return false
+		}
+		if !it(p[:n]) {
+			return true
+		}
+		p = p[n:]
+		p = p[SkipSpace(p):]
+		if len(p) > 0 && p[0] != ',' {
 			return false
 		}
 	}
+}
 
-	return ok && !lexer.err
+// containsToken reports whether comma separated list of tokens contains token
+// for which match returns true. It does not allocate.
+func containsToken(data []byte, match func([]byte) bool) (has bool) {
+	scanTokenList(data, func(p []byte) bool {
+		has = match(p)
+		return !has
+	})
+	return has
 }
 
 // ParseOptions parses all header options and appends it to given slice of
@@ -44,19 +75,130 @@ func ScanTokens(data []byte, it func([]byte) bool) bool {
 // Note that appended options are all consist of subslices of data. That is,
 // mutation of data will mutate appended options.
 func ParseOptions(data []byte, options []Option) ([]Option, bool) {
-	var i int
+	return ParseOptionsFlags(data, options, 0)
+}
+
+// ParseFlag encodes way of options parsing.
+type ParseFlag byte
+
+const (
+	// ParseKeepFirst causes parser to ignore options with the name which was
+	// already parsed.
+	ParseKeepFirst ParseFlag = 1 << iota
+
+	// ParseKeepLast causes parser to replace already parsed option with the
+	// same name. Replaced option keeps the position of the first occurence.
+	ParseKeepLast
+
+	// ParseMerge causes parser to append parameters of the option with the
+	// name which was already parsed to the parameters of that option.
+	ParseMerge
+
+	// ParseRejectDuplicates causes parser to treat options with the name
+	// which was already parsed as malformed input.
+	ParseRejectDuplicates
+
+	// ParseLowercase causes parser to store option and parameter names in
+	// lower case. Names are copied only if they contain upper case letters.
+	// Note that duplicates are compared after names normalization.
+	ParseLowercase
+)
+
+const parseDuplicates = ParseKeepFirst | ParseKeepLast | ParseMerge | ParseRejectDuplicates
+
+var parseFlagNames = [...]string{
+	"keep-first",
+	"keep-last",
+	"merge",
+	"reject-duplicates",
+	"lowercase",
+}
+
+// String represents flag as string.
+func (f ParseFlag) String() string {
+	var flags []string
+	for i, name := range parseFlagNames {
+		if f&(1<<uint(i)) != 0 {
+			flags = append(flags, name)
+		}
+	}
+	return "[" + strings.Join(flags, "|") + "]"
+}
+
+// ParseOptionsFlags is the same as ParseOptions() but respects given flags.
+//
+// By default all options are appended to the resulting slice, even if there
+// are multiple options with the same name. Duplicate policy could be changed
+// with one of the ParseKeepFirst, ParseKeepLast, ParseMerge or
+// ParseRejectDuplicates flags. Options are compared by names, including
+// options which are already present in given slice. If multiple policy flags
+// are given, the first one of the list above is used.
+func ParseOptionsFlags(data []byte, options []Option, flags ParseFlag) ([]Option, bool) {
+	options, err := parseOptions(data, options, flags)
+	return options, err == nil
+}
+
+// ParseOptionsErr is the same as ParseOptions() but returns an error instead
+// of false flag. Returned error is a *SyntaxError carrying offset of the
+// offending byte and description of expected input if data is malformed. See
+// ListScanner.ScanOptionsErr() for details.
+func ParseOptionsErr(data []byte, options []Option) ([]Option, error) {
+	return parseOptions(data, options, 0)
+}
+
+func parseOptions(data []byte, options []Option, flags ParseFlag) ([]Option, error) {
+	var (
+		i   int
+		dup bool
+	)
 	index := -1
-	return options, ScanOptions(data, func(idx int, name, attr, val []byte) Control {
+	err := ScanOptionsErr(data, func(idx int, name, attr, val []byte) Control {
+		if flags&ParseLowercase != 0 {
+			attr = lower(attr)
+		}
 		if idx != index {
 			index = idx
-			i = len(options)
-			options = append(options, Option{Name: name})
+			if flags&ParseLowercase != 0 {
+				name = lower(name)
+			}
+			j := -1
+			if flags&parseDuplicates != 0 {
+				j = indexOption(options, name)
+			}
+			switch {
+			case j == -1:
+				i = len(options)
+				options = append(options, Option{Name: name})
+			case flags&ParseKeepFirst != 0:
+				return ControlSkip
+			case flags&ParseKeepLast != 0:
+				i = j
+				options[i] = Option{Name: name}
+			case flags&ParseMerge != 0:
+				i = j
+			default:
+				dup = true
+				return ControlBreak
+			}
 		}
 		if attr != nil {
 			options[i].Parameters.Set(attr, val)
 		}
 		return ControlContinue
 	})
+	if err == nil && dup {
+		err = errDuplicate
+	}
+	return options, err
+}
+
+func indexOption(options []Option, name []byte) int {
+	for i := range options {
+		if bytes.Equal(options[i].Name, name) {
+			return i
+		}
+	}
+	return -1
 }
 
 // SelectFlag encodes way of options selection.
@@ -64,7 +206,7 @@ type SelectFlag byte
 
 // String represetns flag as string.
 func (f SelectFlag) String() string {
-	var flags [2]string
+	var flags [3]string
 	var n int
 	if f&SelectCopy != 0 {
 		flags[n] = "copy"
@@ -74,6 +216,10 @@ func (f SelectFlag) String() string {
 		flags[n] = "unique"
 		n++
 	}
+	if f&SelectFold != 0 {
+		flags[n] = "fold"
+		n++
+	}
 	return "[" + strings.Join(flags[:n], "|") + "]"
 }
 
@@ -87,8 +233,41 @@ const (
 	// SelectUnique causes selector to append only not yet existing option to
 	// resulting slice. Unique is checked by comparing option names.
 	SelectUnique
+
+	// SelectFold causes selector to compare option names case-insensitively
+	// when SelectUnique flag is set. That is, "Gzip" and "gzip" are treated
+	// as the same option.
+	SelectFold
 )
 
+// RejectReason describes the reason of option rejection by OptionSelector.
+type RejectReason byte
+
+const (
+	// RejectCheck means that option was rejected by OptionSelector.Check.
+	RejectCheck RejectReason = iota + 1
+
+	// RejectDuplicate means that option was rejected due to SelectUnique flag.
+	RejectDuplicate
+
+	// RejectLimit means that option was rejected due to OptionSelector.Limit.
+	RejectLimit
+)
+
+// String represents reason as a string.
+func (r RejectReason) String() string {
+	switch r {
+	case RejectCheck:
+		return "check"
+	case RejectDuplicate:
+		return "duplicate"
+	case RejectLimit:
+		return "limit"
+	default:
+		return "unknown"
+	}
+}
+
 // OptionSelector contains configuration for selecting Options from header value.
 type OptionSelector struct {
 	// Check is a filter function that applied to every Option that possibly
@@ -104,6 +283,19 @@ type OptionSelector struct {
 	// of single Option.
 	// If Alloc is nil make is used.
 	Alloc func(n int) []byte
+
+	// Limit is the maximum number of options selected by a single Select()
+	// call. Options exceeding the limit are rejected.
+	// If Limit is zero, the number of selected options is not limited.
+	Limit int
+
+	// Reject is called for every option which was not selected, with the
+	// reason of rejection. Note that passed option is never copied and
+	// contains sub-slices of the initial data. Duplicate options are
+	// rejected before parsing their parameters, so only option name is set
+	// in that case.
+	// If Reject is nil rejected options are silently dropped.
+	Reject func(Option, RejectReason)
 }
 
 // Select parses header data and appends it to given slice of Option.
@@ -111,6 +303,7 @@ type OptionSelector struct {
 func (s OptionSelector) Select(data []byte, options []Option) ([]Option, bool) {
 	var current Option
 	var has bool
+	var n int
 	index := -1
 
 	alloc := s.Alloc
@@ -121,19 +314,35 @@ func (s OptionSelector) Select(data []byte, options []Option) ([]Option, bool) {
 	if check == nil {
 		check = defaultCheck
 	}
+	reject := s.Reject
+	if reject == nil {
+		reject = defaultReject
+	}
+	flush := func() {
+		switch {
+		case !check(current):
+			reject(current, RejectCheck)
+		case s.Limit > 0 && n >= s.Limit:
+			reject(current, RejectLimit)
+		default:
+			if s.Flags&SelectCopy != 0 {
+				current = current.Copy(alloc(current.Size()))
+			}
+			options = append(options, current)
+			n++
+		}
+		has = false
+	}
 
 	ok := ScanOptions(data, func(idx int, name, attr, val []byte) Control {
 		if idx != index {
-			if has && check(current) {
-				if s.Flags&SelectCopy != 0 {
-					current = current.Copy(alloc(current.Size()))
-				}
-				options = append(options, current)
-				has = false
+			if has {
+				flush()
 			}
 			if s.Flags&SelectUnique != 0 {
 				for i := len(options) - 1; i >= 0; i-- {
-					if bytes.Equal(options[i].Name, name) {
+					if s.sameName(options[i].Name, name) {
+						reject(Option{Name: name}, RejectDuplicate)
 						return ControlSkip
 					}
 				}
@@ -148,19 +357,25 @@ func (s OptionSelector) Select(data []byte, options []Option) ([]Option, bool) {
 
 		return ControlContinue
 	})
-	if has && check(current) {
-		if s.Flags&SelectCopy != 0 {
-			current = current.Copy(alloc(current.Size()))
-		}
-		options = append(options, current)
+	if has {
+		flush()
 	}
 
 	return options, ok
 }
 
+func (s OptionSelector) sameName(a, b []byte) bool {
+	if s.Flags&SelectFold != 0 {
+		return TokenEqualFold(a, b)
+	}
+	return bytes.Equal(a, b)
+}
+
 func defaultAlloc(n int) []byte { return make([]byte, n) }
 func defaultCheck(Option) bool  { return true }
 
+func defaultReject(Option, RejectReason) {}
+
 // Control represents operation that scanner should perform.
 type Control byte
 
@@ -169,7 +384,11 @@ const (
 	ControlContinue Control = iota
 	// ControlBreak causes scanner to stop scan tokens.
 	ControlBreak
-	// ControlSkip causes scanner to skip current entity.
+	// ControlSkip causes scanner to skip current entity. For options it
+	// means skipping the rest of parameters of the current option. If the
+	// option is already ended (that is, callback was called for an option
+	// without parameters right before the comma), there is nothing to skip
+	// and scanning continues with the next option.
 	ControlSkip
 )
 
@@ -193,7 +412,349 @@ const (
 //
 // It returns false if data is malformed.
 func ScanOptions(data []byte, it func(index int, option, attribute, value []byte) Control) bool {
-	lexer := &Scanner{data: data}
+	return DefaultListScanner.ScanOptions(data, it)
+}
+
+// ScanOptionsErr is the same as ScanOptions() but returns an error describing
+// malformed data. See ListScanner.ScanOptionsErr() for details.
+func ScanOptionsErr(data []byte, it func(index int, option, attribute, value []byte) Control) error {
+	return DefaultListScanner.ScanOptionsErr(data, it)
+}
+
+// ScanFlag encodes way of header value scanning.
+type ScanFlag uint32
+
+const (
+	// ScanRejectEmpty causes scanner to treat empty list elements (such as
+	// "a,,b" or leading and trailing commas) as malformed input.
+	//
+	// Note that RFC7230 only allows recipients to accept empty elements, so
+	// by default they are silently skipped.
+	// See https://tools.ietf.org/html/rfc7230#section-7
+	ScanRejectEmpty ScanFlag = 1 << iota
+
+	// ScanEmptyValues causes scanner to accept parameters with empty value
+	// after the "=" sign, such as "foo=;bar=1" or trailing "foo=". Such
+	// parameters are reported with empty but non-nil value.
+	ScanEmptyValues
+
+	// ScanExtValues causes scanner to decode RFC8187 ext-value of parameters
+	// which names end with "*" (such as "filename*=UTF-8''%e2%82%ac") before
+	// passing them to the callback. Extended parameters with malformed or
+	// quoted values are treated as malformed input.
+	// See https://tools.ietf.org/html/rfc8187#section-3.2
+	ScanExtValues
+
+	// ScanRejectControl causes scanner to treat any control character except
+	// HT (including bare CR, LF and NUL) as malformed input, even if it
+	// appears inside quoted-string. Scanner reports ErrHeaderInjection in
+	// such case.
+	// See https://tools.ietf.org/html/rfc9110#section-5.5
+	ScanRejectControl
+
+	// ScanValidUTF8 causes scanner to treat quoted-strings which contents are
+	// not valid UTF-8 text as malformed input. Scanner reports SyntaxError
+	// with ErrInvalidUTF8 and offset of the first invalid sequence in such
+	// case.
+	ScanValidUTF8
+
+	// ScanNoComments causes scanner to treat "(" as malformed input instead
+	// of scanning a comment. Comments are allowed only in some fields, such
+	// as User-Agent, Server or Via.
+	// See https://tools.ietf.org/html/rfc9110#section-5.6.5
+	ScanNoComments
+
+	// ScanRejectTrailingSpace causes scanner to treat whitespace after
+	// unquoted parameter value as malformed input. By default such
+	// whitespace is trimmed.
+	ScanRejectTrailingSpace
+
+	// ScanRejectObsFold causes scanner to treat obsolete line folding (CRLF
+	// followed by SP or HT) as malformed input.
+	// See https://tools.ietf.org/html/rfc9112#section-5.2
+	ScanRejectObsFold
+
+	// ScanRejectObsText causes scanner to treat non-ASCII bytes inside
+	// quoted-strings and comments as malformed input.
+	// See https://tools.ietf.org/html/rfc9110#section-5.5
+	ScanRejectObsText
+
+	// ScanRejectBWS causes scanner to treat whitespace around "=" sign of
+	// parameters as malformed input, as RFC9110 parameter grammar does not
+	// allow it.
+	// See https://tools.ietf.org/html/rfc9110#section-5.6.6
+	ScanRejectBWS
+
+	// ScanBareSemicolons causes scanner to accept empty parameters, such as
+	// "foo;;a=1" or "foo;,bar".
+	ScanBareSemicolons
+
+	// ScanMediaTypes causes scanner to expect option names in the media type
+	// form, such as "text/html" or "*/*":
+	//
+	// media-type = type "/" subtype
+	//
+	// Option name is passed to the callback as a single slice including "/".
+	// See https://tools.ietf.org/html/rfc9110#section-8.3.1
+	ScanMediaTypes
+
+	// ScanRequireComma causes scanner to treat list elements which are not
+	// separated by comma as malformed input, such as "gzip chunked". By
+	// default such input is accepted for compatibility, but it could be
+	// interpreted differently by other implementations.
+	ScanRequireComma
+
+	// ScanStrict causes scanner to check quoted-strings and comments against
+	// RFC9110 grammar instead of RFC2616 one:
+	//
+	// quoted-string = DQUOTE *( qdtext / quoted-pair ) DQUOTE
+	// qdtext        = HTAB / SP / %x21 / %x23-5B / %x5D-7E / obs-text
+	// quoted-pair   = "\" ( HTAB / SP / VCHAR / obs-text )
+	//
+	// That is, control characters other than HT are treated as malformed
+	// input even if they are escaped, and backslash is always treated as a
+	// start of quoted-pair, such that "\\" does not escape the following
+	// quote. Note that tokens are always scanned as RFC9110 tchar sequences.
+	// See https://tools.ietf.org/html/rfc9110#section-5.6.4
+	ScanStrict
+
+	// ScanUnfoldLWS causes scanner to replace obsolete line folding (CRLF
+	// followed by SP or HT) with a single SP before scanning. Data is copied
+	// only if it contains such folding; in that case scanned items are
+	// subslices of the copy and error offsets are relative to it.
+	// ScanRejectObsFold has no effect if this flag is set.
+	// See UnfoldLWS().
+	ScanUnfoldLWS
+
+	// ScanRawValues causes scanner to pass quoted-strings as is, including
+	// surrounding quotes and escaping backslashes, instead of unescaping
+	// them. That is, quoted parameter values are always subslices of the
+	// scanned data and could be copied verbatim. Quoted values could be
+	// distinguished from tokens by the leading '"' and unescaped later by
+	// passing them without quotes to UnescapeQuotedString().
+	ScanRawValues
+)
+
+var scanFlagNames = [...]string{
+	"reject-empty",
+	"empty-values",
+	"ext-values",
+	"reject-control",
+	"valid-utf8",
+	"no-comments",
+	"reject-trailing-space",
+	"reject-obs-fold",
+	"reject-obs-text",
+	"reject-bws",
+	"bare-semicolons",
+	"media-types",
+	"require-comma",
+	"strict",
+	"unfold-lws",
+	"raw-values",
+}
+
+// Unnamed flags used by Validate() to reuse options scanning.
+const (
+	// scanTokensOnly causes scanner to treat parameters as malformed input.
+	scanTokensOnly ScanFlag = 1 << (31 - iota)
+
+	// scanRejectTruncated causes scanner to report ErrTruncated if data ends
+	// with ";" or "=" of the parameter.
+	scanRejectTruncated
+)
+
+// String represents flag as string.
+func (f ScanFlag) String() string {
+	var flags []string
+	for i, name := range scanFlagNames {
+		if f&(1<<uint(i)) != 0 {
+			flags = append(flags, name)
+		}
+	}
+	return "[" + strings.Join(flags, "|") + "]"
+}
+
+// SpaceMode describes how whitespace at some position of the grammar is
+// handled.
+type SpaceMode byte
+
+const (
+	// SpaceAuto means that scanner uses its own default for the position.
+	SpaceAuto SpaceMode = iota
+
+	// SpaceOptional means that whitespace may be omitted, as RFC9110 OWS.
+	SpaceOptional
+
+	// SpaceRequired means that whitespace must be present, as RFC9110 RWS.
+	SpaceRequired
+)
+
+// String represents mode as a string.
+func (m SpaceMode) String() string {
+	switch m {
+	case SpaceAuto:
+		return "auto"
+	case SpaceOptional:
+		return "optional"
+	case SpaceRequired:
+		return "required"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultListScanner is a ListScanner which is used by ScanTokens() and
+// ScanOptions().
+var DefaultListScanner = ListScanner{}
+
+// ListScanner contains options for scanning comma separated lists of header
+// values.
+type ListScanner struct {
+	// Flags contains flags for header value scanning.
+	Flags ScanFlag
+
+	// Min and Max are the minimum and maximum number of non-empty list
+	// elements, as in RFC9110 "<n>#<m>element" notation. Data with other
+	// number of elements is treated as malformed input and ErrCardinality is
+	// reported.
+	// If Min is less than one, at least one element is required anyway.
+	// If Max is zero, the number of elements is not limited.
+	// See https://tools.ietf.org/html/rfc9110#section-5.6.1
+	//
+	// Note that elements are not counted after callback breaks the scanning.
+	Min, Max int
+
+	// MaxItems, MaxTokenLength and MaxCommentDepth limit scanning of
+	// untrusted data, such that scanning stops with ErrLimitExceeded when
+	// some of them is exceeded. Zero value disables the limit. See
+	// Scanner.SetLimits().
+	MaxItems, MaxTokenLength, MaxCommentDepth int
+
+	// Buffer is used to store unescaped quoted-string values, such that
+	// scanning does not allocate when values contain escaped characters and
+	// Buffer has enough capacity. Values passed to the callback could refer
+	// to Buffer. See Scanner.SetBuffer().
+	Buffer []byte
+}
+
+// ScanTokens is the same as ScanTokens() function, but respects scanner
+// configuration.
+func (s ListScanner) ScanTokens(data []byte, it func([]byte) bool) bool {
+	return s.ScanTokensControl(data, func(v []byte) Control {
+		if it(v) {
+			return ControlContinue
+		}
+		return ControlBreak
+	})
+}
+
+// ScanTokensControl is the same as ScanTokensControl() function, but respects
+// scanner configuration.
+func (s ListScanner) ScanTokensControl(data []byte, it func([]byte) Control) bool {
+	lexer := newScanner(data, s.Flags)
+	lexer.SetLimits(s.MaxItems, s.MaxTokenLength, s.MaxCommentDepth)
+
+	var (
+		ok    bool
+		elem  bool
+		comma bool
+		n     int
+	)
+	for lexer.Next() {
+		switch lexer.Type() {
+		case ItemToken:
+			if elem && s.Flags&ScanRequireComma != 0 {
+				return false
+			}
+			if n++; s.Max > 0 && n > s.Max {
+				return false
+			}
+			ok = true
+			elem = true
+			comma = false
+			switch it(lexer.Bytes()) {
+			case ControlBreak:
+				return true
+			case ControlSkip:
+				if comma = lexer.skipEscaped(','); comma {
+					elem = false
+				}
+			case ControlContinue:
+			default:
+				panic("unexpected control value")
+			}
+		case ItemSeparator:
+			if !isComma(lexer.Bytes()) {
+				return false
+			}
+			if !elem && s.Flags&ScanRejectEmpty != 0 {
+				return false
+			}
+			elem = false
+			comma = true
+		default:
+			return false
+		}
+	}
+	if comma && s.Flags&ScanRejectEmpty != 0 {
+		return false
+	}
+
+	return ok && lexer.err == nil && n >= s.Min
+}
+
+// ScanOptions is the same as ScanOptions() function, but respects scanner
+// configuration.
+func (s ListScanner) ScanOptions(data []byte, it func(index int, option, attribute, value []byte) Control) bool {
+	err := s.scanOptions(data, func(index int, option, attribute, value []byte, _, _ int) Control {
+		return it(index, option, attribute, value)
+	})
+	if err == ErrControl {
+		panic("unexpected control value")
+	}
+	return err == nil
+}
+
+// ScanOptionsOffsets is the same as ScanOptionsErr() but also passes byte
+// range of the scanned option text to the callback. See
+// ListScanner.ScanOptionsOffsets() for details.
+func ScanOptionsOffsets(data []byte, it func(index int, option, attribute, value []byte, start, end int) Control) error {
+	return DefaultListScanner.ScanOptionsOffsets(data, it)
+}
+
+// ScanOptionsErr is the same as ScanOptions() but returns an error instead of
+// false flag. Returned error is a *SyntaxError if data is malformed, or
+// ErrLimitExceeded if data is too long.
+//
+// Unlike ScanOptions() it does not panic when callback returns unknown
+// Control value, but stops scanning and returns ErrControl instead.
+func (s ListScanner) ScanOptionsErr(data []byte, it func(index int, option, attribute, value []byte) Control) error {
+	return s.scanOptions(data, func(index int, option, attribute, value []byte, _, _ int) Control {
+		return it(index, option, attribute, value)
+	})
+}
+
+// ScanOptionsOffsets is the same as ScanOptionsErr() but also passes to the
+// callback byte range of the option text scanned so far. That is,
+// data[start:end] is the raw text from the beginning of the option name up to
+// the end of the current parameter, including quotes and escaping
+// backslashes. Thus on the last call for some option it covers the whole
+// option.
+//
+// Note that if ScanUnfoldLWS flag is set, offsets are relative to the
+// unfolded copy of data.
+func (s ListScanner) ScanOptionsOffsets(data []byte, it func(index int, option, attribute, value []byte, start, end int) Control) error {
+	return s.scanOptions(data, it)
+}
+
+func (s ListScanner) scanOptions(data []byte, it func(index int, option, attribute, value []byte, start, end int) Control) error {
+	var lexer Scanner
+	lexer.init(data, s.Flags)
+	lexer.SetBuffer(s.Buffer)
+	lexer.SetLimits(s.MaxItems, s.MaxTokenLength, s.MaxCommentDepth)
+	// Note that data could be unfolded by the scanner.
+	data = lexer.data
 
 	var ok bool
 	var state int
@@ -204,11 +765,24 @@ func ScanOptions(data []byte, it func(index int, option, attribute, value []byte
 		stateParamBeforeValue
 		stateParamValue
 	)
+	expected := [...]string{
+		stateKey:              "token",
+		stateParamBeforeName:  "';' or ','",
+		stateParamName:        "token after ';'",
+		stateParamBeforeValue: "'=', ';' or ','",
+		stateParamValue:       "token or '\"' after '='",
+	}
+	if s.Flags&scanTokensOnly != 0 {
+		expected[stateParamBeforeName] = "','"
+	}
 
 	var (
 		index             int
 		key, param, value []byte
 		mustCall          bool
+		comma             bool
+		n                 int
+		start, end        int
 	)
 	for lexer.Next() {
 		var (
@@ -219,11 +793,28 @@ func ScanOptions(data []byte, it func(index int, option, attribute, value []byte
 		t := lexer.Type()
 		v := lexer.Bytes()
 
+		if t != ItemSeparator || !isComma(v) {
+			comma = false
+		}
+
 		switch t {
 		case ItemToken:
 			switch state {
 			case stateKey, stateParamBeforeName:
-				key = v
+				if state == stateParamBeforeName && s.Flags&ScanRequireComma != 0 {
+					return lexer.malformed(expected[state])
+				}
+				if state == stateKey {
+					if n++; s.Max > 0 && n > s.Max {
+						lexer.fail(lexer.start, ErrCardinality, "")
+						return lexer.err
+					}
+				}
+				if s.Flags&ScanMediaTypes != 0 && !lexer.fetchMediaType() {
+					return lexer.err
+				}
+				start = lexer.start
+				key = lexer.Bytes()
 				state = stateParamBeforeName
 				mustCall = true
 			case stateParamName:
@@ -231,27 +822,41 @@ func ScanOptions(data []byte, it func(index int, option, attribute, value []byte
 				state = stateParamBeforeValue
 				mustCall = true
 			case stateParamValue:
+				if s.Flags&ScanRejectTrailingSpace != 0 && isSpace(lexer.Peek()) {
+					lexer.fail(lexer.pos, ErrMalformed, expected[stateParamBeforeName])
+					return lexer.err
+				}
 				value = v
 				state = stateParamBeforeName
 				call = true
 			default:
-				return false
+				return lexer.malformed(expected[state])
 			}
 
 		case ItemString:
 			if state != stateParamValue {
-				return false
+				return lexer.malformed(expected[state])
+			}
+			if s.Flags&ScanExtValues != 0 && isExtName(param) {
+				return lexer.malformed("ext-value token")
 			}
 			value = v
 			state = stateParamBeforeName
 			call = true
 
 		case ItemSeparator:
+			if isComma(v) {
+				comma = true
+			} else if s.Flags&scanTokensOnly != 0 {
+				return lexer.malformed(expected[state])
+			}
 			switch {
 			case isComma(v) && state == stateKey:
-				// Nothing to do.
+				if s.Flags&ScanRejectEmpty != 0 {
+					return lexer.malformed(expected[state])
+				}
 
-			case isComma(v) && state == stateParamBeforeName:
+			case isComma(v) && (state == stateParamBeforeName || state == stateParamName && s.Flags&ScanBareSemicolons != 0):
 				state = stateKey
 				// Make call only if we have not called this key yet.
 				call = mustCall
@@ -272,38 +877,71 @@ func ScanOptions(data []byte, it func(index int, option, attribute, value []byte
 			case isSemicolon(v) && state == stateParamBeforeName:
 				state = stateParamName
 
+			case isSemicolon(v) && state == stateParamName && s.Flags&ScanBareSemicolons != 0:
+				// Empty parameter.
+
 			case isSemicolon(v) && state == stateParamBeforeValue:
 				state = stateParamName
 				call = true
 
 			case isEquality(v) && state == stateParamBeforeValue:
+				if s.Flags&ScanRejectBWS != 0 && (isSpace(data[lexer.start-1]) || isSpace(lexer.Peek())) {
+					return lexer.malformed("'=' without whitespace around")
+				}
+				end = lexer.pos
 				state = stateParamValue
 
+			case isComma(v) && state == stateParamValue && s.Flags&ScanEmptyValues != 0:
+				value = v[:0]
+				state = stateKey
+				growIndex = 1
+				call = true
+
+			case isSemicolon(v) && state == stateParamValue && s.Flags&ScanEmptyValues != 0:
+				value = v[:0]
+				state = stateParamName
+				call = true
+
 			default:
-				return false
+				return lexer.malformed(expected[state])
 			}
 
 		default:
-			return false
+			return lexer.malformed(expected[state])
+		}
+		if t != ItemSeparator {
+			end = lexer.pos
 		}
 
+		if call && len(value) > 0 && s.Flags&ScanExtValues != 0 && isExtName(param) {
+			var ok bool
+			if value, ok = extValue(value); !ok {
+				return lexer.malformed("ext-value token")
+			}
+		}
 		if call {
-			switch it(index, key, param, value) {
+			switch it(index, key, param, value, start, end) {
 			case ControlBreak:
 				// User want to stop to parsing parameters.
-				return true
+				return nil
 
 			case ControlSkip:
-				// User want to skip current param.
-				state = stateKey
-				lexer.SkipEscaped(',')
+				// User want to skip current param. Note that if call was
+				// made on the comma, then there is nothing to skip.
+				if state != stateKey {
+					state = stateKey
+					if comma = lexer.skipEscaped(','); comma {
+						// Skipped option is ended.
+						growIndex = 1
+					}
+				}
 
 			case ControlContinue:
 				// User is interested in rest of parameters.
 				// Nothing to do.
 
 			default:
-				panic("unexpected control value")
+				return ErrControl
 			}
 			ok = true
 			param = nil
@@ -312,12 +950,67 @@ func ScanOptions(data []byte, it func(index int, option, attribute, value []byte
 			index += growIndex
 		}
 	}
+	if lexer.err == nil && s.Flags&scanRejectTruncated != 0 &&
+		(state == stateParamName && s.Flags&ScanBareSemicolons == 0 ||
+			state == stateParamValue && s.Flags&ScanEmptyValues == 0) {
+		return &SyntaxError{
+			Offset:   len(data),
+			Err:      ErrTruncated,
+			Expected: expected[state],
+		}
+	}
 	if mustCall {
+		if state == stateParamValue && s.Flags&ScanEmptyValues != 0 {
+			value = data[len(data):]
+		}
 		ok = true
-		it(index, key, param, value)
+		it(index, key, param, value, start, end)
+	}
+	if comma && s.Flags&ScanRejectEmpty != 0 {
+		return &SyntaxError{
+			Offset:   len(data),
+			Err:      ErrMalformed,
+			Expected: expected[stateKey],
+		}
+	}
+	if lexer.err != nil {
+		if e, ok := lexer.err.(*SyntaxError); ok && e.Expected == "" && e.Err == ErrMalformed {
+			e.Expected = expected[state]
+		}
+		return lexer.err
+	}
+	if !ok {
+		return &SyntaxError{
+			Offset:   len(data),
+			Err:      ErrMalformed,
+			Expected: expected[stateKey],
+		}
 	}
+	if n < s.Min {
+		return &SyntaxError{
+			Offset:   len(data),
+			Err:      ErrCardinality,
+			Expected: "','",
+		}
+	}
+	return nil
+}
+
+// lower returns p in lower case. It copies p only if it contains upper case
+// letters.
+func lower(p []byte) []byte {
+	for i, c := range p {
+		if lowercase[c] != c {
+			r := make([]byte, i, len(p))
+			copy(r, p[:i])
+			return FoldToken(r, p[i:])
+		}
+	}
+	return p
+}
 
-	return ok && !lexer.err
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
 }
 
 func isComma(b []byte) bool {
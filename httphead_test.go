@@ -2,8 +2,10 @@ package httphead
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 )
 
@@ -37,7 +39,7 @@ func ExampleParseOptions() {
 	// Output: [{foo [bar:1]} {baz []}] true
 }
 
-func ExampleParseOptionsLifetime() {
+func ExampleParseOptions_lifetime() {
 	data := []byte(`foo;bar=1,baz`)
 	options, ok := ParseOptions(data, nil)
 	copy(data, []byte(`xxx;yyy=0,zzz`))
@@ -50,6 +52,8 @@ var listCases = []struct {
 	in    []byte
 	ok    bool
 	exp   [][]byte
+
+	s ListScanner
 }{
 	{
 		label: "simple",
@@ -80,13 +84,113 @@ var listCases = []struct {
 			[]byte(`b`),
 		},
 	},
+	{
+		label: "reject_empty",
+		in:    []byte(`a, b ,c`),
+		ok:    true,
+		exp: [][]byte{
+			[]byte(`a`),
+			[]byte(`b`),
+			[]byte(`c`),
+		},
+		s: ListScanner{Flags: ScanRejectEmpty},
+	},
+	{
+		label: "reject_empty",
+		in:    []byte(`a,b, ,c`),
+		ok:    false,
+		exp: [][]byte{
+			[]byte(`a`),
+			[]byte(`b`),
+		},
+		s: ListScanner{Flags: ScanRejectEmpty},
+	},
+	{
+		label: "reject_empty",
+		in:    []byte(`,a`),
+		ok:    false,
+		s:     ListScanner{Flags: ScanRejectEmpty},
+	},
+	{
+		label: "reject_empty",
+		in:    []byte(`a,`),
+		ok:    false,
+		exp: [][]byte{
+			[]byte(`a`),
+		},
+		s: ListScanner{Flags: ScanRejectEmpty},
+	},
+	{
+		label: "require_comma",
+		in:    []byte(`a, b c`),
+		ok:    false,
+		exp: [][]byte{
+			[]byte(`a`),
+			[]byte(`b`),
+		},
+		s: ListScanner{Flags: ScanRequireComma},
+	},
+	{
+		label: "reject_control",
+		in:    []byte("a,\x00b"),
+		ok:    false,
+		s:     ListScanner{Flags: ScanRejectControl},
+	},
+	{
+		label: "cardinality",
+		in:    []byte(`a,,b`),
+		ok:    true,
+		exp: [][]byte{
+			[]byte(`a`),
+			[]byte(`b`),
+		},
+		s: ListScanner{Min: 2, Max: 2},
+	},
+	{
+		label: "cardinality",
+		in:    []byte(`a`),
+		ok:    false,
+		exp: [][]byte{
+			[]byte(`a`),
+		},
+		s: ListScanner{Min: 2},
+	},
+	{
+		label: "cardinality",
+		in:    []byte(`a,b,c`),
+		ok:    false,
+		exp: [][]byte{
+			[]byte(`a`),
+			[]byte(`b`),
+		},
+		s: ListScanner{Max: 2},
+	},
+	{
+		label: "limits",
+		in:    []byte(`ab, abc`),
+		ok:    false,
+		exp: [][]byte{
+			[]byte(`ab`),
+		},
+		s: ListScanner{MaxTokenLength: 2},
+	},
+	{
+		label: "limits",
+		in:    []byte(`a, b, c`),
+		ok:    false,
+		exp: [][]byte{
+			[]byte(`a`),
+			[]byte(`b`),
+		},
+		s: ListScanner{MaxItems: 4},
+	},
 }
 
 func TestScanTokens(t *testing.T) {
 	for _, test := range listCases {
 		t.Run(test.label, func(t *testing.T) {
 			var act [][]byte
-			ok := ScanTokens(test.in, func(v []byte) bool {
+			ok := test.s.ScanTokens(test.in, func(v []byte) bool {
 				act = append(act, v)
 				return true
 			})
@@ -106,11 +210,67 @@ func TestScanTokens(t *testing.T) {
 	}
 }
 
+func TestScanTokensControl(t *testing.T) {
+	for _, test := range []struct {
+		label string
+		in    string
+		s     ListScanner
+		ok    bool
+		exp   []string
+	}{
+		{
+			label: "skip",
+			in:    `a, b junk; (x) "y", c`,
+			ok:    true,
+			exp:   []string{"a", "b", "c"},
+		},
+		{
+			label: "skip_last",
+			in:    `a, b; junk`,
+			ok:    true,
+			exp:   []string{"a", "b"},
+		},
+		{
+			label: "skip_reject_empty",
+			in:    `b;x,,c`,
+			s:     ListScanner{Flags: ScanRejectEmpty},
+			ok:    false,
+			exp:   []string{"b"},
+		},
+		{
+			label: "break",
+			in:    `a, stop, c`,
+			ok:    true,
+			exp:   []string{"a", "stop"},
+		},
+	} {
+		t.Run(test.label, func(t *testing.T) {
+			var act []string
+			ok := test.s.ScanTokensControl([]byte(test.in), func(v []byte) Control {
+				act = append(act, string(v))
+				switch string(v) {
+				case "b":
+					return ControlSkip
+				case "stop":
+					return ControlBreak
+				}
+				return ControlContinue
+			})
+			if ok != test.ok {
+				t.Errorf("ScanTokensControl(%q) = %v; want %v", test.in, ok, test.ok)
+			}
+			if !reflect.DeepEqual(act, test.exp) {
+				t.Errorf("ScanTokensControl(%q) tokens are %q; want %q", test.in, act, test.exp)
+			}
+		})
+	}
+}
+
 func BenchmarkScanTokens(b *testing.B) {
 	for _, bench := range listCases {
 		b.Run(bench.label, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				_ = ScanTokens(bench.in, func(v []byte) bool { return true })
+				_ = bench.s.ScanTokens(bench.in, func(v []byte) bool { return true })
 			}
 		})
 	}
@@ -132,6 +292,8 @@ var parametersCases = []struct {
 	in    []byte
 	ok    bool
 	exp   []tuple
+
+	s ListScanner
 }{
 	{
 		label: "simple",
@@ -210,6 +372,205 @@ var parametersCases = []struct {
 			{index: 1, option: []byte(`bar`), attribute: []byte(`b`), value: []byte(`2`)},
 		},
 	},
+	{
+		label: "reject_empty",
+		in:    []byte(`foo;a=1, bar;b=2`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+			{index: 1, option: []byte(`bar`), attribute: []byte(`b`), value: []byte(`2`)},
+		},
+		s: ListScanner{Flags: ScanRejectEmpty},
+	},
+	{
+		label: "reject_empty",
+		in:    []byte(`foo;a=1,,bar;b=2`),
+		ok:    false,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+		},
+		s: ListScanner{Flags: ScanRejectEmpty},
+	},
+	{
+		label: "reject_empty",
+		in:    []byte(`,foo`),
+		ok:    false,
+		s:     ListScanner{Flags: ScanRejectEmpty},
+	},
+	{
+		label: "reject_empty",
+		in:    []byte(`foo,bar;b, `),
+		ok:    false,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`)},
+			{index: 1, option: []byte(`bar`), attribute: []byte(`b`)},
+		},
+		s: ListScanner{Flags: ScanRejectEmpty},
+	},
+	{
+		label: "require_comma",
+		in:    []byte(`foo;a=1 bar`),
+		ok:    false,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+		},
+		s: ListScanner{Flags: ScanRequireComma},
+	},
+	{
+		label: "bare_semicolons",
+		in:    []byte(`foo;;a=1;,bar;,baz`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+			{index: 1, option: []byte(`bar`)},
+			{index: 2, option: []byte(`baz`)},
+		},
+		s: ListScanner{Flags: ScanBareSemicolons},
+	},
+	{
+		label: "cardinality",
+		in:    []byte(`foo;a=1,bar`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+			{index: 1, option: []byte(`bar`)},
+		},
+		s: ListScanner{Min: 2, Max: 2},
+	},
+	{
+		label: "cardinality",
+		in:    []byte(`foo;a=1,bar,baz`),
+		ok:    false,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+			{index: 1, option: []byte(`bar`)},
+		},
+		s: ListScanner{Max: 2},
+	},
+	{
+		label: "cardinality",
+		in:    []byte(`foo;a=1`),
+		ok:    false,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+		},
+		s: ListScanner{Min: 2},
+	},
+	{
+		label: "empty_values",
+		in:    []byte(`foo;a=;b=1,bar;c=`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte{}},
+			{index: 0, option: []byte(`foo`), attribute: []byte(`b`), value: []byte(`1`)},
+			{index: 1, option: []byte(`bar`), attribute: []byte(`c`), value: []byte{}},
+		},
+		s: ListScanner{Flags: ScanEmptyValues},
+	},
+	{
+		label: "empty_values",
+		in:    []byte(`foo;a=,bar`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte{}},
+			{index: 1, option: []byte(`bar`)},
+		},
+		s: ListScanner{Flags: ScanEmptyValues},
+	},
+	{
+		label: "empty_values",
+		in:    []byte(`foo;a=;b=1`),
+		ok:    false,
+	},
+	{
+		label: "ext_values",
+		in:    []byte(`foo;a*=UTF-8''%e2%82%ac;a=EUR`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a*`), value: []byte("€")},
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`EUR`)},
+		},
+		s: ListScanner{Flags: ScanExtValues},
+	},
+	{
+		label: "ext_values",
+		in:    []byte(`foo;a*=UTF-8''%e2%82%ac`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a*`), value: []byte(`UTF-8''%e2%82%ac`)},
+		},
+	},
+	{
+		label: "ext_values",
+		in:    []byte(`foo;a*="UTF-8''%e2%82%ac"`),
+		ok:    false,
+		s:     ListScanner{Flags: ScanExtValues},
+	},
+	{
+		label: "reject_control",
+		in:    []byte("foo;a=\"x\r\nSet-Cookie: y\""),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte("x\r\nSet-Cookie: y")},
+		},
+	},
+	{
+		label: "reject_control",
+		in:    []byte("foo;a=\"x\r\nSet-Cookie: y\""),
+		ok:    false,
+		s:     ListScanner{Flags: ScanRejectControl},
+	},
+	{
+		label: "reject_control",
+		in:    []byte("foo;a=\"x\ty\""),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte("x\ty")},
+		},
+		s: ListScanner{Flags: ScanRejectControl},
+	},
+	{
+		label: "trailing_space",
+		in:    []byte(`foo;a=1 ;b="2" , bar`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+			{index: 0, option: []byte(`foo`), attribute: []byte(`b`), value: []byte(`2`)},
+			{index: 1, option: []byte(`bar`)},
+		},
+	},
+	{
+		label: "trailing_space",
+		in:    []byte(`foo;a=1;b="2" , bar`),
+		ok:    true,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+			{index: 0, option: []byte(`foo`), attribute: []byte(`b`), value: []byte(`2`)},
+			{index: 1, option: []byte(`bar`)},
+		},
+		s: ListScanner{Flags: ScanRejectTrailingSpace},
+	},
+	{
+		label: "trailing_space",
+		in:    []byte(`foo;a=1 ;b=2`),
+		ok:    false,
+		s:     ListScanner{Flags: ScanRejectTrailingSpace},
+	},
+	{
+		label: "trailing_space",
+		in:    []byte(`foo;a=1 `),
+		ok:    false,
+		s:     ListScanner{Flags: ScanRejectTrailingSpace},
+	},
+	{
+		label: "no_comments",
+		in:    []byte(`foo (comment), bar`),
+		ok:    false,
+		exp: []tuple{
+			{index: 0, option: []byte(`foo`)},
+		},
+		s: ListScanner{Flags: ScanNoComments},
+	},
 }
 
 func TestParameters(t *testing.T) {
@@ -217,7 +578,7 @@ func TestParameters(t *testing.T) {
 		t.Run(test.label, func(t *testing.T) {
 			var act []tuple
 
-			ok := ScanOptions(test.in, func(index int, key, param, value []byte) Control {
+			ok := test.s.ScanOptions(test.in, func(index int, key, param, value []byte) Control {
 				act = append(act, tuple{index, key, param, value})
 				return ControlContinue
 			})
@@ -233,7 +594,7 @@ func TestParameters(t *testing.T) {
 			for i, e := range test.exp {
 				a := act[i]
 
-				if a.index != e.index || !bytes.Equal(a.option, e.option) || !bytes.Equal(a.attribute, e.attribute) || !bytes.Equal(a.value, e.value) {
+				if a.index != e.index || !bytes.Equal(a.option, e.option) || !bytes.Equal(a.attribute, e.attribute) || !bytes.Equal(a.value, e.value) || (a.value == nil) != (e.value == nil) {
 					t.Errorf(
 						"unexpected %d-th tuple: #%d %#q[%#q = %#q]; want #%d %#q[%#q = %#q]",
 						i,
@@ -246,11 +607,259 @@ func TestParameters(t *testing.T) {
 	}
 }
 
+func TestScanOptionsErr(t *testing.T) {
+	for _, test := range parametersCases {
+		t.Run(test.label, func(t *testing.T) {
+			err := test.s.ScanOptionsErr(test.in, func(_ int, _, _, _ []byte) Control {
+				return ControlContinue
+			})
+			if ok := err == nil; ok != test.ok {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if _, ok := err.(*SyntaxError); err != nil && !ok {
+				t.Errorf("unexpected error type: %T; want *SyntaxError", err)
+			}
+		})
+	}
+	t.Run("offset", func(t *testing.T) {
+		err := ScanOptionsErr([]byte(`foo;bar=1, baz;=2`), func(_ int, _, _, _ []byte) Control {
+			return ControlContinue
+		})
+		if se, ok := err.(*SyntaxError); !ok || se.Offset != 15 || se.Byte != '=' || se.Err != ErrMalformed || se.Expected != "token after ';'" {
+			t.Errorf("unexpected error: %v; want malformed at offset 15", err)
+		}
+	})
+	t.Run("expected", func(t *testing.T) {
+		err := ScanOptionsErr([]byte(`foo;bar=)`), func(_ int, _, _, _ []byte) Control {
+			return ControlContinue
+		})
+		exp := `httphead: malformed header value at offset 8: expected token or '"' after '='`
+		if err == nil || err.Error() != exp {
+			t.Errorf("unexpected error: %v; want %s", err, exp)
+		}
+	})
+	t.Run("cardinality", func(t *testing.T) {
+		s := ListScanner{Max: 1}
+		err := s.ScanOptionsErr([]byte(`foo;a=1, bar`), func(_ int, _, _, _ []byte) Control {
+			return ControlContinue
+		})
+		if se, ok := err.(*SyntaxError); !ok || se.Offset != 9 || se.Err != ErrCardinality {
+			t.Errorf("unexpected error: %v; want cardinality error at offset 9", err)
+		}
+		s = ListScanner{Min: 2}
+		err = s.ScanOptionsErr([]byte(`foo;a=1`), func(_ int, _, _, _ []byte) Control {
+			return ControlContinue
+		})
+		if se, ok := err.(*SyntaxError); !ok || se.Offset != 7 || se.Err != ErrCardinality {
+			t.Errorf("unexpected error: %v; want cardinality error at offset 7", err)
+		}
+	})
+	t.Run("control", func(t *testing.T) {
+		err := ScanOptionsErr([]byte(`foo;bar=1`), func(_ int, _, _, _ []byte) Control {
+			return Control(42)
+		})
+		if err != ErrControl {
+			t.Errorf("unexpected error: %v; want %v", err, ErrControl)
+		}
+	})
+}
+
+func TestParseOptionsErr(t *testing.T) {
+	opts, err := ParseOptionsErr([]byte(`foo;a=1, bar`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp := []Option{
+		NewOption("foo", map[string]string{"a": "1"}),
+		NewOption("bar", nil),
+	}; !reflect.DeepEqual(opts, exp) {
+		t.Errorf("ParseOptionsErr() = %v; want %v", opts, exp)
+	}
+
+	_, err = ParseOptionsErr([]byte(`foo;a=1, bar;b="x`), nil)
+	se, ok := err.(*SyntaxError)
+	if !ok || se.Offset != 15 || se.Byte != '"' || se.Err != ErrTruncated {
+		t.Errorf("unexpected error: %v; want truncated at offset 15", err)
+	}
+}
+
+func TestScanOptionsBuffer(t *testing.T) {
+	data := []byte(`foo;a="x\"y";b="z", bar;c="w\"v"`)
+	s := ListScanner{Buffer: make([]byte, 0, 16)}
+	var act []string
+	it := func(_ int, _, _, value []byte) Control {
+		act = append(act, string(value))
+		return ControlContinue
+	}
+	if err := s.ScanOptionsErr(data, it); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp := []string{`x"y`, `z`, `w"v`}; fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected values: %q; want %q", act, exp)
+	}
+	n := testing.AllocsPerRun(100, func() {
+		s.ScanOptions(data, func(int, []byte, []byte, []byte) Control {
+			return ControlContinue
+		})
+	})
+	if n != 0 {
+		t.Errorf("ScanOptions() allocates %v times; want 0", n)
+	}
+}
+
+func TestScanOptionsOffsets(t *testing.T) {
+	data := []byte(`foo;a="x y" ;b, bar, baz;c=`)
+	var act []string
+	err := ListScanner{Flags: ScanEmptyValues}.ScanOptionsOffsets(data, func(_ int, _, _, _ []byte, start, end int) Control {
+		act = append(act, string(data[start:end]))
+		return ControlContinue
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp := []string{`foo;a="x y"`, `foo;a="x y" ;b`, `bar`, `baz;c=`}
+	if fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected options text: %q; want %q", act, exp)
+	}
+}
+
+func TestScanOptionsBufferEqual(t *testing.T) {
+	for _, flags := range []ScanFlag{0, ScanStrict} {
+		for _, in := range []string{
+			`foo;a="x\\y\"z"`,
+			`foo;a="\\\\"`,
+			`foo;a="a\bc\\"`,
+			`foo;a="\"",b="x\y"`,
+		} {
+			scan := func(buf []byte) (ret []string, err error) {
+				s := ListScanner{Flags: flags, Buffer: buf}
+				err = s.ScanOptionsErr([]byte(in), func(_ int, _, _, value []byte) Control {
+					ret = append(ret, string(value))
+					return ControlContinue
+				})
+				return ret, err
+			}
+			exp, expErr := scan(nil)
+			act, actErr := scan(make([]byte, 0, 64))
+			if fmt.Sprint(act, actErr) != fmt.Sprint(exp, expErr) {
+				t.Errorf("%s %q: buffered values %q (%v); want %q (%v)", flags, in, act, actErr, exp, expErr)
+			}
+		}
+	}
+}
+
+func TestScanOptionsRawValues(t *testing.T) {
+	data := []byte(`foo;a="x\"y";b=z;c=""`)
+	s := ListScanner{Flags: ScanRawValues}
+	var act []string
+	it := func(_ int, _, _, value []byte) Control {
+		act = append(act, string(value))
+		return ControlContinue
+	}
+	if err := s.ScanOptionsErr(data, it); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp := []string{`"x\"y"`, `z`, `""`}; fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected values: %q; want %q", act, exp)
+	}
+	n := testing.AllocsPerRun(100, func() {
+		s.ScanOptions(data, func(int, []byte, []byte, []byte) Control {
+			return ControlContinue
+		})
+	})
+	if n != 0 {
+		t.Errorf("ScanOptions() allocates %v times; want 0", n)
+	}
+}
+
+func TestScanOptionsSkip(t *testing.T) {
+	var act []tuple
+	ScanOptions([]byte(`foo;a=1;b=2,bar,baz;c=3`), func(index int, key, param, value []byte) Control {
+		act = append(act, tuple{index, key, param, value})
+		if string(key) == "foo" {
+			return ControlSkip
+		}
+		return ControlContinue
+	})
+	exp := []tuple{
+		{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+		{index: 1, option: []byte(`bar`)},
+		{index: 2, option: []byte(`baz`), attribute: []byte(`c`), value: []byte(`3`)},
+	}
+	if fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected tuples: %v; want %v", act, exp)
+	}
+}
+
+func TestScanOptionsSkipParams(t *testing.T) {
+	// Skipping on some parameter skips the rest of parameters of the option,
+	// as it always did.
+	var act []tuple
+	ScanOptions([]byte(`foo;a=1;b=2;c=3`), func(index int, key, param, value []byte) Control {
+		act = append(act, tuple{index, key, param, value})
+		return ControlSkip
+	})
+	exp := []tuple{
+		{index: 0, option: []byte(`foo`), attribute: []byte(`a`), value: []byte(`1`)},
+	}
+	if fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected tuples: %v; want %v", act, exp)
+	}
+}
+
+func TestScanOptionsSkipEnded(t *testing.T) {
+	// Skipping an option which is already ended by comma must not skip the
+	// next option.
+	var act []tuple
+	ScanOptions([]byte(`foo, bar, baz;a=1`), func(index int, key, param, value []byte) Control {
+		act = append(act, tuple{index, key, param, value})
+		return ControlSkip
+	})
+	exp := []tuple{
+		{index: 0, option: []byte(`foo`)},
+		{index: 1, option: []byte(`bar`)},
+		{index: 2, option: []byte(`baz`), attribute: []byte(`a`), value: []byte(`1`)},
+	}
+	if fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected tuples: %v; want %v", act, exp)
+	}
+}
+
+func TestScanOptionsSkipIndex(t *testing.T) {
+	// Option following the skipped one must get the next index, such that
+	// callers which detect option boundaries by index change (as
+	// OptionSelector does) do not merge it into the skipped one.
+	for _, test := range []struct {
+		in   string
+		skip string
+		exp  []int
+	}{
+		{`foo;a=1;b=2, bar, baz`, "foo", []int{0, 1, 2}},
+		{`foo;a=1, bar;b=2, baz;c=3`, "bar", []int{0, 1, 2}},
+		{`foo;a="x,y";b=2, bar`, "foo", []int{0, 1}},
+		{`foo;a=1;b=2`, "foo", []int{0}},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			var act []int
+			ScanOptions([]byte(test.in), func(index int, key, _, _ []byte) Control {
+				act = append(act, index)
+				if string(key) == test.skip {
+					return ControlSkip
+				}
+				return ControlContinue
+			})
+			if fmt.Sprint(act) != fmt.Sprint(test.exp) {
+				t.Errorf("unexpected indexes: %v; want %v", act, test.exp)
+			}
+		})
+	}
+}
+
 func BenchmarkParameters(b *testing.B) {
 	for _, bench := range parametersCases {
 		b.Run(bench.label, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				_ = ScanOptions(bench.in, func(_ int, _, _, _ []byte) Control { return ControlContinue })
+				_ = bench.s.ScanOptions(bench.in, func(_ int, _, _, _ []byte) Control { return ControlContinue })
 			}
 		})
 	}
@@ -332,6 +941,30 @@ var selectOptionsCases = []struct {
 		},
 		ok: true,
 	},
+	{
+		label: "unique_fold",
+		selector: OptionSelector{
+			Flags: SelectUnique | SelectFold,
+		},
+		in: []byte(`Gzip;q=1,gzip,Chunked,chunked`),
+		exp: []Option{
+			NewOption("Gzip", map[string]string{"q": "1"}),
+			NewOption("Chunked", nil),
+		},
+		ok: true,
+	},
+	{
+		label: "unique_no_params",
+		selector: OptionSelector{
+			Flags: SelectUnique,
+		},
+		in: []byte(`foo,foo,bar`),
+		exp: []Option{
+			NewOption("foo", nil),
+			NewOption("bar", nil),
+		},
+		ok: true,
+	},
 	{
 		label: "multiparam_heap",
 		selector: OptionSelector{
@@ -358,6 +991,120 @@ var selectOptionsCases = []struct {
 	},
 }
 
+func TestParseOptionsFlags(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		flags ParseFlag
+		exp   []Option
+		ok    bool
+	}{
+		{
+			in: `foo;a=1,bar,foo;a=2;b=3`,
+			exp: []Option{
+				NewOption("foo", map[string]string{"a": "1"}),
+				NewOption("bar", nil),
+				NewOption("foo", map[string]string{"a": "2", "b": "3"}),
+			},
+			ok: true,
+		},
+		{
+			in:    `foo;a=1,bar,foo;a=2;b=3,bar,baz`,
+			flags: ParseKeepFirst,
+			exp: []Option{
+				NewOption("foo", map[string]string{"a": "1"}),
+				NewOption("bar", nil),
+				NewOption("baz", nil),
+			},
+			ok: true,
+		},
+		{
+			in:    `foo;a=1,bar,foo;a=2;b=3`,
+			flags: ParseKeepLast,
+			exp: []Option{
+				NewOption("foo", map[string]string{"a": "2", "b": "3"}),
+				NewOption("bar", nil),
+			},
+			ok: true,
+		},
+		{
+			in:    `foo;a=1,bar,foo;b=3`,
+			flags: ParseMerge,
+			exp: []Option{
+				NewOption("foo", map[string]string{"a": "1", "b": "3"}),
+				NewOption("bar", nil),
+			},
+			ok: true,
+		},
+		{
+			in:    `foo;a=1,bar,foo;b=3`,
+			flags: ParseRejectDuplicates,
+			exp: []Option{
+				NewOption("foo", map[string]string{"a": "1"}),
+				NewOption("bar", nil),
+			},
+			ok: false,
+		},
+		{
+			in:    `Foo;A=1,bar,FOO;b=2`,
+			flags: ParseLowercase | ParseMerge,
+			exp: []Option{
+				NewOption("foo", map[string]string{"a": "1", "b": "2"}),
+				NewOption("bar", nil),
+			},
+			ok: true,
+		},
+		{
+			in:    `Foo;A=1,bar,FOO;b=2`,
+			flags: ParseMerge,
+			exp: []Option{
+				NewOption("Foo", map[string]string{"A": "1"}),
+				NewOption("bar", nil),
+				NewOption("FOO", map[string]string{"b": "2"}),
+			},
+			ok: true,
+		},
+		{
+			in:    `foo;a=1,bar`,
+			flags: ParseRejectDuplicates,
+			exp: []Option{
+				NewOption("foo", map[string]string{"a": "1"}),
+				NewOption("bar", nil),
+			},
+			ok: true,
+		},
+	} {
+		t.Run(test.in+test.flags.String(), func(t *testing.T) {
+			act, ok := ParseOptionsFlags([]byte(test.in), nil, test.flags)
+			if ok != test.ok {
+				t.Errorf("ParseOptionsFlags(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			}
+			if !optionsEqual(act, test.exp) {
+				t.Errorf("ParseOptionsFlags(%q) = %v; want %v", test.in, act, test.exp)
+			}
+		})
+	}
+}
+
+func TestParseOptionsLowercase(t *testing.T) {
+	data := []byte(`foo;Bar=Baz,QUX`)
+	opts, ok := ParseOptionsFlags(data, nil, ParseLowercase)
+	if !ok || len(opts) != 2 {
+		t.Fatalf("unexpected result: %v %v", opts, ok)
+	}
+	if string(data) != `foo;Bar=Baz,QUX` {
+		t.Errorf("input data was modified: %q", data)
+	}
+	if &opts[0].Name[0] != &data[0] {
+		t.Errorf("lower case name was copied")
+	}
+	if v, _ := opts[0].Parameters.Get("bar"); string(v) != "Baz" {
+		t.Errorf("unexpected parameter value: %q; want %q", v, "Baz")
+	}
+	if string(opts[1].Name) != "qux" {
+		t.Errorf("unexpected name: %q; want %q", opts[1].Name, "qux")
+	}
+}
+
 func TestSelectOptions(t *testing.T) {
 	for _, test := range selectOptionsCases {
 		t.Run(test.label+test.selector.Flags.String(), func(t *testing.T) {
@@ -372,6 +1119,76 @@ func TestSelectOptions(t *testing.T) {
 	}
 }
 
+func TestSelectOptionsReject(t *testing.T) {
+	for _, test := range []struct {
+		label    string
+		selector OptionSelector
+		in       []byte
+		exp      []Option
+		rejected []string
+	}{
+		{
+			label: "check",
+			selector: OptionSelector{
+				Check: func(opt Option) bool {
+					return string(opt.Name) != "bar"
+				},
+			},
+			in:       []byte(`foo,bar;a=1,baz`),
+			exp:      []Option{NewOption("foo", nil), NewOption("baz", nil)},
+			rejected: []string{"bar:check"},
+		},
+		{
+			label: "unique",
+			selector: OptionSelector{
+				Flags: SelectUnique,
+			},
+			in:       []byte(`foo,foo;a=1,bar,foo`),
+			exp:      []Option{NewOption("foo", nil), NewOption("bar", nil)},
+			rejected: []string{"foo:duplicate", "foo:duplicate"},
+		},
+		{
+			label: "limit",
+			selector: OptionSelector{
+				Limit: 2,
+			},
+			in:       []byte(`foo,bar,baz;a=1,qux`),
+			exp:      []Option{NewOption("foo", nil), NewOption("bar", nil)},
+			rejected: []string{"baz:limit", "qux:limit"},
+		},
+		{
+			label: "limit_check",
+			selector: OptionSelector{
+				Limit: 1,
+				Check: func(opt Option) bool {
+					return string(opt.Name) != "foo"
+				},
+			},
+			in:       []byte(`foo,bar,baz`),
+			exp:      []Option{NewOption("bar", nil)},
+			rejected: []string{"foo:check", "baz:limit"},
+		},
+	} {
+		t.Run(test.label, func(t *testing.T) {
+			var rejected []string
+			s := test.selector
+			s.Reject = func(opt Option, reason RejectReason) {
+				rejected = append(rejected, string(opt.Name)+":"+reason.String())
+			}
+			act, ok := s.Select(test.in, nil)
+			if !ok {
+				t.Fatalf("Select(%q) wellformed sign is false; want true", test.in)
+			}
+			if !optionsEqual(act, test.exp) {
+				t.Errorf("Select(%q) = %v; want %v", test.in, act, test.exp)
+			}
+			if !reflect.DeepEqual(rejected, test.rejected) {
+				t.Errorf("Select(%q) rejected %v; want %v", test.in, rejected, test.rejected)
+			}
+		})
+	}
+}
+
 func BenchmarkSelectOptions(b *testing.B) {
 	for _, test := range selectOptionsCases {
 		s := test.selector
@@ -445,6 +1262,53 @@ func TestOptionCopy(t *testing.T) {
 	}
 }
 
+func TestOptionsString(t *testing.T) {
+	foo := Option{Name: []byte("foo")}
+	foo.Parameters.Set([]byte("a"), []byte("1"))
+	foo.Parameters.Set([]byte("b"), []byte("x y"))
+	opts := Options{foo, NewOption("bar", nil)}
+	exp := `foo;a=1;b="x y",bar`
+	if act := opts.String(); act != exp {
+		t.Errorf("String() = %q; want %q", act, exp)
+	}
+	if act := opts.AppendTo([]byte("X: ")); string(act) != "X: "+exp {
+		t.Errorf("AppendTo() = %q; want %q", act, "X: "+exp)
+	}
+	// Check that String() output could be parsed back.
+	act, ok := ParseOptionsString(opts.String(), nil)
+	if !ok || !optionsEqual(act, opts) {
+		t.Errorf("ParseOptionsString(%q) = %v, %v; want %v", opts.String(), act, ok, opts)
+	}
+}
+
+func TestForEachErr(t *testing.T) {
+	errStop := errors.New("stop")
+	opts := Options{
+		NewOption("foo", nil),
+		NewOption("bar", map[string]string{"a": "1"}),
+		NewOption("baz", nil),
+	}
+	var names []string
+	err := opts.ForEachErr(func(opt Option) error {
+		names = append(names, string(opt.Name))
+		return opt.Parameters.ForEachErr(func(k, v []byte) error {
+			if string(k) == "a" {
+				return errStop
+			}
+			return nil
+		})
+	})
+	if err != errStop {
+		t.Errorf("ForEachErr() = %v; want %v", err, errStop)
+	}
+	if exp := []string{"foo", "bar"}; !reflect.DeepEqual(names, exp) {
+		t.Errorf("ForEachErr() visited %v; want %v", names, exp)
+	}
+	if err := opts.ForEachErr(func(Option) error { return nil }); err != nil {
+		t.Errorf("ForEachErr() = %v; want nil", err)
+	}
+}
+
 func memset(dst []byte, v byte) {
 	copy(dst, bytes.Repeat([]byte{v}, len(dst)))
 }
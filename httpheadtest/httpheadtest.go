@@ -0,0 +1,229 @@
+// Package httpheadtest provides test corpora of the httphead package and
+// helpers to run them against other parsers.
+//
+// It is intended for libraries which wrap httphead and want to verify that
+// they preserve its parsing semantics.
+package httpheadtest
+
+import (
+	"testing"
+
+	"github.com/gobwas/httphead"
+	"github.com/gobwas/httphead/internal/corpus"
+)
+
+// TokensCase describes single case of comma separated tokens list parsing.
+type TokensCase struct {
+	Label string
+	In    string
+	OK    bool
+	Exp   []string
+}
+
+// OptionsCase describes single case of options list parsing.
+type OptionsCase struct {
+	Label string
+	In    string
+	OK    bool
+	Exp   []httphead.Option
+}
+
+// CookieCase describes single case of cookie header parsing.
+type CookieCase struct {
+	Label string
+	In    string
+	OK    bool
+	Exp   []Cookie
+}
+
+// Cookie represents cookie name and value pair.
+type Cookie struct {
+	Name, Value string
+}
+
+// QuotedStringCase describes single case of quoted-string parsing. Out is
+// the unescaped content of quoted-string.
+type QuotedStringCase struct {
+	Label string
+	In    string
+	OK    bool
+	Out   string
+}
+
+// TokensCases contains cases of comma separated tokens list parsing, as
+// httphead.ScanTokens() does it.
+var TokensCases = tokensCases(corpus.Tokens)
+
+// OptionsCases contains cases of options list parsing, as
+// httphead.ParseOptions() does it.
+var OptionsCases = optionsCases(corpus.Options)
+
+// CookieCases contains cases of cookie header parsing, as httphead.ScanCookie()
+// does it.
+var CookieCases = cookieCases(corpus.Cookies)
+
+// QuotedStringCases contains cases of quoted-string parsing, as
+// httphead.Scanner does it.
+var QuotedStringCases = quotedStringCases(corpus.QuotedStrings)
+
+// AdversarialInputs contains inputs which parsers must handle without
+// panics, infinite loops or excessive resource consumption. Results of
+// parsing are not specified.
+var AdversarialInputs = append([]string(nil), corpus.Adversarial...)
+
+// Note that corpora are shared with the httphead package tests, thus they are
+// converted here instead of being copied.
+
+func tokensCases(cs []corpus.TokensCase) []TokensCase {
+	ret := make([]TokensCase, len(cs))
+	for i, c := range cs {
+		ret[i] = TokensCase(c)
+	}
+	return ret
+}
+
+func optionsCases(cs []corpus.OptionsCase) []OptionsCase {
+	ret := make([]OptionsCase, len(cs))
+	for i, c := range cs {
+		ret[i] = OptionsCase{
+			Label: c.Label,
+			In:    c.In,
+			OK:    c.OK,
+		}
+		for _, opt := range c.Exp {
+			ret[i].Exp = append(ret[i].Exp, httphead.NewOption(opt.Name, opt.Params))
+		}
+	}
+	return ret
+}
+
+func cookieCases(cs []corpus.CookieCase) []CookieCase {
+	ret := make([]CookieCase, len(cs))
+	for i, c := range cs {
+		ret[i] = CookieCase{
+			Label: c.Label,
+			In:    c.In,
+			OK:    c.OK,
+		}
+		for _, cookie := range c.Exp {
+			ret[i].Exp = append(ret[i].Exp, Cookie(cookie))
+		}
+	}
+	return ret
+}
+
+func quotedStringCases(cs []corpus.QuotedStringCase) []QuotedStringCase {
+	ret := make([]QuotedStringCase, len(cs))
+	for i, c := range cs {
+		ret[i] = QuotedStringCase(c)
+	}
+	return ret
+}
+
+// RunTokens runs TokensCases against scan function as subtests of t.
+func RunTokens(t *testing.T, scan func(data []byte) ([]string, bool)) {
+	for _, test := range TokensCases {
+		test := test
+		t.Run(test.Label, func(t *testing.T) {
+			act, ok := scan([]byte(test.In))
+			if ok != test.OK {
+				t.Errorf("scan(%q) wellformed sign is %v; want %v", test.In, ok, test.OK)
+			}
+			if !equalStrings(act, test.Exp) {
+				t.Errorf("scan(%q) = %q; want %q", test.In, act, test.Exp)
+			}
+		})
+	}
+}
+
+// RunOptions runs OptionsCases against parse function as subtests of t.
+// Options are compared only for wellformed cases.
+func RunOptions(t *testing.T, parse func(data []byte) ([]httphead.Option, bool)) {
+	for _, test := range OptionsCases {
+		test := test
+		t.Run(test.Label, func(t *testing.T) {
+			act, ok := parse([]byte(test.In))
+			if ok != test.OK {
+				t.Errorf("parse(%q) wellformed sign is %v; want %v", test.In, ok, test.OK)
+			}
+			if !ok || !test.OK {
+				return
+			}
+			if len(act) != len(test.Exp) {
+				t.Fatalf("parse(%q) = %v; want %v", test.In, act, test.Exp)
+			}
+			for i := range act {
+				if !act[i].Equal(test.Exp[i]) {
+					t.Errorf("parse(%q) = %v; want %v", test.In, act, test.Exp)
+					break
+				}
+			}
+		})
+	}
+}
+
+// RunCookies runs CookieCases against scan function as subtests of t.
+func RunCookies(t *testing.T, scan func(data []byte) ([]Cookie, bool)) {
+	for _, test := range CookieCases {
+		test := test
+		t.Run(test.Label, func(t *testing.T) {
+			act, ok := scan([]byte(test.In))
+			if ok != test.OK {
+				t.Errorf("scan(%q) wellformed sign is %v; want %v", test.In, ok, test.OK)
+			}
+			if len(act) != len(test.Exp) {
+				t.Fatalf("scan(%q) = %q; want %q", test.In, act, test.Exp)
+			}
+			for i := range act {
+				if act[i] != test.Exp[i] {
+					t.Errorf("scan(%q) = %q; want %q", test.In, act, test.Exp)
+					break
+				}
+			}
+		})
+	}
+}
+
+// RunQuotedStrings runs QuotedStringCases against read function as subtests
+// of t.
+func RunQuotedStrings(t *testing.T, read func(data []byte) (string, bool)) {
+	for _, test := range QuotedStringCases {
+		test := test
+		t.Run(test.Label, func(t *testing.T) {
+			act, ok := read([]byte(test.In))
+			if ok != test.OK {
+				t.Errorf("read(%q) wellformed sign is %v; want %v", test.In, ok, test.OK)
+			}
+			if ok && act != test.Out {
+				t.Errorf("read(%q) = %q; want %q", test.In, act, test.Out)
+			}
+		})
+	}
+}
+
+// RunAdversarial calls parse with each of AdversarialInputs and reports
+// panics as test failures.
+func RunAdversarial(t *testing.T, parse func(data []byte)) {
+	for i, in := range AdversarialInputs {
+		func() {
+			defer func() {
+				if err := recover(); err != nil {
+					t.Errorf("#%d: parse(%q) panicked: %v", i, in, err)
+				}
+			}()
+			parse([]byte(in))
+		}()
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package httpheadtest
+
+import (
+	"testing"
+
+	"github.com/gobwas/httphead"
+)
+
+func TestTokens(t *testing.T) {
+	RunTokens(t, func(data []byte) (ret []string, ok bool) {
+		ok = httphead.ScanTokens(data, func(v []byte) bool {
+			ret = append(ret, string(v))
+			return true
+		})
+		return ret, ok
+	})
+}
+
+func TestOptions(t *testing.T) {
+	RunOptions(t, func(data []byte) ([]httphead.Option, bool) {
+		return httphead.ParseOptions(data, nil)
+	})
+}
+
+func TestCookies(t *testing.T) {
+	RunCookies(t, func(data []byte) (ret []Cookie, ok bool) {
+		ok = httphead.ScanCookie(data, func(name, value []byte) bool {
+			ret = append(ret, Cookie{string(name), string(value)})
+			return true
+		})
+		return ret, ok
+	})
+}
+
+func TestQuotedStrings(t *testing.T) {
+	RunQuotedStrings(t, func(data []byte) (string, bool) {
+		s := httphead.NewScanner(data)
+		if !s.Next() || s.Type() != httphead.ItemString {
+			return "", false
+		}
+		return string(s.Bytes()), true
+	})
+}
+
+func TestAdversarial(t *testing.T) {
+	RunAdversarial(t, func(data []byte) {
+		httphead.ScanTokens(data, func([]byte) bool { return true })
+		httphead.ParseOptions(data, nil)
+		httphead.ScanCookie(data, func(_, _ []byte) bool { return true })
+		httphead.Validate(data, httphead.GrammarOptions)
+		httphead.Normalize(nil, data, 0)
+		httphead.ParsePrefer(data, nil)
+		httphead.ParseExpect(data, nil)
+	})
+}
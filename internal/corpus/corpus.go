@@ -0,0 +1,212 @@
+// Package corpus contains test corpora shared by the httphead package tests
+// and the httpheadtest package.
+package corpus
+
+import "bytes"
+
+// TokensCase describes single case of comma separated tokens list parsing.
+type TokensCase struct {
+	Label string
+	In    string
+	OK    bool
+	Exp   []string
+}
+
+// OptionsCase describes single case of options list parsing.
+type OptionsCase struct {
+	Label string
+	In    string
+	OK    bool
+	Exp   []Option
+}
+
+// Option represents expected option name and parameters.
+type Option struct {
+	Name   string
+	Params map[string]string
+}
+
+// CookieCase describes single case of cookie header parsing.
+type CookieCase struct {
+	Label string
+	In    string
+	OK    bool
+	Exp   []Cookie
+}
+
+// Cookie represents cookie name and value pair.
+type Cookie struct {
+	Name, Value string
+}
+
+// QuotedStringCase describes single case of quoted-string parsing. Out is
+// the unescaped content of quoted-string.
+type QuotedStringCase struct {
+	Label string
+	In    string
+	OK    bool
+	Out   string
+}
+
+// Tokens contains cases of comma separated tokens list parsing, as
+// httphead.ScanTokens() does it.
+var Tokens = []TokensCase{
+	{"simple", `a,b,c`, true, []string{"a", "b", "c"}},
+	{"spaces", ` a , b ,c `, true, []string{"a", "b", "c"}},
+	{"empty_elements", `a,,b, ,c`, true, []string{"a", "b", "c"}},
+	{"separator", `a,b;c`, false, []string{"a", "b"}},
+	{"quoted", `a,"b"`, false, []string{"a"}},
+	{"empty", ``, false, nil},
+	{"only_commas", `,,`, false, nil},
+}
+
+// Options contains cases of options list parsing, as httphead.ParseOptions()
+// does it.
+var Options = []OptionsCase{
+	{
+		Label: "simple",
+		In:    `foo;bar=1,baz`,
+		OK:    true,
+		Exp: []Option{
+			{"foo", map[string]string{"bar": "1"}},
+			{"baz", nil},
+		},
+	},
+	{
+		Label: "quoted",
+		In:    `foo;bar="a \"b\", c"`,
+		OK:    true,
+		Exp: []Option{
+			{"foo", map[string]string{"bar": `a "b", c`}},
+		},
+	},
+	{
+		Label: "spaces",
+		In:    ` foo ; bar = 1 ,  baz `,
+		OK:    true,
+		Exp: []Option{
+			{"foo", map[string]string{"bar": "1"}},
+			{"baz", nil},
+		},
+	},
+	{
+		Label: "duplicates",
+		In:    `foo;a=1,foo;a=2`,
+		OK:    true,
+		Exp: []Option{
+			{"foo", map[string]string{"a": "1"}},
+			{"foo", map[string]string{"a": "2"}},
+		},
+	},
+	{
+		Label: "empty_elements",
+		In:    `foo,, ,bar`,
+		OK:    true,
+		Exp: []Option{
+			{"foo", nil},
+			{"bar", nil},
+		},
+	},
+	{
+		Label: "missing_param_name",
+		In:    `foo;=1`,
+		OK:    false,
+	},
+	{
+		Label: "missing_value",
+		In:    `foo;a=,bar`,
+		OK:    false,
+	},
+	{
+		Label: "unterminated_quote",
+		In:    `foo;a="1`,
+		OK:    false,
+	},
+	{
+		Label: "empty",
+		In:    ``,
+		OK:    false,
+	},
+}
+
+// Cookies contains cases of cookie header parsing, as httphead.ScanCookie()
+// does it.
+var Cookies = []CookieCase{
+	{"simple", `foo=bar`, true, []Cookie{{"foo", "bar"}}},
+	{"multiple", `foo=bar; bar=baz`, true, []Cookie{{"foo", "bar"}, {"bar", "baz"}}},
+	{"quoted", `foo="bar"`, true, []Cookie{{"foo", "bar"}}},
+	{"empty_value", `foo=; bar=baz`, true, []Cookie{{"foo", ""}, {"bar", "baz"}}},
+	{"no_space", `foo=bar;bar=baz`, true, []Cookie{{"foo", "bar"}, {"bar", "baz"}}},
+	{"invalid_name", `f@o=1; bar=baz`, true, []Cookie{{"bar", "baz"}}},
+	{"invalid_value", `foo="1; bar=baz`, true, []Cookie{{"bar", "baz"}}},
+}
+
+// QuotedStrings contains cases of quoted-string parsing, as httphead.Scanner
+// does it.
+var QuotedStrings = []QuotedStringCase{
+	{"simple", `"foo"`, true, "foo"},
+	{"empty", `""`, true, ""},
+	{"escaped_quote", `"a\"b"`, true, `a"b`},
+	{"escaped_char", `"a\bc"`, true, "abc"},
+	{"unterminated", `"foo`, false, ""},
+	{"unterminated_escape", `"foo\"`, false, ""},
+}
+
+// Adversarial contains inputs which parsers must handle without panics,
+// infinite loops or excessive resource consumption.
+var Adversarial = []string{
+	"",
+	",",
+	";",
+	"=",
+	`"`,
+	`\`,
+	"(",
+	")",
+	string(bytes.Repeat([]byte("("), 1024)),
+	string(bytes.Repeat([]byte(`\`), 1024)),
+	string(bytes.Repeat([]byte(`"`), 1023)),
+	string(bytes.Repeat([]byte("a;"), 1024)),
+	string(bytes.Repeat([]byte(","), 4096)),
+	"a;b=\"\\",
+	"a\r\n b",
+	"a\r\nSet-Cookie: x=y",
+	"\x00\x01\x7f\xff",
+	"a;b=\xff\xfe",
+	";a",
+	"; a=1",
+	",;x",
+	"Basic",
+	"Basic ",
+	"Basic foo bar",
+	"Digest realm=",
+	`Bearer a, realm="x`,
+	"for=",
+	`for="[`,
+	`for="[::1`,
+	"[::ffff:1.2.3.4",
+	"1.2.3.4:",
+	"W/",
+	`W/"`,
+	"bytes=-",
+	"bytes=1-0,-",
+	"=?",
+	"?1;",
+	":",
+	":@@:",
+	"@",
+	"%",
+	"1.",
+	"-",
+	string(bytes.Repeat([]byte("["), 1024)),
+	string(bytes.Repeat([]byte("{\"a\":"), 1024)),
+	`{"report_to":"a","max_age":1e999}`,
+	`{"a":"\u`,
+	"max-age=99999999999999999999999",
+	"q=1.0000",
+	"a;q=",
+	"*/",
+	"/*",
+	"a/b;",
+	"*;q=0",
+}
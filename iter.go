@@ -0,0 +1,99 @@
+//go:build go1.23
+
+package httphead
+
+import "iter"
+
+// Tokens returns an iterator over tokens of data, as ScanTokens() scans them:
+//
+//	for token := range httphead.Tokens(data) {
+//		if token == nil {
+//			// Data is malformed.
+//		}
+//	}
+//
+// If data is malformed, iteration ends with nil token. Note that well-formed
+// tokens are never nil.
+func Tokens(data []byte) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		var stop bool
+		ok := ScanTokens(data, func(v []byte) bool {
+			stop = !yield(v)
+			return !stop
+		})
+		if !ok && !stop {
+			yield(nil)
+		}
+	}
+}
+
+// OptionsSeq returns an iterator over options of data, as ScanOptions() scans
+// them, with their indexes:
+//
+//	for i, opt := range httphead.OptionsSeq(data) {
+//		if i == -1 {
+//			// Data is malformed.
+//		}
+//	}
+//
+// Yielded options consist of subslices of data. If data is malformed,
+// iteration ends with index -1 and empty Option. Note that options which were
+// completely scanned before malformed part of data are still yielded.
+func OptionsSeq(data []byte) iter.Seq2[int, Option] {
+	return func(yield func(int, Option) bool) {
+		var (
+			current Option
+			has     bool
+			stop    bool
+		)
+		index := -1
+		ok := ScanOptions(data, func(idx int, name, attr, val []byte) Control {
+			if idx != index {
+				if has && !yield(index, current) {
+					stop = true
+					return ControlBreak
+				}
+				index = idx
+				current = Option{Name: name}
+				has = true
+			}
+			if attr != nil {
+				current.Parameters.Set(attr, val)
+			}
+			return ControlContinue
+		})
+		if stop {
+			return
+		}
+		if !ok {
+			yield(-1, Option{})
+			return
+		}
+		if has {
+			yield(index, current)
+		}
+	}
+}
+
+// Cookies returns an iterator over cookie name-value pairs of data, as
+// ScanCookie() scans them:
+//
+//	for name, value := range httphead.Cookies(data) {
+//		if name == nil {
+//			// Data is malformed.
+//		}
+//	}
+//
+// If data is malformed, iteration ends with nil name and value.
+func Cookies(data []byte) iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		var stop bool
+		ok := ScanCookie(data, func(key, value []byte) bool {
+			stop = !yield(key, value)
+			return !stop
+		})
+		if !ok && !stop {
+			yield(nil, nil)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build go1.23
+
+package httphead
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+	}{
+		{`foo, bar`, []string{"foo", "bar"}},
+		{`foo, "bar"`, []string{"foo", "<nil>"}},
+		{``, []string{"<nil>"}},
+	} {
+		var act []string
+		for token := range Tokens([]byte(test.in)) {
+			if token == nil {
+				act = append(act, "<nil>")
+				continue
+			}
+			act = append(act, string(token))
+		}
+		if fmt.Sprint(act) != fmt.Sprint(test.exp) {
+			t.Errorf("Tokens(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+	for range Tokens([]byte(`foo, bar`)) {
+		break
+	}
+}
+
+func TestOptionsSeq(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+	}{
+		{`foo;a=1, bar, foo;b=2`, []string{"0:{foo [a:1]}", "1:{bar []}", "2:{foo [b:2]}"}},
+		{`foo;a=1, bar, baz;=`, []string{"0:{foo [a:1]}", "-1:{ []}"}},
+	} {
+		var act []string
+		for i, opt := range OptionsSeq([]byte(test.in)) {
+			act = append(act, fmt.Sprintf("%d:%s", i, opt))
+		}
+		if fmt.Sprint(act) != fmt.Sprint(test.exp) {
+			t.Errorf("OptionsSeq(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+	var n int
+	for range OptionsSeq([]byte(`foo, bar, baz`)) {
+		if n++; n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("unexpected number of iterations: %d", n)
+	}
+}
+
+func TestCookies(t *testing.T) {
+	defer func(n int) { MaxValueLength = n }(MaxValueLength)
+	var act []string
+	for name, value := range Cookies([]byte(`foo=bar; baz="qux"`)) {
+		if name == nil {
+			act = append(act, "<nil>")
+			continue
+		}
+		act = append(act, string(name)+"="+string(value))
+	}
+	if exp := []string{"foo=bar", "baz=qux"}; fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("Cookies() = %q; want %q", act, exp)
+	}
+
+	MaxValueLength = 8
+	var n int
+	for name := range Cookies([]byte(`foo=bar; baz="qux"`)) {
+		if n++; name != nil {
+			t.Errorf("Cookies() yielded %q; want nil", name)
+		}
+	}
+	if n != 1 {
+		t.Errorf("unexpected number of iterations: %d; want 1", n)
+	}
+}
@@ -0,0 +1,217 @@
+package httphead
+
+import "bytes"
+
+// ValidLanguageTag reports whether p is a well-formed language tag:
+//
+// Language-Tag = langtag / privateuse / grandfathered
+// langtag      = language [ "-" script ] [ "-" region ] rest
+// rest         = *( "-" variant ) *( "-" extension ) [ "-" privateuse ]
+// language     = 2*3ALPHA [ "-" extlang ] / 4ALPHA / 5*8ALPHA
+// extlang      = 3ALPHA *2( "-" 3ALPHA )
+// script       = 4ALPHA
+// region       = 2ALPHA / 3DIGIT
+// variant      = 5*8alphanum / ( DIGIT 3alphanum )
+// extension    = singleton 1*( "-" ( 2*8alphanum ) )
+// privateuse   = "x" 1*( "-" ( 1*8alphanum ) )
+//
+// Note that it does not check subtags against IANA Language Subtag Registry,
+// neither it checks for duplicate variants or extensions.
+// See https://tools.ietf.org/html/rfc5646#section-2.1
+func ValidLanguageTag(p []byte) bool {
+	if isIrregularLanguageTag(p) {
+		return true
+	}
+	const (
+		slotExtlang = iota
+		slotScript
+		slotRegion
+		slotVariant
+		slotExtension
+	)
+	var (
+		slot    int
+		extlang int
+		ext     int // Number of subtags of the current extension.
+	)
+	for i := 0; len(p) > 0 || i == 0; i++ {
+		sub := p
+		if j := bytes.IndexByte(p, '-'); j != -1 {
+			sub, p = p[:j], p[j+1:]
+			if len(p) == 0 {
+				// Trailing "-".
+				return false
+			}
+		} else {
+			p = nil
+		}
+		n := len(sub)
+		if n == 0 || n > 8 || !isAlphaNums(sub) {
+			return false
+		}
+		if n == 1 && sub[0]|toLower == 'x' {
+			return len(p) > 0 && isPrivateUse(p)
+		}
+		if i == 0 {
+			if !isAlphas(sub) || n == 1 {
+				return false
+			}
+			if n > 3 {
+				slot = slotScript
+			}
+			continue
+		}
+		switch {
+		case n == 1:
+			if slot == slotExtension && ext == 0 {
+				return false
+			}
+			slot, ext = slotExtension, 0
+
+		case slot == slotExtension:
+			ext++
+
+		case slot == slotExtlang && n == 3 && isAlphas(sub) && extlang < 3:
+			extlang++
+
+		case slot <= slotScript && n == 4 && isAlphas(sub):
+			slot = slotRegion
+
+		case slot <= slotRegion && (n == 2 && isAlphas(sub) || n == 3 && isDigits(sub)):
+			slot = slotVariant
+
+		case n >= 5 || n == 4 && isDigit(sub[0]):
+			slot = slotVariant
+
+		default:
+			return false
+		}
+	}
+	return slot != slotExtension || ext > 0
+}
+
+// CanonicalLanguageTag returns language tag in canonical case: two letter
+// subtags (regions) are upper case, four letter subtags (scripts) are title
+// case and other subtags are lower case. Subtags after a singleton, including
+// private use ones, are always lower case, as well as the first subtag. That
+// is, "EN-latn-us" becomes "en-Latn-US".
+//
+// It returns p itself if it is already in canonical case. Otherwise it returns
+// a copy. Note that it does not check tag for well-formedness.
+// See https://tools.ietf.org/html/rfc5646#section-2.1.1
+func CanonicalLanguageTag(p []byte) []byte {
+	var (
+		ret       = p
+		copied    bool
+		singleton bool
+	)
+	for i := 0; i < len(p); {
+		j := bytes.IndexByte(p[i:], '-')
+		if j == -1 {
+			j = len(p)
+		} else {
+			j += i
+		}
+		sub := p[i:j]
+		for k, c := range sub {
+			lo := lowercase[c]
+			want := lo
+			if i > 0 && !singleton && (len(sub) == 2 || len(sub) == 4 && k == 0) && 'a' <= lo && lo <= 'z' {
+				want = lo &^ toLower
+			}
+			if want != c {
+				if !copied {
+					ret = make([]byte, len(p))
+					copy(ret, p)
+					copied = true
+				}
+				ret[i+k] = want
+			}
+		}
+		if len(sub) == 1 {
+			singleton = true
+		}
+		i = j + 1
+	}
+	return ret
+}
+
+var irregularLanguageTags = []string{
+	"en-GB-oed",
+	"i-ami",
+	"i-bnn",
+	"i-default",
+	"i-enochian",
+	"i-hak",
+	"i-klingon",
+	"i-lux",
+	"i-mingo",
+	"i-navajo",
+	"i-pwn",
+	"i-tao",
+	"i-tay",
+	"i-tsu",
+	"sgn-BE-FR",
+	"sgn-BE-NL",
+	"sgn-CH-DE",
+}
+
+// isIrregularLanguageTag reports whether p is one of grandfathered tags which
+// do not match the langtag grammar.
+func isIrregularLanguageTag(p []byte) bool {
+	for _, tag := range irregularLanguageTags {
+		if equalFoldString(p, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPrivateUse(p []byte) bool {
+	for len(p) > 0 {
+		sub := p
+		if j := bytes.IndexByte(p, '-'); j != -1 {
+			sub, p = p[:j], p[j+1:]
+			if len(p) == 0 {
+				return false
+			}
+		} else {
+			p = nil
+		}
+		if len(sub) == 0 || len(sub) > 8 || !isAlphaNums(sub) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphaNums(p []byte) bool {
+	for _, c := range p {
+		if !isAlphaNum(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphas(p []byte) bool {
+	for _, c := range p {
+		if !isAlpha(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(p []byte) bool {
+	for _, c := range p {
+		if !isDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,78 @@
+package httphead
+
+import "testing"
+
+func TestValidLanguageTag(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp bool
+	}{
+		{"de", true},
+		{"zh-Hant", true},
+		{"zh-cmn-Hans-CN", true},
+		{"zh-yue-HK", true},
+		{"sr-Latn-RS", true},
+		{"es-419", true},
+		{"sl-rozaj-biske", true},
+		{"de-CH-1901", true},
+		{"hy-Latn-IT-arevela", true},
+		{"en-US-u-islamcal", true},
+		{"de-DE-u-co-phonebk", true},
+		{"en-a-bbb-x-a-ccc", true},
+		{"x-whatever", true},
+		{"qaa-Qaaa-QM-x-southern", true},
+		{"i-klingon", true},
+		{"EN-gb-OED", true},
+		{"zh-min-nan", true},
+
+		{"", false},
+		{"d", false},
+		{"i-foo", false},
+		{"de-419-DE", false},
+		{"a-DE", false},
+		{"ar-a-aaa-b-bbb-a", false},
+		{"en-a", false},
+		{"en-x", false},
+		{"en-US-", false},
+		{"en--US", false},
+		{"1en", false},
+		{"zh-aaa-bbb-ccc-ddd", false},
+		{"en-Latn-Latn", false},
+		{"en-US-GB", false},
+		{"abcdefghi", false},
+		{"x-abcdefghi", false},
+	} {
+		if act := ValidLanguageTag([]byte(test.in)); act != test.exp {
+			t.Errorf("ValidLanguageTag(%q) = %v; want %v", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestCanonicalLanguageTag(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+	}{
+		{"en-US", "en-US"},
+		{"EN-us", "en-US"},
+		{"ZH-hant-tw", "zh-Hant-TW"},
+		{"en-CA-x-CA", "en-CA-x-ca"},
+		{"sgn-be-fr", "sgn-BE-FR"},
+		{"AZ-latn-X-LATN", "az-Latn-x-latn"},
+		{"de-CH-1901", "de-CH-1901"},
+		{"en-US-U-Islamcal", "en-US-u-islamcal"},
+		{"", ""},
+	} {
+		in := []byte(test.in)
+		act := CanonicalLanguageTag(in)
+		if string(act) != test.exp {
+			t.Errorf("CanonicalLanguageTag(%q) = %q; want %q", test.in, act, test.exp)
+		}
+		if test.in == test.exp && len(in) > 0 && &act[0] != &in[0] {
+			t.Errorf("CanonicalLanguageTag(%q) copied canonical tag", test.in)
+		}
+		if string(in) != test.in {
+			t.Errorf("CanonicalLanguageTag(%q) modified input", test.in)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package httphead
 
 import (
 	"bytes"
+	"unicode/utf8"
 )
 
 // ItemType encodes type of the lexing token.
@@ -20,6 +21,10 @@ const (
 	ItemComment
 	// ItemOctet reports that token is octet slice.
 	ItemOctet
+	// ItemRaw reports that token is the rest of data taken as is.
+	ItemRaw
+	// ItemToken68 reports that token is RFC9110 token68.
+	ItemToken68
 )
 
 // Scanner represents header tokens scanner.
@@ -30,31 +35,115 @@ type Scanner struct {
 
 	itemType  ItemType
 	itemBytes []byte
+	start     int
 
-	err bool
+	flags ScanFlag
+	err   error
+	items int
+	buf   []byte
+
+	maxItems        int
+	maxTokenLength  int
+	maxCommentDepth int
+
+	peek   scanItem
+	peeked bool
+}
+
+// scanItem holds scanner state after the item fetched by PeekItem().
+type scanItem struct {
+	itemType  ItemType
+	itemBytes []byte
+	start     int
+	pos       int
+	items     int
+	err       error
+	ok        bool
 }
 
+// MaxValueLength is the maximum length of data which scanners accept. Longer
+// data is rejected with ErrLimitExceeded before scanning. Non-positive value
+// disables the limit.
+var MaxValueLength = 1 << 20
+
 // NewScanner creates new RFC2616 data scanner.
 func NewScanner(data []byte) *Scanner {
-	return &Scanner{data: data}
+	return newScanner(data, 0)
+}
+
+// NewScannerFlags creates new RFC2616 data scanner which respects given scan
+// flags.
+func NewScannerFlags(data []byte, flags ScanFlag) *Scanner {
+	return newScanner(data, flags)
+}
+
+func newScanner(data []byte, flags ScanFlag) *Scanner {
+	l := new(Scanner)
+	l.init(data, flags)
+	return l
+}
+
+// init prepares l for scanning data. It is used instead of newScanner() when
+// Scanner could be allocated on stack.
+func (l *Scanner) init(data []byte, flags ScanFlag) {
+	l.data = data
+	l.flags = flags
+	if exceedsLimit(data) {
+		l.err = ErrLimitExceeded
+		return
+	}
+	if flags&ScanUnfoldLWS != 0 && indexObsFold(data) != -1 {
+		l.data = UnfoldLWS(nil, data)
+		data = l.data
+	}
+	if flags&ScanRejectControl != 0 {
+		if i := indexControl(data); i != -1 {
+			l.fail(i, ErrHeaderInjection, "")
+			return
+		}
+	}
+	if flags&ScanRejectObsFold != 0 {
+		if i := indexObsFold(data); i != -1 {
+			l.fail(i, ErrMalformed, "")
+		}
+	}
 }
 
 // Next scans for next token. It returns true on successful scanning, and false
 // on error or EOF.
 func (l *Scanner) Next() bool {
+	if l.peeked {
+		l.peeked = false
+		l.itemType = l.peek.itemType
+		l.itemBytes = l.peek.itemBytes
+		l.start = l.peek.start
+		l.pos = l.peek.pos
+		l.items = l.peek.items
+		l.err = l.peek.err
+		return l.peek.ok
+	}
 	c, ok := l.nextChar()
 	if !ok {
 		return false
 	}
+	l.start = l.pos
+	if l.items++; l.maxItems > 0 && l.items > l.maxItems {
+		l.fail(l.pos, ErrLimitExceeded, "")
+		return false
+	}
 	switch c {
 	case '"': // quoted-string;
 		return l.fetchQuotedString()
 
 	case '(': // comment;
+		if l.flags&ScanNoComments != 0 {
+			l.fail(l.pos, ErrMalformed, "")
+			return false
+		}
 		return l.fetchComment()
 
 	case '\\', ')': // unexpected chars;
-		l.err = true
+		l.fail(l.pos, ErrMalformed, "")
 		return false
 
 	default:
@@ -62,6 +151,82 @@ func (l *Scanner) Next() bool {
 	}
 }
 
+// PeekItem scans next item without consuming it. That is, the following Next()
+// call returns the same item, while Type(), Bytes() and Err() still report
+// the current one. It returns ItemUndef and nil if there are no more items or
+// next item is malformed.
+//
+// Scanned item is cached until Next() call or until position is changed by
+// other methods, such as Advance() or Skip().
+func (l *Scanner) PeekItem() (ItemType, []byte) {
+	if !l.peeked {
+		var (
+			itemType  = l.itemType
+			itemBytes = l.itemBytes
+			start     = l.start
+			pos       = l.pos
+			items     = l.items
+			err       = l.err
+		)
+		ok := l.Next()
+		l.peek = scanItem{
+			itemType:  l.itemType,
+			itemBytes: l.itemBytes,
+			start:     l.start,
+			pos:       l.pos,
+			items:     l.items,
+			err:       l.err,
+			ok:        ok,
+		}
+		l.itemType = itemType
+		l.itemBytes = itemBytes
+		l.start = start
+		l.pos = pos
+		l.items = items
+		l.err = err
+		l.peeked = true
+	}
+	if !l.peek.ok {
+		return ItemUndef, nil
+	}
+	return l.peek.itemType, l.peek.itemBytes
+}
+
+// NextToken68 fetches ItemToken68 at current scanner position, such as
+// credentials of Basic or Bearer authentication schemes. Unlike Next(), it
+// treats trailing "=" signs as a part of the item. Token68 must be followed by
+// optional whitespace and either comma or end of data; that is, it does not
+// match auth-param such as "realm=example".
+//
+// It returns false and does not move if there is no token68 at current
+// position, such that caller could scan the data with Next() instead.
+// See ScanToken68().
+func (l *Scanner) NextToken68() bool {
+	if l.err != nil {
+		return false
+	}
+	pos := l.pos + SkipSpace(l.data[l.pos:])
+	n := ScanToken68(l.data[pos:])
+	if n == 0 {
+		return false
+	}
+	if q := l.data[pos+n:]; len(q) > 0 {
+		if q = q[SkipSpace(q):]; len(q) > 0 && q[0] != ',' {
+			return false
+		}
+	}
+	if l.items++; l.maxItems > 0 && l.items > l.maxItems {
+		l.fail(pos, ErrLimitExceeded, "")
+		return false
+	}
+	l.resetItem()
+	l.itemType = ItemToken68
+	l.itemBytes = l.data[pos : pos+n]
+	l.start = pos
+	l.pos = pos + n
+	return true
+}
+
 // FetchUntil fetches ItemOctet from current scanner position to first
 // occurence of the c or to the end of the underlying data.
 func (l *Scanner) FetchUntil(c byte) bool {
@@ -72,6 +237,35 @@ func (l *Scanner) FetchUntil(c byte) bool {
 	return l.fetchOctet(c)
 }
 
+// FetchRaw fetches ItemRaw from current scanner position to the end of the
+// underlying data, excluding surrounding whitespace. It is useful for header
+// values which do not follow token grammar, such as JSON values of Report-To
+// or NEL headers.
+//
+// Fetched bytes are only checked to be a valid RFC7230 field-value. That is,
+// it fails with ErrHeaderInjection if they contain control characters other
+// than HT.
+func (l *Scanner) FetchRaw() bool {
+	l.resetItem()
+	if l.err != nil {
+		return false
+	}
+	l.pos += SkipSpace(l.data[l.pos:])
+	if l.pos == len(l.data) {
+		return false
+	}
+	if i := indexControl(l.data[l.pos:]); i != -1 {
+		l.fail(l.pos+i, ErrHeaderInjection, "")
+		return false
+	}
+
+	l.itemType = ItemRaw
+	l.itemBytes = trimRight(l.data[l.pos:])
+	l.pos = len(l.data)
+
+	return true
+}
+
 // Peek reads byte at current position without advancing it. On end of data it
 // returns 0.
 func (l *Scanner) Peek() byte {
@@ -101,6 +295,7 @@ func (l *Scanner) Buffered() int {
 // Advance moves current position index at n bytes. It returns true on
 // successful move.
 func (l *Scanner) Advance(n int) bool {
+	l.peeked = false
 	l.pos += n
 	if l.pos > len(l.data) {
 		l.pos = len(l.data)
@@ -111,7 +306,7 @@ func (l *Scanner) Advance(n int) bool {
 
 // Skip skips all bytes until first occurence of c.
 func (l *Scanner) Skip(c byte) {
-	if l.err {
+	if l.err != nil {
 		return
 	}
 	// Reset scanner state.
@@ -127,18 +322,26 @@ func (l *Scanner) Skip(c byte) {
 
 // SkipEscaped skips all bytes until first occurence of non-escaped c.
 func (l *Scanner) SkipEscaped(c byte) {
-	if l.err {
-		return
+	l.skipEscaped(c)
+}
+
+// skipEscaped is the same as SkipEscaped() but also reports whether c was
+// found and skipped.
+func (l *Scanner) skipEscaped(c byte) bool {
+	if l.err != nil {
+		return false
 	}
 	// Reset scanner state.
 	l.resetItem()
 
-	if i := ScanUntil(l.data[l.pos:], c); i == -1 {
+	i := ScanUntil(l.data[l.pos:], c)
+	if i == -1 {
 		// Reached the end of data.
 		l.pos = len(l.data)
-	} else {
-		l.pos += i + 1
+		return false
 	}
+	l.pos += i + 1
+	return true
 }
 
 // Type reports current token type.
@@ -151,11 +354,58 @@ func (l *Scanner) Bytes() []byte {
 	return l.itemBytes
 }
 
+// Pos returns current position of the scanner in data. After successful Next()
+// call it is the end offset of the current item.
+func (l *Scanner) Pos() int {
+	return l.pos
+}
+
+// ItemOffset returns offset of the current item in data. That is, raw bytes of
+// the current item, including quotes of quoted-string or parentheses of
+// comment, are data[l.ItemOffset():l.Pos()].
+//
+// Note that if ScanUnfoldLWS flag is set, offsets are relative to the
+// unfolded copy of data.
+func (l *Scanner) ItemOffset() int {
+	return l.start
+}
+
+// SetBuffer makes scanner to append unescaped contents of quoted-strings and
+// comments to buf instead of allocating a new slice for each of them. Items
+// without escaped characters are still subslices of the scanned data. Note
+// that items could refer to buf after scanning, thus buf must not be reused
+// while they are in use. Nil buf disables buffering.
+func (l *Scanner) SetBuffer(buf []byte) {
+	l.buf = buf
+}
+
+// SetLimits limits scanning of untrusted data. The items is the maximum
+// number of items, including separators, which scanner fetches with Next().
+// The tokenLength is the maximum length of token or quoted-string item. The
+// commentDepth is the maximum nesting depth of comments, such that "(a (b))"
+// has depth of two. Scanner stops with ErrLimitExceeded when some of limits
+// is exceeded. Non-positive value disables the limit, which is the default.
+func (l *Scanner) SetLimits(items, tokenLength, commentDepth int) {
+	l.maxItems = items
+	l.maxTokenLength = tokenLength
+	l.maxCommentDepth = commentDepth
+}
+
+// Err returns an error which caused scanner to stop, if any. It returns
+// ErrLimitExceeded if data is longer than MaxValueLength. Otherwise returned
+// error is a *SyntaxError which reason is ErrTruncated if data ended in the
+// middle of quoted-string or comment, ErrLimitExceeded if some of limits set
+// by SetLimits() is exceeded, and ErrMalformed if unexpected byte was met.
+// That is, caller could wait for more data in the ErrTruncated case.
+func (l *Scanner) Err() error {
+	return l.err
+}
+
 func (l *Scanner) nextChar() (byte, bool) {
 	// Reset scanner state.
 	l.resetItem()
 
-	if l.err {
+	if l.err != nil {
 		return 0, false
 	}
 	l.pos += SkipSpace(l.data[l.pos:])
@@ -165,9 +415,29 @@ func (l *Scanner) nextChar() (byte, bool) {
 	return l.data[l.pos], true
 }
 
+func (l *Scanner) fail(offset int, err error, expected string) {
+	var b byte
+	if offset < len(l.data) {
+		b = l.data[offset]
+	}
+	l.err = &SyntaxError{
+		Offset:   offset,
+		Byte:     b,
+		Err:      err,
+		Expected: expected,
+	}
+}
+
+// malformed marks current item as unexpected one and returns resulting error.
+func (l *Scanner) malformed(expected string) error {
+	l.fail(l.start, ErrMalformed, expected)
+	return l.err
+}
+
 func (l *Scanner) resetItem() {
 	l.itemType = ItemUndef
 	l.itemBytes = nil
+	l.peeked = false
 }
 
 func (l *Scanner) fetchOctet(c byte) bool {
@@ -188,7 +458,10 @@ func (l *Scanner) fetchOctet(c byte) bool {
 func (l *Scanner) fetchToken() bool {
 	n, t := ScanToken(l.data[l.pos:])
 	if n == -1 {
-		l.err = true
+		l.fail(l.pos, ErrMalformed, "")
+		return false
+	}
+	if !l.checkLength(n) {
 		return false
 	}
 
@@ -199,17 +472,58 @@ func (l *Scanner) fetchToken() bool {
 	return true
 }
 
+// fetchMediaType extends current token item up to the end of subtype, if
+// current token is followed by "/" and subtype token.
+func (l *Scanner) fetchMediaType() bool {
+	l.peeked = false
+	if l.Peek() != '/' {
+		l.fail(l.pos, ErrMalformed, "'/'")
+		return false
+	}
+	n, t := ScanToken(l.data[l.pos+1:])
+	if t != ItemToken {
+		l.fail(l.pos+1, ErrMalformed, "subtype token")
+		return false
+	}
+	l.pos += n + 1
+	l.itemBytes = l.data[l.start:l.pos]
+	return true
+}
+
 func (l *Scanner) fetchQuotedString() (ok bool) {
 	l.pos++
 
-	n := ScanUntil(l.data[l.pos:], '"')
+	var n int
+	if l.flags&ScanStrict != 0 {
+		n = scanQuotedText(l.data[l.pos:])
+	} else {
+		n = ScanUntil(l.data[l.pos:], '"')
+	}
 	if n == -1 {
-		l.err = true
+		l.fail(l.pos-1, ErrTruncated, `closing '"'`)
+		return false
+	}
+	if !l.checkLength(n) {
+		return false
+	}
+
+	if l.flags&ScanValidUTF8 != 0 {
+		if i := IndexInvalidUTF8(l.data[l.pos : l.pos+n]); i != -1 {
+			l.fail(l.pos+i, ErrInvalidUTF8, "")
+			return false
+		}
+	}
+
+	if !l.checkObsText(l.data[l.pos:l.pos+n]) || !l.checkStrict(l.data[l.pos:l.pos+n]) {
 		return false
 	}
 
 	l.itemType = ItemString
-	l.itemBytes = RemoveByte(l.data[l.pos:l.pos+n], '\\')
+	if l.flags&ScanRawValues != 0 {
+		l.itemBytes = l.data[l.start : l.pos+n+1]
+	} else {
+		l.itemBytes = l.unescape(l.data[l.pos : l.pos+n])
+	}
 	l.pos += n + 1
 
 	return true
@@ -218,19 +532,125 @@ func (l *Scanner) fetchQuotedString() (ok bool) {
 func (l *Scanner) fetchComment() (ok bool) {
 	l.pos++
 
+	if l.maxCommentDepth > 0 {
+		if i := indexCommentDepth(l.data[l.pos:], l.maxCommentDepth); i != -1 {
+			l.fail(l.pos+i, ErrLimitExceeded, "")
+			return false
+		}
+	}
+
 	n := ScanPairGreedy(l.data[l.pos:], '(', ')')
 	if n == -1 {
-		l.err = true
+		l.fail(l.pos-1, ErrTruncated, `closing ')'`)
+		return false
+	}
+
+	if !l.checkObsText(l.data[l.pos:l.pos+n]) || !l.checkStrict(l.data[l.pos:l.pos+n]) {
 		return false
 	}
 
 	l.itemType = ItemComment
-	l.itemBytes = RemoveByte(l.data[l.pos:l.pos+n], '\\')
+	l.itemBytes = l.unescape(l.data[l.pos : l.pos+n])
 	l.pos += n + 1
 
 	return true
 }
 
+// unescape returns contents of quoted-string or comment p without escaping
+// backslashes. It appends unescaped bytes to the scanner buffer if it is set.
+func (l *Scanner) unescape(p []byte) []byte {
+	if bytes.IndexByte(p, '\\') == -1 {
+		return p
+	}
+	if l.buf == nil {
+		if l.flags&ScanStrict != 0 {
+			return UnescapeQuotedString(p)
+		}
+		return RemoveByte(p, '\\')
+	}
+	// Note that buffered result must be the same as unbuffered one.
+	i := len(l.buf)
+	if l.flags&ScanStrict != 0 {
+		l.buf = UnescapeTo(l.buf, p)
+	} else {
+		l.buf = appendRemoveByte(l.buf, p, '\\')
+	}
+	return l.buf[i:len(l.buf):len(l.buf)]
+}
+
+// checkLength fails scanner if n exceeds maximum token length.
+func (l *Scanner) checkLength(n int) bool {
+	if l.maxTokenLength > 0 && n > l.maxTokenLength {
+		l.fail(l.pos+l.maxTokenLength, ErrLimitExceeded, "")
+		return false
+	}
+	return true
+}
+
+// indexCommentDepth returns index of the opening parenthesis in p which makes
+// comment nesting depth greater than max, or -1 if there is no such one. Note
+// that p must start right after the opening parenthesis of the outer comment.
+func indexCommentDepth(p []byte, max int) int {
+	depth := 1
+	for i := 0; i < len(p) && depth > 0; i++ {
+		switch p[i] {
+		case '\\':
+			i++
+		case '(':
+			if depth++; depth > max {
+				return i
+			}
+		case ')':
+			depth--
+		}
+	}
+	return -1
+}
+
+// checkObsText fails scanner if ScanRejectObsText flag is set and p contains
+// non-ASCII bytes. Note that p must be a subslice of l.data starting at l.pos.
+func (l *Scanner) checkObsText(p []byte) bool {
+	if l.flags&ScanRejectObsText == 0 {
+		return true
+	}
+	for i, c := range p {
+		if c >= utf8.RuneSelf {
+			l.fail(l.pos+i, ErrMalformed, "")
+			return false
+		}
+	}
+	return true
+}
+
+// checkStrict fails scanner if ScanStrict flag is set and p contains control
+// characters other than HT. Note that p must be a subslice of l.data starting
+// at l.pos.
+func (l *Scanner) checkStrict(p []byte) bool {
+	if l.flags&ScanStrict == 0 {
+		return true
+	}
+	if i := indexControl(p); i != -1 {
+		l.fail(l.pos+i, ErrMalformed, "")
+		return false
+	}
+	return true
+}
+
+// scanQuotedText returns index of closing quote of quoted-string contents p.
+// Unlike ScanUntil() it treats every backslash as a start of quoted-pair. It
+// returns -1 if closing quote is not found.
+func scanQuotedText(p []byte) int {
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '"':
+			return i
+		case '\\':
+			i++
+		}
+	}
+	return -1
+}
+
 // ScanUntil scans for first non-escaped character c in given data.
 // It returns index of matched c and -1 if c is not found.
 func ScanUntil(data []byte, c byte) (n int) {
@@ -283,33 +703,68 @@ func ScanPairGreedy(data []byte, open, close byte) (n int) {
 	return
 }
 
+// UnescapeQuotedString replaces quoted-pairs in p, which is a content of
+// quoted-string without surrounding quotes, by octets they escape:
+//
+// quoted-pair = "\\" ( HTAB / SP / VCHAR / obs-text )
+//
+// That is, `a\"b` becomes `a"b` and `a\\b` becomes `a\b`. Unlike
+// RemoveByte(p, '\\'), it keeps escaped backslashes. If p contains no
+// quoted-pairs it returns the same slice. If not, it returns a copy. Trailing
+// lone backslash is kept as is.
+func UnescapeQuotedString(p []byte) []byte {
+	if bytes.IndexByte(p, '\\') == -1 {
+		return p
+	}
+	return UnescapeTo(make([]byte, 0, len(p)-1), p)
+}
+
+// UnescapeTo appends src to dst replacing quoted-pairs by octets they escape,
+// as UnescapeQuotedString() does, and returns the extended slice. It does not
+// allocate if dst has enough capacity.
+func UnescapeTo(dst, src []byte) []byte {
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if c == '\\' && i+1 < len(src) {
+			i++
+			c = src[i]
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
 // RemoveByte returns data without c. If c is not present in data it returns
 // the same slice. If not, it copies data without c.
 func RemoveByte(data []byte, c byte) []byte {
-	j := bytes.IndexByte(data, c)
-	if j == -1 {
+	if bytes.IndexByte(data, c) == -1 {
 		return data
 	}
-
-	n := len(data) - 1
-
 	// If character is present, than allocate slice with n-1 capacity. That is,
 	// resulting bytes could be at most n-1 length.
-	result := make([]byte, n)
-	k := copy(result, data[:j])
+	return appendRemoveByte(make([]byte, 0, len(data)-1), data, c)
+}
 
+// appendRemoveByte appends data without c to dst, exactly as RemoveByte()
+// returns it, and returns the extended slice.
+func appendRemoveByte(dst, data []byte, c byte) []byte {
+	j := bytes.IndexByte(data, c)
+	if j == -1 {
+		return append(dst, data...)
+	}
+	n := len(data) - 1
+	dst = append(dst, data[:j]...)
 	for i := j + 1; i < n; {
 		j = bytes.IndexByte(data[i:], c)
 		if j != -1 {
-			k += copy(result[k:], data[i:i+j])
+			dst = append(dst, data[i:i+j]...)
 			i = i + j + 1
 		} else {
-			k += copy(result[k:], data[i:])
+			dst = append(dst, data[i:]...)
 			break
 		}
 	}
-
-	return result[:k]
+	return dst
 }
 
 // SkipSpace skips spaces and lws-sequences from p.
@@ -333,6 +788,75 @@ func SkipSpace(p []byte) (n int) {
 	return
 }
 
+func exceedsLimit(data []byte) bool {
+	return MaxValueLength > 0 && len(data) > MaxValueLength
+}
+
+// indexControl returns index of the first control character in p which is not
+// allowed in RFC7230 field-value, or -1 if there are no such characters. Note
+// that HT is the only allowed control character.
+func indexControl(p []byte) int {
+	for i, c := range p {
+		if (c < 0x20 || c == 0x7f) && c != '\t' {
+			return i
+		}
+	}
+	return -1
+}
+
+// UnfoldLWS appends p to dst replacing each obsolete line folding (CRLF
+// followed by one or more SP or HT) with a single SP and returns the extended
+// slice:
+//
+// obs-fold = OWS CRLF RWS
+//
+// Whitespace before CRLF is kept as is. Since result is never longer than p,
+// it could be used to unfold p in place by passing p[:0] as dst.
+// See https://tools.ietf.org/html/rfc9112#section-5.2
+func UnfoldLWS(dst, p []byte) []byte {
+	for i := 0; i < len(p); {
+		j := indexObsFold(p[i:])
+		if j == -1 {
+			return append(dst, p[i:]...)
+		}
+		dst = append(dst, p[i:i+j]...)
+		dst = append(dst, ' ')
+		i += j + 2
+		for i < len(p) && (p[i] == ' ' || p[i] == '\t') {
+			i++
+		}
+	}
+	return dst
+}
+
+// indexObsFold returns index of the first obsolete line folding in p, or -1 if
+// there are no such sequences.
+func indexObsFold(p []byte) int {
+	for i := 0; i+2 < len(p); i++ {
+		if p[i] == '\r' && p[i+1] == '\n' && (p[i+2] == ' ' || p[i+2] == '\t') {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexInvalidUTF8 returns index of the first invalid UTF-8 sequence in p, or
+// -1 if p is a valid UTF-8 text.
+func IndexInvalidUTF8(p []byte) int {
+	for i := 0; i < len(p); {
+		if p[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		r, n := utf8.DecodeRune(p[i:])
+		if r == utf8.RuneError && n == 1 {
+			return i
+		}
+		i += n
+	}
+	return -1
+}
+
 // ScanToken scan for next token in p. It returns length of the token and its
 // type. It do not trim p.
 func ScanToken(p []byte) (n int, t ItemType) {
@@ -358,3 +882,31 @@ func ScanToken(p []byte) (n int, t ItemType) {
 		return -1, ItemUndef
 	}
 }
+
+// ScanToken68 scans for RFC9110 token68 in p:
+//
+// token68 = 1*( ALPHA / DIGIT / "-" / "." / "_" / "~" / "+" / "/" ) *"="
+//
+// It returns length of the token68 or 0 if p does not start with it. It does
+// not trim p.
+// See https://tools.ietf.org/html/rfc9110#section-11.2
+func ScanToken68(p []byte) (n int) {
+	for n < len(p) && isToken68Char(p[n]) {
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	for n < len(p) && p[n] == '=' {
+		n++
+	}
+	return n
+}
+
+func isToken68Char(c byte) bool {
+	switch c {
+	case '-', '.', '_', '~', '+', '/':
+		return true
+	}
+	return isAlphaNum(c)
+}
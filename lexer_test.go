@@ -2,6 +2,9 @@ package httphead
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -30,6 +33,237 @@ func TestScannerSkipEscaped(t *testing.T) {
 	}
 }
 
+func TestScannerErr(t *testing.T) {
+	for _, test := range []struct {
+		in    []byte
+		flags ScanFlag
+		err   error
+		off   int
+	}{
+		{
+			in: []byte(`foo, "bar"`),
+		},
+		{
+			in:  []byte(`foo, "bar`),
+			err: ErrTruncated,
+			off: 5,
+		},
+		{
+			in:  []byte(`foo, (bar (baz)`),
+			err: ErrTruncated,
+			off: 5,
+		},
+		{
+			in:  []byte(`foo, bar)`),
+			err: ErrMalformed,
+			off: 8,
+		},
+		{
+			in:  []byte("foo, \x01bar"),
+			err: ErrMalformed,
+			off: 5,
+		},
+		{
+			in: []byte("foo,\r\n bar"),
+		},
+		{
+			in:    []byte("foo,\r\n bar"),
+			flags: ScanRejectControl,
+			err:   ErrHeaderInjection,
+			off:   4,
+		},
+		{
+			in:    []byte("foo, \"b\x00ar\""),
+			flags: ScanRejectControl,
+			err:   ErrHeaderInjection,
+			off:   7,
+		},
+		{
+			in: []byte("foo, \"b\xffar\""),
+		},
+		{
+			in: []byte("foo (bar)"),
+		},
+		{
+			in:    []byte("foo (bar)"),
+			flags: ScanNoComments,
+			err:   ErrMalformed,
+			off:   4,
+		},
+		{
+			in:    []byte("foo, \"\xe2\x82\xac\", \"b\xffar\""),
+			flags: ScanValidUTF8,
+			err:   ErrInvalidUTF8,
+			off:   14,
+		},
+		{
+			in: []byte("foo, \"b\x01ar\""),
+		},
+		{
+			in:    []byte("foo, \"b\x01ar\""),
+			flags: ScanStrict,
+			err:   ErrMalformed,
+			off:   7,
+		},
+		{
+			in:    []byte("foo (b\\\x7far)"),
+			flags: ScanStrict,
+			err:   ErrMalformed,
+			off:   7,
+		},
+		{
+			in:    []byte("foo, \"b\tar\xff\""),
+			flags: ScanStrict,
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			s := NewScannerFlags(test.in, test.flags)
+			for s.Next() {
+			}
+			err := s.Err()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("unexpected error: %v; want %v", err, test.err)
+			}
+			if se, ok := err.(*SyntaxError); ok && se.Offset != test.off {
+				t.Errorf("unexpected error offset: %d; want %d", se.Offset, test.off)
+			}
+		})
+	}
+}
+
+func TestScannerStrict(t *testing.T) {
+	s := NewScannerFlags([]byte(`"a\\", b`), ScanStrict)
+	var act []string
+	for s.Next() {
+		act = append(act, string(s.Bytes()))
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp := []string{`a\`, ",", "b"}; fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected items: %q; want %q", act, exp)
+	}
+}
+
+func TestScannerOffsets(t *testing.T) {
+	data := []byte(`foo, "bar" (baz)`)
+	s := NewScanner(data)
+	var act []string
+	for s.Next() {
+		act = append(act, string(data[s.ItemOffset():s.Pos()]))
+	}
+	if exp := []string{`foo`, `,`, `"bar"`, `(baz)`}; fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected items: %q; want %q", act, exp)
+	}
+}
+
+func TestScannerPeekItem(t *testing.T) {
+	s := NewScanner([]byte(`foo "bar", (`))
+	if !s.Next() || string(s.Bytes()) != "foo" {
+		t.Fatalf("unexpected first item: %q", s.Bytes())
+	}
+	for i := 0; i < 2; i++ {
+		if typ, p := s.PeekItem(); typ != ItemString || string(p) != "bar" {
+			t.Errorf("PeekItem() = %v %q; want %v %q", typ, p, ItemString, "bar")
+		}
+	}
+	if s.Type() != ItemToken || string(s.Bytes()) != "foo" {
+		t.Errorf("current item changed after PeekItem(): %v %q", s.Type(), s.Bytes())
+	}
+	if !s.Next() || s.Type() != ItemString || string(s.Bytes()) != "bar" {
+		t.Errorf("Next() returned %v %q; want peeked item", s.Type(), s.Bytes())
+	}
+	if !s.Next() || !isComma(s.Bytes()) {
+		t.Errorf("Next() returned %v %q; want comma", s.Type(), s.Bytes())
+	}
+	if typ, p := s.PeekItem(); typ != ItemUndef || p != nil {
+		t.Errorf("PeekItem() = %v %q; want %v", typ, p, ItemUndef)
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("unexpected error after PeekItem(): %v", err)
+	}
+	if s.Next() || !errors.Is(s.Err(), ErrTruncated) {
+		t.Errorf("Next() returned %v; want %v", s.Err(), ErrTruncated)
+	}
+}
+
+func TestScannerNextToken68(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{in: `Basic QWxhZGRpbjpvcGVuIHNlc2FtZQ==`, exp: `QWxhZGRpbjpvcGVuIHNlc2FtZQ==`, ok: true},
+		{in: `Bearer mF_9.B5f-4.1JqM , Basic`, exp: `mF_9.B5f-4.1JqM`, ok: true},
+		{in: `Basic realm="example"`},
+		{in: `Basic realm=example`},
+		{in: `Basic`},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			s := NewScanner([]byte(test.in))
+			if !s.Next() {
+				t.Fatalf("can not scan scheme: %v", s.Err())
+			}
+			pos := s.Pos()
+			ok := s.NextToken68()
+			if ok != test.ok {
+				t.Fatalf("NextToken68() = %v; want %v", ok, test.ok)
+			}
+			if !ok {
+				if s.Pos() != pos {
+					t.Errorf("NextToken68() moved scanner to %d; want %d", s.Pos(), pos)
+				}
+				return
+			}
+			if s.Type() != ItemToken68 || string(s.Bytes()) != test.exp {
+				t.Errorf("NextToken68() fetched %v %q; want %v %q", s.Type(), s.Bytes(), ItemToken68, test.exp)
+			}
+		})
+	}
+}
+
+func TestUnfoldLWS(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+	}{
+		{"", ""},
+		{"foo, bar", "foo, bar"},
+		{"foo,\r\n bar", "foo, bar"},
+		{"foo, \r\n\t \tbar", "foo,  bar"},
+		{"foo;a=\"x\r\n  y\"\r\n\t,baz", "foo;a=\"x y\" ,baz"},
+		{"foo\r\nbar\r\n", "foo\r\nbar\r\n"},
+	} {
+		t.Run("", func(t *testing.T) {
+			if act := string(UnfoldLWS(nil, []byte(test.in))); act != test.exp {
+				t.Errorf("UnfoldLWS(%q) = %q; want %q", test.in, act, test.exp)
+			}
+			p := []byte(test.in)
+			if act := string(UnfoldLWS(p[:0], p)); act != test.exp {
+				t.Errorf("UnfoldLWS(%q) in place = %q; want %q", test.in, act, test.exp)
+			}
+		})
+	}
+}
+
+func TestScannerUnfoldLWS(t *testing.T) {
+	in := []byte("foo;a=\"x\r\n y\",\r\n bar")
+	var act []string
+	err := ListScanner{Flags: ScanUnfoldLWS | ScanRejectControl}.ScanOptionsErr(in, func(_ int, key, param, value []byte) Control {
+		act = append(act, string(key), string(param), string(value))
+		return ControlContinue
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp := []string{"foo", "a", "x y", "bar", "", ""}; fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("unexpected options: %q; want %q", act, exp)
+	}
+	if string(in) != "foo;a=\"x\r\n y\",\r\n bar" {
+		t.Errorf("scanner modified data: %q", in)
+	}
+}
+
 type readCase struct {
 	label string
 	in    []byte
@@ -153,3 +387,161 @@ func BenchmarkScannerReadComment(b *testing.B) {
 		})
 	}
 }
+
+func TestScannerFetchRaw(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		out string
+		ok  bool
+		err error
+	}{
+		{
+			in:  ` {"group":"a", "max_age": 1} `,
+			out: `{"group":"a", "max_age": 1}`,
+			ok:  true,
+		},
+		{
+			in:  "{\"a\":\"\tb\"}",
+			out: "{\"a\":\"\tb\"}",
+			ok:  true,
+		},
+		{
+			in:  "{\"a\":\"b\nc\"}",
+			ok:  false,
+			err: ErrHeaderInjection,
+		},
+		{
+			in: "  ",
+			ok: false,
+		},
+	} {
+		l := NewScanner([]byte(test.in))
+		if ok := l.FetchRaw(); ok != test.ok {
+			t.Errorf("FetchRaw(%q) = %v; want %v", test.in, ok, test.ok)
+		}
+		if act := string(l.Bytes()); act != test.out {
+			t.Errorf("FetchRaw(%q) fetched %q; want %q", test.in, act, test.out)
+		}
+		if ok := l.Err() == nil; ok != (test.err == nil) || test.err != nil && !errors.Is(l.Err(), test.err) {
+			t.Errorf("FetchRaw(%q) error is %v; want %v", test.in, l.Err(), test.err)
+		}
+	}
+}
+
+func TestMaxValueLength(t *testing.T) {
+	defer func(n int) { MaxValueLength = n }(MaxValueLength)
+	MaxValueLength = 8
+
+	data := []byte(`foo, bar, baz`)
+	if ScanTokens(data, func([]byte) bool { return true }) {
+		t.Errorf("ScanTokens() = true; want false")
+	}
+	if err := ScanOptionsErr(data, func(int, []byte, []byte, []byte) Control { return ControlContinue }); err != ErrLimitExceeded {
+		t.Errorf("ScanOptionsErr() = %v; want %v", err, ErrLimitExceeded)
+	}
+	if ScanCookie([]byte(`foo=bar; bar=baz`), func(_, _ []byte) bool { return true }) {
+		t.Errorf("ScanCookie() = true; want false")
+	}
+	if !ScanTokens(data[:8], func([]byte) bool { return true }) {
+		t.Errorf("ScanTokens() = false; want true")
+	}
+
+	MaxValueLength = 0
+	if !ScanTokens(data, func([]byte) bool { return true }) {
+		t.Errorf("ScanTokens() = false; want true")
+	}
+}
+
+func TestScannerLimits(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		items  int
+		length int
+		depth  int
+		off    int
+		err    error
+	}{
+		{in: "foo, bar, baz", items: 5},
+		{in: "foo, bar, baz", items: 4, off: 10, err: ErrLimitExceeded},
+		{in: `foo, "bar"`, length: 3},
+		{in: "foobar, baz", length: 3, off: 3, err: ErrLimitExceeded},
+		{in: `foo, "barbaz"`, length: 3, off: 9, err: ErrLimitExceeded},
+		{in: `a (b (c) d)`, depth: 2},
+		{in: `a (b (c (d)))`, depth: 2, off: 8, err: ErrLimitExceeded},
+		{in: `a (b (c (d)))`},
+		{in: strings.Repeat("(", 100) + strings.Repeat(")", 100)},
+	} {
+		t.Run("", func(t *testing.T) {
+			s := NewScanner([]byte(test.in))
+			s.SetLimits(test.items, test.length, test.depth)
+			for s.Next() {
+			}
+			err := s.Err()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("unexpected error: %v; want %v", err, test.err)
+			}
+			if se, ok := err.(*SyntaxError); ok && se.Offset != test.off {
+				t.Errorf("unexpected error offset: %d; want %d", se.Offset, test.off)
+			}
+		})
+	}
+}
+
+func TestUnescapeTo(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+	}{
+		{``, ``},
+		{`abc`, `abc`},
+		{`a\"b`, `a"b`},
+		{`a\\b`, `a\b`},
+		{`a\`, `a\`},
+	} {
+		if act := string(UnescapeTo([]byte("x"), []byte(test.in))); act != "x"+test.exp {
+			t.Errorf("UnescapeTo(%q) = %q; want %q", test.in, act, "x"+test.exp)
+		}
+	}
+}
+
+func TestScanToken68(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp int
+	}{
+		{"QWxhZGRpbjpvcGVuIHNlc2FtZQ==", 28},
+		{"mF_9.B5f-4.1JqM", 15},
+		{"a+b/c~==, x", 8},
+		{"a=b", 2},
+		{"=abc", 0},
+		{"", 0},
+		{" abc", 0},
+	} {
+		if act := ScanToken68([]byte(test.in)); act != test.exp {
+			t.Errorf("ScanToken68(%q) = %d; want %d", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestUnescapeQuotedString(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+	}{
+		{`abc`, `abc`},
+		{`a\"b`, `a"b`},
+		{`a\\b`, `a\b`},
+		{`\\\"`, `\"`},
+		{`a\b\c`, `abc`},
+		{`abc\`, `abc\`},
+		{``, ``},
+	} {
+		if act := string(UnescapeQuotedString([]byte(test.in))); act != test.exp {
+			t.Errorf("UnescapeQuotedString(%#q) = %#q; want %#q", test.in, act, test.exp)
+		}
+	}
+	p := []byte("abc")
+	if act := UnescapeQuotedString(p); &act[0] != &p[0] {
+		t.Errorf("UnescapeQuotedString() copied data without quoted-pairs")
+	}
+}
@@ -0,0 +1,53 @@
+package httphead
+
+// ScanList splits data into elements separated by delim and calls elem for
+// each of them. Delimiters inside quoted-strings and comments are ignored, such
+// that it could be used for both comma separated lists and semicolon separated
+// ones, such as Content-Security-Policy directives or Set-Cookie attributes.
+//
+// Elements are passed as is, with quoted-strings and comments, but without
+// surrounding whitespace. Empty elements are skipped. If elem returns
+// ControlBreak, scanning stops; other Control values continue it.
+//
+// It returns false if data contains unterminated quoted-string or comment, or
+// unbalanced closing parenthesis.
+func ScanList(data []byte, delim byte, elem func([]byte) Control) bool {
+	if exceedsLimit(data) {
+		return false
+	}
+	var (
+		start int
+		depth int
+	)
+	for pos := 0; pos < len(data); pos++ {
+		switch c := data[pos]; {
+		case c == '\\' && depth > 0:
+			pos++
+		case c == '"' && depth == 0:
+			n := scanQuotedText(data[pos+1:])
+			if n == -1 {
+				return false
+			}
+			pos += n + 1
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return false
+			}
+			depth--
+		case c == delim && depth == 0:
+			if p := trim(data[start:pos]); len(p) > 0 && elem(p) == ControlBreak {
+				return true
+			}
+			start = pos + 1
+		}
+	}
+	if depth > 0 {
+		return false
+	}
+	if p := trim(data[start:]); len(p) > 0 {
+		elem(p)
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package httphead
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestScanList(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		delim byte
+		exp   []string
+		ok    bool
+	}{
+		{
+			in:    `default-src 'self'; img-src *; ; script-src "a;b"`,
+			delim: ';',
+			exp:   []string{`default-src 'self'`, `img-src *`, `script-src "a;b"`},
+			ok:    true,
+		},
+		{
+			in:    `foo, bar (a, (b, c)), "x\", y" ,`,
+			delim: ',',
+			exp:   []string{`foo`, `bar (a, (b, c))`, `"x\", y"`},
+			ok:    true,
+		},
+		{
+			in:    `Path=/; Domain="a;b`,
+			delim: ';',
+			exp:   []string{`Path=/`},
+			ok:    false,
+		},
+		{
+			in:    `foo (bar, baz`,
+			delim: ',',
+			ok:    false,
+		},
+		{
+			in:    `foo), bar`,
+			delim: ',',
+			ok:    false,
+		},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			var act []string
+			ok := ScanList([]byte(test.in), test.delim, func(p []byte) Control {
+				act = append(act, string(p))
+				return ControlContinue
+			})
+			if ok != test.ok {
+				t.Errorf("ScanList() = %v; want %v", ok, test.ok)
+			}
+			if fmt.Sprint(act) != fmt.Sprint(test.exp) {
+				t.Errorf("ScanList() elements = %q; want %q", act, test.exp)
+			}
+		})
+	}
+}
+
+func TestScanListBreak(t *testing.T) {
+	var n int
+	ok := ScanList([]byte(`a;b;c`), ';', func([]byte) Control {
+		n++
+		return ControlBreak
+	})
+	if !ok || n != 1 {
+		t.Errorf("ScanList() = %v after %d calls; want true after 1 call", ok, n)
+	}
+}
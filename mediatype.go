@@ -0,0 +1,105 @@
+package httphead
+
+import (
+	"bytes"
+	"strings"
+)
+
+// MediaType represents RFC9110 media type, such as value of Content-Type
+// header.
+type MediaType struct {
+	// Type and Subtype are the parts of media type, such as "application"
+	// and "ld+json".
+	Type, Subtype []byte
+
+	// Suffix is the structured syntax suffix of subtype including "+" sign,
+	// such as "+json". It is nil if subtype has no suffix.
+	// See https://tools.ietf.org/html/rfc6838#section-4.2.8
+	Suffix []byte
+
+	// Parameters contains media type parameters.
+	Parameters Parameters
+}
+
+// ParseMediaType parses media type from data:
+//
+// media-type = type "/" subtype parameters
+// parameters = *( OWS ";" OWS [ parameter ] )
+// parameter  = parameter-name "=" parameter-value
+//
+// Note that returned media type consists of subslices of data. That is, it
+// does not allocate unless parameters are escaped quoted-strings or there are
+// too many parameters. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-8.3.1
+func ParseMediaType(data []byte) (mt MediaType, ok bool) {
+	valid := true
+	s := ListScanner{
+		Flags: ScanMediaTypes,
+		Max:   1,
+	}
+	ok = s.ScanOptions(data, func(_ int, name, attr, val []byte) Control {
+		if mt.Type == nil {
+			j := bytes.IndexByte(name, '/')
+			mt.Type, mt.Subtype = name[:j], name[j+1:]
+			if i := bytes.LastIndexByte(mt.Subtype, '+'); i > 0 && i < len(mt.Subtype)-1 {
+				mt.Suffix = mt.Subtype[i:]
+			}
+		}
+		if attr == nil {
+			return ControlContinue
+		}
+		if val == nil {
+			valid = false
+			return ControlBreak
+		}
+		mt.Parameters.Set(attr, val)
+		return ControlContinue
+	})
+	if !ok || !valid {
+		return MediaType{}, false
+	}
+	return mt, true
+}
+
+// Match reports whether media type matches given pattern. Pattern is a media
+// range without parameters, such as "text/html", "text/*" or "*/*". Subtype
+// of pattern could also be in "*+suffix" form, such as "application/*+json",
+// which matches subtypes with given structured syntax suffix. Comparison is
+// case-insensitive.
+func (mt MediaType) Match(pattern string) bool {
+	i := strings.IndexByte(pattern, '/')
+	if i == -1 {
+		return false
+	}
+	typ, sub := pattern[:i], pattern[i+1:]
+	if typ == "*" {
+		return sub == "*"
+	}
+	if !strings.EqualFold(string(mt.Type), typ) {
+		return false
+	}
+	switch {
+	case sub == "*":
+		return true
+	case strings.HasPrefix(sub, "*+"):
+		return strings.EqualFold(string(mt.Suffix), sub[1:])
+	default:
+		return strings.EqualFold(string(mt.Subtype), sub)
+	}
+}
+
+// String represents media type as a header value.
+func (mt MediaType) String() string {
+	var sb strings.Builder
+	sb.Write(mt.Type)
+	sb.WriteByte('/')
+	sb.Write(mt.Subtype)
+	w := writer{w: &sb}
+	for _, p := range mt.Parameters.data() {
+		w.write(semicolon)
+		writeTokenSanitized(&w, p.key)
+		w.write(equality)
+		writeTokenSanitized(&w, p.value)
+	}
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+package httphead
+
+import "testing"
+
+func TestParseMediaType(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		typ     string
+		subtype string
+		suffix  string
+		str     string
+		ok      bool
+	}{
+		{
+			in:      `text/html`,
+			typ:     "text",
+			subtype: "html",
+			str:     `text/html`,
+			ok:      true,
+		},
+		{
+			in:      `text/html; charset="utf-8"`,
+			typ:     "text",
+			subtype: "html",
+			str:     `text/html;charset=utf-8`,
+			ok:      true,
+		},
+		{
+			in:      `application/ld+json;profile="http://www.w3.org/ns/json-ld#compacted"`,
+			typ:     "application",
+			subtype: "ld+json",
+			suffix:  "+json",
+			str:     `application/ld+json;profile="http://www.w3.org/ns/json-ld#compacted"`,
+			ok:      true,
+		},
+		{
+			in:      `application/vnd.api+json`,
+			typ:     "application",
+			subtype: "vnd.api+json",
+			suffix:  "+json",
+			str:     `application/vnd.api+json`,
+			ok:      true,
+		},
+		{in: `text`},
+		{in: `text/`},
+		{in: `text/html;charset`},
+		{in: `text/html, text/plain`},
+		{in: `text/html;=utf-8`},
+	} {
+		mt, ok := ParseMediaType([]byte(test.in))
+		if ok != test.ok {
+			t.Errorf("ParseMediaType(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if string(mt.Type) != test.typ || string(mt.Subtype) != test.subtype || string(mt.Suffix) != test.suffix {
+			t.Errorf(
+				"ParseMediaType(%q) = %q %q %q; want %q %q %q", test.in,
+				mt.Type, mt.Subtype, mt.Suffix,
+				test.typ, test.subtype, test.suffix,
+			)
+		}
+		if act := mt.String(); act != test.str {
+			t.Errorf("ParseMediaType(%q).String() = %q; want %q", test.in, act, test.str)
+		}
+	}
+}
+
+func TestMediaTypeMatch(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		pattern string
+		exp     bool
+	}{
+		{"text/html", "text/html", true},
+		{"Text/HTML", "text/html", true},
+		{"text/html", "text/*", true},
+		{"text/html", "*/*", true},
+		{"text/html", "text/plain", false},
+		{"text/html", "image/*", false},
+		{"text/html", "*/html", false},
+		{"application/ld+json", "application/*+json", true},
+		{"application/json", "application/*+json", false},
+		{"application/ld+json", "application/json", false},
+		{"text/html", "text", false},
+	} {
+		mt := MustParseMediaType(test.in)
+		if act := mt.Match(test.pattern); act != test.exp {
+			t.Errorf("ParseMediaType(%q).Match(%q) = %v; want %v", test.in, test.pattern, act, test.exp)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package httphead
+
+import "strconv"
+
+// MustParseOptions is like ParseOptions() but panics if data is malformed.
+// It simplifies safe initialization of global variables holding static header
+// values.
+func MustParseOptions(data string) []Option {
+	options, err := ParseOptionsErr([]byte(data), nil)
+	if err != nil {
+		panic(`httphead: ParseOptions(` + strconv.Quote(data) + `): ` + err.Error())
+	}
+	return options
+}
+
+// MustParseMediaType is like ParseMediaType() but panics if data is
+// malformed.
+func MustParseMediaType(data string) MediaType {
+	mt, ok := ParseMediaType([]byte(data))
+	if !ok {
+		panic(`httphead: ParseMediaType(` + strconv.Quote(data) + `): malformed media type`)
+	}
+	return mt
+}
+
+// MustParseQuality is like ParseQuality() but panics if data is malformed.
+func MustParseQuality(data string) uint16 {
+	q, err := ParseQuality([]byte(data))
+	if err != nil {
+		panic(`httphead: ParseQuality(` + strconv.Quote(data) + `): ` + err.Error())
+	}
+	return q
+}
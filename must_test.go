@@ -0,0 +1,39 @@
+package httphead
+
+import "testing"
+
+func TestMustParseOptions(t *testing.T) {
+	opts := MustParseOptions(`foo;a=1,bar`)
+	exp := []Option{
+		NewOption("foo", map[string]string{"a": "1"}),
+		NewOption("bar", nil),
+	}
+	if !optionsEqual(opts, exp) {
+		t.Errorf("MustParseOptions() = %v; want %v", opts, exp)
+	}
+	mustPanic(t, func() { MustParseOptions(`foo;=1`) })
+}
+
+func TestMustParseMediaType(t *testing.T) {
+	if mt := MustParseMediaType("text/html"); mt.String() != "text/html" {
+		t.Errorf("MustParseMediaType() = %s; want text/html", mt)
+	}
+	mustPanic(t, func() { MustParseMediaType("text") })
+}
+
+func TestMustParseQuality(t *testing.T) {
+	if q := MustParseQuality("0.5"); q != 500 {
+		t.Errorf("MustParseQuality() = %d; want 500", q)
+	}
+	mustPanic(t, func() { MustParseQuality("2") })
+}
+
+func mustPanic(t *testing.T, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+	f()
+}
@@ -0,0 +1,204 @@
+package httphead
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Negotiate selects the most preferred value of available ones according to
+// accept, which is a value of Accept, Accept-Encoding, Accept-Language or
+// similar header.
+//
+// If available values are media types (that is, contain "/"), accept is
+// parsed as Accept header (see ParseAccept()). Available media types could
+// carry parameters, such as "text/html;level=1". Media ranges are matched on
+// type and subtype, and each parameter of media range must be present in
+// available value with the same value (names and values are compared
+// case-insensitively). That is, "text/html;level=1" does not match
+// "text/html". Media ranges with more parameters are more specific.
+// Otherwise accept is parsed as list of tokens with optional quality values.
+// Tokens are matched case-insensitively and as prefixes of "-" separated
+// values, such that "en" matches "en-US". In both cases "*" matches any value.
+//
+// Quality of available value is the quality of the most specific matching
+// range. Values with zero quality are never selected. If multiple values
+// have the same quality, the first one in available is selected. Empty accept
+// means that any value is acceptable.
+//
+// It returns false if accept is malformed or there is no acceptable value.
+// See https://tools.ietf.org/html/rfc9110#section-12.5.1
+func Negotiate(accept []byte, available []string) (string, bool) {
+	if len(available) == 0 {
+		return "", false
+	}
+	if len(trim(accept)) == 0 {
+		return available[0], true
+	}
+	var (
+		ranges []weighted
+		ok     bool
+	)
+	media := strings.IndexByte(available[0], '/') != -1
+	if media {
+		var mr []MediaRange
+		if mr, ok = ParseAccept(accept, nil); ok {
+			for _, r := range mr {
+				ranges = append(ranges, weighted{
+					value:  r.Type,
+					sub:    r.Subtype,
+					params: r.Parameters,
+					q:      r.Quality,
+				})
+			}
+		}
+	} else {
+		ok = scanWeighted(accept, func(v []byte, q uint16) bool {
+			ranges = append(ranges, weighted{value: v, q: q})
+			return true
+		})
+	}
+	if !ok {
+		return "", false
+	}
+	var (
+		best  = -1
+		bestQ uint16
+	)
+	for i, a := range available {
+		v := weighted{value: []byte(a)}
+		if media {
+			mt, ok := ParseAccept(v.value, nil)
+			if !ok || len(mt) != 1 {
+				continue
+			}
+			v = weighted{
+				value:  mt[0].Type,
+				sub:    mt[0].Subtype,
+				params: mt[0].Parameters,
+			}
+		}
+		q := quality(ranges, v, media)
+		if q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best == -1 {
+		return "", false
+	}
+	return available[best], true
+}
+
+// weighted represents range of values with its quality.
+type weighted struct {
+	value, sub []byte
+	params     Parameters
+	q          uint16
+}
+
+// quality returns quality of the most specific range matching v.
+func quality(ranges []weighted, v weighted, media bool) uint16 {
+	var (
+		q    uint16
+		spec = -1
+	)
+	for _, r := range ranges {
+		s := specificity(r, v, media)
+		if s > spec {
+			q, spec = r.q, s
+		}
+	}
+	return q
+}
+
+// specificity returns specificity of range r matching v, or -1 if r does not
+// match v.
+func specificity(r weighted, v weighted, media bool) int {
+	if !media {
+		n := len(v.value)
+		switch {
+		case isWildcard(r.value):
+			return 0
+		case bytes.EqualFold(r.value, v.value):
+			return n + 1
+		case n > len(r.value) && v.value[len(r.value)] == '-' && bytes.EqualFold(r.value, v.value[:len(r.value)]):
+			return len(r.value)
+		}
+		return -1
+	}
+	switch {
+	case isWildcard(r.value):
+		return 0
+	case !bytes.EqualFold(r.value, v.value):
+		return -1
+	case isWildcard(r.sub):
+		return 1
+	case !bytes.EqualFold(r.sub, v.sub):
+		return -1
+	}
+	// All parameters of r must be present in v.
+	matched := 0
+	r.params.ForEach(func(key, value []byte) bool {
+		if x, ok := getFold(&v.params, key); !ok || !bytes.EqualFold(x, value) {
+			matched = -1
+			return false
+		}
+		matched++
+		return true
+	})
+	if matched == -1 {
+		return -1
+	}
+	return 2 + matched
+}
+
+// getFold returns value of parameter which name is case-insensitively equal
+// to key.
+func getFold(p *Parameters, key []byte) (value []byte, ok bool) {
+	p.ForEach(func(k, v []byte) bool {
+		if bytes.EqualFold(k, key) {
+			value, ok = v, true
+		}
+		return !ok
+	})
+	return value, ok
+}
+
+// scanWeighted scans list of tokens with optional quality values, such as
+// Want-Digest or Accept-Encoding values. Tokens without quality value are
+// reported with quality of 1000. Other parameters are ignored.
+func scanWeighted(data []byte, it func(v []byte, q uint16) bool) bool {
+	var (
+		value []byte
+		q     uint16
+		index = -1
+		valid = true
+		stop  bool
+	)
+	ok := ScanOptions(data, func(i int, name, attr, val []byte) Control {
+		if i != index {
+			if index != -1 && !it(value, q) {
+				stop = true
+				return ControlBreak
+			}
+			index = i
+			value = name
+			q = 1000
+		}
+		if isQualityName(attr) {
+			var err error
+			if q, err = ParseQuality(val); err != nil {
+				valid = false
+				return ControlBreak
+			}
+		}
+		return ControlContinue
+	})
+	if ok && valid && !stop && index != -1 {
+		it(value, q)
+	}
+	return ok && valid
+}
+
+func isQualityName(p []byte) bool {
+	return len(p) == 1 && (p[0] == 'q' || p[0] == 'Q')
+}
@@ -0,0 +1,126 @@
+package httphead
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	for _, test := range []struct {
+		accept    string
+		available []string
+		exp       string
+		ok        bool
+	}{
+		{
+			accept:    `text/html, application/json;q=0.9`,
+			available: []string{"application/json", "text/html"},
+			exp:       "text/html",
+			ok:        true,
+		},
+		{
+			accept:    `text/*;q=0.3, text/html;q=0.7, */*;q=0.5`,
+			available: []string{"text/plain", "image/png", "text/html"},
+			exp:       "text/html",
+			ok:        true,
+		},
+		{
+			accept:    `text/*;q=0.3, */*;q=0.5`,
+			available: []string{"text/plain", "image/png"},
+			exp:       "image/png",
+			ok:        true,
+		},
+		{
+			accept:    `text/html;q=0, */*`,
+			available: []string{"text/html"},
+			ok:        false,
+		},
+		{
+			accept:    `text/html;level=1, text/html;q=0.5, application/json;q=0.6`,
+			available: []string{"text/html", "application/json"},
+			exp:       "application/json",
+			ok:        true,
+		},
+		{
+			accept:    `application/json;charset=utf-8`,
+			available: []string{"application/json"},
+			ok:        false,
+		},
+		{
+			accept:    `text/html;level=1`,
+			available: []string{"text/html"},
+			ok:        false,
+		},
+		{
+			accept:    `text/html;level=1;charset=utf-8`,
+			available: []string{"text/html;level=1"},
+			ok:        false,
+		},
+		{
+			accept:    `text/html;level=1;q=0.9, text/html;q=0.1`,
+			available: []string{"text/html;charset=utf-8;level=1"},
+			exp:       "text/html;charset=utf-8;level=1",
+			ok:        true,
+		},
+		{
+			accept:    `text/html;level=1;q=0.2, text/html;q=0.8`,
+			available: []string{"text/html"},
+			exp:       "text/html",
+			ok:        true,
+		},
+		{
+			accept:    `text/html;level=1;q=0.8, text/html;q=0.2, application/json;q=0.5`,
+			available: []string{"application/json", "text/html;level=1"},
+			exp:       "text/html;level=1",
+			ok:        true,
+		},
+		{
+			accept:    `text/html;level=2, application/json;q=0.5`,
+			available: []string{"text/html;level=1", "application/json"},
+			exp:       "application/json",
+			ok:        true,
+		},
+		{
+			accept:    `text/html;CHARSET=UTF-8;q=0.1, text/*;q=0.9`,
+			available: []string{"text/html;charset=utf-8"},
+			exp:       "text/html;charset=utf-8",
+			ok:        true,
+		},
+		{
+			accept:    `application/xml`,
+			available: []string{"application/json"},
+			ok:        false,
+		},
+		{
+			accept:    `gzip;q=0.5, br, *;q=0.1`,
+			available: []string{"identity", "gzip", "br"},
+			exp:       "br",
+			ok:        true,
+		},
+		{
+			accept:    `da, en-gb;q=0.8, en;q=0.7`,
+			available: []string{"en-US", "en-GB"},
+			exp:       "en-GB",
+			ok:        true,
+		},
+		{
+			accept:    `en;q=0.7, fr;q=0.1`,
+			available: []string{"en-US", "fr"},
+			exp:       "en-US",
+			ok:        true,
+		},
+		{
+			accept:    ``,
+			available: []string{"gzip", "br"},
+			exp:       "gzip",
+			ok:        true,
+		},
+		{
+			accept:    `gzip;q=x`,
+			available: []string{"gzip"},
+			ok:        false,
+		},
+	} {
+		act, ok := Negotiate([]byte(test.accept), test.available)
+		if act != test.exp || ok != test.ok {
+			t.Errorf("Negotiate(%q, %q) = %q, %v; want %q, %v", test.accept, test.available, act, ok, test.exp, test.ok)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package httphead
+
+import (
+	"bytes"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NEL represents Network Error Logging policy delivered with NEL header.
+// See https://www.w3.org/TR/network-error-logging/#nel-response-header
+type NEL struct {
+	// ReportTo is the name of the endpoint group to deliver reports to.
+	ReportTo []byte
+
+	// MaxAge is the policy lifetime in seconds. Zero value means that policy
+	// must be removed.
+	MaxAge int64
+
+	// IncludeSubdomains reports whether policy applies to subdomains.
+	IncludeSubdomains bool
+
+	// SuccessFraction is the sampling rate of successful requests. It is 0
+	// if not present.
+	SuccessFraction float64
+
+	// FailureFraction is the sampling rate of failed requests. It is 1 if not
+	// present.
+	FailureFraction float64
+}
+
+// ParseNEL parses NEL header value, which is a JSON object:
+//
+// NEL: {"report_to": "nel", "max_age": 2592000, "failure_fraction": 0.5}
+//
+// Only policy fields listed in NEL struct are extracted; other members,
+// including nested objects and arrays, are checked for JSON syntax and
+// skipped. The max_age member is required, and report_to is required unless
+// max_age is 0. Fractions must be in 0..1 range.
+//
+// Note that returned ReportTo is a subslice of data, unless it contains
+// escaped characters. It returns false if data is malformed.
+// See https://www.w3.org/TR/network-error-logging/#the-nel-header
+func ParseNEL(data []byte) (nel NEL, ok bool) {
+	const (
+		hasReportTo = 1 << iota
+		hasMaxAge
+	)
+	var (
+		has   int
+		valid = true
+	)
+	if exceedsLimit(data) {
+		return NEL{}, false
+	}
+	nel.FailureFraction = 1
+	ok = scanJSONObject(trim(data), func(key, value []byte) bool {
+		switch string(key) {
+		case "report_to":
+			nel.ReportTo, valid = jsonString(value)
+			has |= hasReportTo
+		case "max_age":
+			var n uint64
+			n, valid = ParseDigits(value)
+			nel.MaxAge = int64(n)
+			valid = valid && n <= MaxDeltaSeconds
+			has |= hasMaxAge
+		case "include_subdomains":
+			nel.IncludeSubdomains, valid = jsonBool(value)
+		case "success_fraction":
+			nel.SuccessFraction, valid = jsonFraction(value)
+		case "failure_fraction":
+			nel.FailureFraction, valid = jsonFraction(value)
+		}
+		return valid
+	})
+	if !ok || !valid || has&hasMaxAge == 0 ||
+		has&hasReportTo == 0 && nel.MaxAge != 0 {
+		return NEL{}, false
+	}
+	return nel, true
+}
+
+// scanJSONObject scans JSON object and calls it for every member with
+// unescaped key and raw value. If it returns false, scanning stops.
+func scanJSONObject(data []byte, it func(key, value []byte) bool) bool {
+	p := data
+	if len(p) == 0 || p[0] != '{' {
+		return false
+	}
+	p = skipJSONSpace(p[1:])
+	if len(p) > 0 && p[0] == '}' {
+		return len(skipJSONSpace(p[1:])) == 0
+	}
+	for {
+		n := scanJSONString(p)
+		if n == -1 {
+			return false
+		}
+		key, ok := jsonString(p[:n])
+		if !ok {
+			return false
+		}
+		p = skipJSONSpace(p[n:])
+		if len(p) == 0 || p[0] != ':' {
+			return false
+		}
+		p = skipJSONSpace(p[1:])
+		if n = scanJSONValue(p, 1); n == -1 {
+			return false
+		}
+		if !it(key, p[:n]) {
+			return false
+		}
+		p = skipJSONSpace(p[n:])
+		if len(p) == 0 {
+			return false
+		}
+		switch p[0] {
+		case ',':
+			p = skipJSONSpace(p[1:])
+		case '}':
+			return len(skipJSONSpace(p[1:])) == 0
+		default:
+			return false
+		}
+	}
+}
+
+// maxJSONDepth is the maximum nesting depth of JSON containers, such that
+// scanning of untrusted data does not exhaust the stack.
+const maxJSONDepth = 32
+
+// scanJSONValue returns length of JSON value at the beginning of p or -1 if
+// p does not start with a valid value. The depth is the number of containers
+// which enclose the value.
+func scanJSONValue(p []byte, depth int) int {
+	if len(p) == 0 {
+		return -1
+	}
+	switch c := p[0]; {
+	case c == '"':
+		return scanJSONString(p)
+	case c == '{' || c == '[':
+		return scanJSONContainer(p, depth+1)
+	case c == '-' || isDigit(c):
+		return scanJSONNumber(p)
+	}
+	for _, lit := range jsonLiterals {
+		if bytes.HasPrefix(p, lit) {
+			return len(lit)
+		}
+	}
+	return -1
+}
+
+var jsonLiterals = [][]byte{
+	[]byte("true"),
+	[]byte("false"),
+	[]byte("null"),
+}
+
+func scanJSONContainer(p []byte, depth int) int {
+	if depth > maxJSONDepth {
+		return -1
+	}
+	open, close := p[0], byte('}')
+	if open == '[' {
+		close = ']'
+	}
+	i := len(p) - len(skipJSONSpace(p[1:]))
+	if i < len(p) && p[i] == close {
+		return i + 1
+	}
+	for i < len(p) {
+		if open == '{' {
+			n := scanJSONString(p[i:])
+			if n == -1 {
+				return -1
+			}
+			i += n
+			i = len(p) - len(skipJSONSpace(p[i:]))
+			if i == len(p) || p[i] != ':' {
+				return -1
+			}
+			i = len(p) - len(skipJSONSpace(p[i+1:]))
+		}
+		n := scanJSONValue(p[i:], depth)
+		if n == -1 {
+			return -1
+		}
+		i = len(p) - len(skipJSONSpace(p[i+n:]))
+		if i == len(p) {
+			return -1
+		}
+		switch p[i] {
+		case ',':
+			i = len(p) - len(skipJSONSpace(p[i+1:]))
+		case close:
+			return i + 1
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+func scanJSONString(p []byte) int {
+	if len(p) == 0 || p[0] != '"' {
+		return -1
+	}
+	for i := 1; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '"':
+			return i + 1
+		case c == '\\':
+			i++
+		case c < 0x20:
+			return -1
+		}
+	}
+	return -1
+}
+
+func scanJSONNumber(p []byte) int {
+	i := 0
+	if p[i] == '-' {
+		i++
+	}
+	start := i
+	for i < len(p) && isDigit(p[i]) {
+		i++
+	}
+	if i == start || p[start] == '0' && i-start > 1 {
+		return -1
+	}
+	if i < len(p) && p[i] == '.' {
+		i++
+		start = i
+		for i < len(p) && isDigit(p[i]) {
+			i++
+		}
+		if i == start {
+			return -1
+		}
+	}
+	if i < len(p) && p[i]|toLower == 'e' {
+		i++
+		if i < len(p) && (p[i] == '+' || p[i] == '-') {
+			i++
+		}
+		start = i
+		for i < len(p) && isDigit(p[i]) {
+			i++
+		}
+		if i == start {
+			return -1
+		}
+	}
+	return i
+}
+
+func skipJSONSpace(p []byte) []byte {
+	for len(p) > 0 {
+		switch p[0] {
+		case ' ', '\t', '\n', '\r':
+			p = p[1:]
+		default:
+			return p
+		}
+	}
+	return p
+}
+
+// jsonString returns unescaped value of JSON string p with quotes. It returns
+// subslice of p if p has no escaped characters.
+func jsonString(p []byte) ([]byte, bool) {
+	if len(p) < 2 || p[0] != '"' || p[len(p)-1] != '"' {
+		return nil, false
+	}
+	p = p[1 : len(p)-1]
+	i := bytes.IndexByte(p, '\\')
+	if i == -1 {
+		return p, true
+	}
+	ret := make([]byte, i, len(p))
+	copy(ret, p)
+	for i < len(p) {
+		c := p[i]
+		if c != '\\' {
+			ret = append(ret, c)
+			i++
+			continue
+		}
+		if i++; i == len(p) {
+			return nil, false
+		}
+		switch c = p[i]; c {
+		case '"', '\\', '/':
+			ret = append(ret, c)
+		case 'b':
+			ret = append(ret, '\b')
+		case 'f':
+			ret = append(ret, '\f')
+		case 'n':
+			ret = append(ret, '\n')
+		case 'r':
+			ret = append(ret, '\r')
+		case 't':
+			ret = append(ret, '\t')
+		case 'u':
+			r, ok := jsonRune(p[i+1:])
+			if !ok {
+				return nil, false
+			}
+			i += 4
+			if utf16.IsSurrogate(r) {
+				// Expect low surrogate to follow.
+				r1 := r
+				r = utf8.RuneError
+				if q := p[i+1:]; len(q) >= 6 && q[0] == '\\' && q[1] == 'u' {
+					if r2, ok := jsonRune(q[2:]); ok {
+						if d := utf16.DecodeRune(r1, r2); d != utf8.RuneError {
+							r = d
+							i += 6
+						}
+					}
+				}
+			}
+			ret = append(ret, string(r)...)
+		default:
+			return nil, false
+		}
+		i++
+	}
+	return ret, true
+}
+
+// jsonRune decodes four hex digits at the beginning of p.
+func jsonRune(p []byte) (r rune, ok bool) {
+	if len(p) < 4 {
+		return 0, false
+	}
+	for _, c := range p[:4] {
+		d := unhex(c)
+		if d == -1 {
+			return 0, false
+		}
+		r = r<<4 | rune(d)
+	}
+	return r, true
+}
+
+func jsonBool(p []byte) (v, ok bool) {
+	switch string(p) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+func jsonFraction(p []byte) (f float64, ok bool) {
+	if len(p) == 0 || p[0] == '-' {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(string(p), 64)
+	if err != nil || f > 1 {
+		return 0, false
+	}
+	return f, true
+}
@@ -0,0 +1,89 @@
+package httphead
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNEL(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp NEL
+		ok  bool
+	}{
+		{
+			in: `{"report_to": "network-errors", "max_age": 2592000, "include_subdomains": true, "success_fraction": 0.01}`,
+			exp: NEL{
+				ReportTo:          []byte("network-errors"),
+				MaxAge:            2592000,
+				IncludeSubdomains: true,
+				SuccessFraction:   0.01,
+				FailureFraction:   1,
+			},
+			ok: true,
+		},
+		{
+			in: ` { "max_age" : 0 } `,
+			exp: NEL{
+				FailureFraction: 1,
+			},
+			ok: true,
+		},
+		{
+			in: `{"report_to":"nel\/1","max_age":60,"failure_fraction":0,` +
+				`"request_headers":["If-None-Match"],"x":{"a":[1,-2.5e3,null,{}]}}`,
+			exp: NEL{
+				ReportTo:        []byte("nel/1"),
+				MaxAge:          60,
+				FailureFraction: 0,
+			},
+			ok: true,
+		},
+		{
+			in: `{"report_to":"\ud83d\ude00\u00e9","max_age":1}`,
+			exp: NEL{
+				ReportTo:        []byte("\U0001F600é"),
+				MaxAge:          1,
+				FailureFraction: 1,
+			},
+			ok: true,
+		},
+		{in: `{"report_to": "nel"}`},
+		{in: `{"max_age": 60}`},
+		{in: `{"report_to": "nel", "max_age": -1}`},
+		{in: `{"report_to": "nel", "max_age": 1.5}`},
+		{in: `{"report_to": "nel", "max_age": "60"}`},
+		{in: `{"report_to": nel, "max_age": 60}`},
+		{in: `{"report_to": "nel", "max_age": 60, "failure_fraction": 1.5}`},
+		{in: `{"report_to": "nel", "max_age": 60, "include_subdomains": 1}`},
+		{in: `{"report_to": "nel", "max_age": 60,}`},
+		{in: `{"report_to": "nel", "max_age": 60, "x": [1,]}`},
+		{in: `{"report_to": "nel", "max_age": 60, "x": 01}`},
+		{in: `{"report_to": "nel", "max_age": 60} x`},
+		{in: `{"report_to": "nel" "max_age": 60}`},
+		{in: `{"report_to": "n\el", "max_age": 60}`},
+		{in: `["report_to"]`},
+		{in: ``},
+	} {
+		act, ok := ParseNEL([]byte(test.in))
+		if ok != test.ok || !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseNEL(%s) = %+v, %v; want %+v, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseNELDepth(t *testing.T) {
+	nested := func(n int) []byte {
+		return []byte(`{"max_age":0,"x":` + strings.Repeat("[", n) + strings.Repeat("]", n) + `}`)
+	}
+	if _, ok := ParseNEL(nested(maxJSONDepth - 1)); !ok {
+		t.Errorf("ParseNEL() = false; want true")
+	}
+	if _, ok := ParseNEL(nested(maxJSONDepth)); ok {
+		t.Errorf("ParseNEL() = true; want false")
+	}
+	if _, ok := ParseNEL(nested(1 << 16)); ok {
+		t.Errorf("ParseNEL() = true; want false")
+	}
+}
@@ -0,0 +1,107 @@
+package httphead
+
+import "strings"
+
+// NormalizeFlag encodes way of header value normalization.
+type NormalizeFlag byte
+
+const (
+	// NormalizeLowercase causes Normalize() to write option and parameter
+	// names in lower case.
+	NormalizeLowercase NormalizeFlag = 1 << iota
+
+	// NormalizeQuoteValues causes Normalize() to write all parameter values
+	// as quoted-strings. By default values are quoted only if they contain
+	// non-token characters.
+	NormalizeQuoteValues
+)
+
+// String represents flag as string.
+func (f NormalizeFlag) String() string {
+	var flags [2]string
+	var n int
+	if f&NormalizeLowercase != 0 {
+		flags[n] = "lowercase"
+		n++
+	}
+	if f&NormalizeQuoteValues != 0 {
+		flags[n] = "quote-values"
+		n++
+	}
+	return "[" + strings.Join(flags[:n], "|") + "]"
+}
+
+// Normalize rewrites options list from src in canonical form and appends it
+// to dst. It does it in a single pass over src without allocating Options.
+//
+// Canonical form has no whitespace around separators and no empty list
+// elements. Parameter values are written as tokens when possible and as
+// quoted-strings otherwise. That is, `foo ; a="1",, bar` is normalized into
+// `foo;a=1,bar`.
+//
+// Quoted-strings are scanned as RFC9110 defines them (see ScanStrict), such
+// that values are re-quoted from their properly unescaped form and escaped
+// backslashes are preserved.
+//
+// It returns false if src is malformed. In that case dst is returned with its
+// initial length.
+func Normalize(dst, src []byte, flags NormalizeFlag) ([]byte, bool) {
+	n := len(dst)
+	index := -1
+	s := ListScanner{Flags: ScanStrict}
+	ok := s.ScanOptions(src, func(i int, name, attr, val []byte) Control {
+		if i != index {
+			if index != -1 {
+				dst = append(dst, ',')
+			}
+			index = i
+			dst = appendName(dst, name, flags)
+		}
+		if attr == nil {
+			return ControlContinue
+		}
+		dst = append(dst, ';')
+		dst = appendName(dst, attr, flags)
+		if val != nil {
+			dst = append(dst, '=')
+			dst = appendValue(dst, val, flags&NormalizeQuoteValues != 0)
+		}
+		return ControlContinue
+	})
+	if !ok {
+		return dst[:n], false
+	}
+	return dst, true
+}
+
+func appendName(dst, name []byte, flags NormalizeFlag) []byte {
+	if flags&NormalizeLowercase == 0 {
+		return append(dst, name...)
+	}
+	return FoldToken(dst, name)
+}
+
+// appendValue appends p as token if it is possible and quote is false, or as
+// quoted-string otherwise.
+func appendValue(dst, p []byte, quote bool) []byte {
+	if !quote {
+		quote = len(p) == 0
+		for _, c := range p {
+			if !OctetTypes[c].IsToken() {
+				quote = true
+				break
+			}
+		}
+	}
+	if !quote {
+		return append(dst, p...)
+	}
+	dst = append(dst, '"')
+	for _, c := range p {
+		if c == '"' || c == '\\' {
+			dst = append(dst, '\\')
+		}
+		dst = append(dst, c)
+	}
+	return append(dst, '"')
+}
@@ -0,0 +1,92 @@
+package httphead
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	for _, test := range []struct {
+		label string
+		in    string
+		flags NormalizeFlag
+		exp   string
+		ok    bool
+	}{
+		{
+			label: "simple",
+			in:    `foo;a=1,bar`,
+			exp:   `foo;a=1,bar`,
+			ok:    true,
+		},
+		{
+			label: "space",
+			in:    ` foo ; a = 1 ,, , bar ;b `,
+			exp:   `foo;a=1,bar;b`,
+			ok:    true,
+		},
+		{
+			label: "quotes",
+			in:    `foo;a="1";b="x y";c="";d="a\"b"`,
+			exp:   `foo;a=1;b="x y";c="";d="a\"b"`,
+			ok:    true,
+		},
+		{
+			label: "escapes",
+			in:    `a;b="x\\y";c="\"q\"";d="\\";e="\t"`,
+			exp:   `a;b="x\\y";c="\"q\"";d="\\";e=t`,
+			ok:    true,
+		},
+		{
+			label: "quote_values",
+			in:    `foo;a=1;b`,
+			flags: NormalizeQuoteValues,
+			exp:   `foo;a="1";b`,
+			ok:    true,
+		},
+		{
+			label: "lowercase",
+			in:    `Foo;A=Bar, BAZ`,
+			flags: NormalizeLowercase,
+			exp:   `foo;a=Bar,baz`,
+			ok:    true,
+		},
+		{
+			label: "malformed",
+			in:    `foo;a=1,bar;=`,
+			ok:    false,
+		},
+	} {
+		t.Run(test.label+test.flags.String(), func(t *testing.T) {
+			dst := []byte("prefix:")
+			act, ok := Normalize(dst, []byte(test.in), test.flags)
+			if ok != test.ok {
+				t.Fatalf("Normalize(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			}
+			if exp := "prefix:" + test.exp; string(act) != exp {
+				t.Errorf("Normalize(%q) = %q; want %q", test.in, act, exp)
+			}
+		})
+	}
+}
+
+func TestNormalizeRoundTrip(t *testing.T) {
+	parse := func(data []byte) (opts []Option) {
+		s := ListScanner{Flags: ScanStrict}
+		if !s.ScanOptions(data, CollectOptions(&opts)) {
+			t.Fatalf("ScanOptions(%q) = false; want true", data)
+		}
+		return opts
+	}
+	for _, in := range []string{
+		`a;b="x\\y"`,
+		`a;b="C:\\dir\\"`,
+		`a;b="say \"hi\"", c;d="\\\""`,
+		`a;b="x y";c=z`,
+	} {
+		act, ok := Normalize(nil, []byte(in), 0)
+		if !ok {
+			t.Fatalf("Normalize(%q) = false; want true", in)
+		}
+		if exp, act := parse([]byte(in)), parse(act); !optionsEqual(act, exp) {
+			t.Errorf("Normalize(%q) changed options: %v; want %v", in, act, exp)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package httphead
+
+import "math"
+
+// ParseDigits parses non-negative decimal integer from p:
+//
+// 1*DIGIT
+//
+// Unlike strconv functions it does not accept signs, underscores or
+// whitespace. It returns false if p is malformed or value overflows uint64.
+func ParseDigits(p []byte) (n uint64, ok bool) {
+	if len(p) == 0 {
+		return 0, false
+	}
+	for _, c := range p {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := uint64(c - '0')
+		if n > (math.MaxUint64-d)/10 {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+	return n, true
+}
+
+// ParseContentLength parses Content-Length header value:
+//
+// Content-Length = 1*DIGIT
+//
+// It returns false if p is malformed or value overflows int64.
+// See https://tools.ietf.org/html/rfc9110#section-8.6
+func ParseContentLength(p []byte) (n int64, ok bool) {
+	u, ok := ParseDigits(p)
+	if !ok || u > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(u), true
+}
+
+// ParseMaxForwards parses Max-Forwards header value:
+//
+// Max-Forwards = 1*DIGIT
+//
+// It follows the same rules as ParseContentLength(). That is, signs,
+// whitespace and multiple values are rejected.
+// See https://tools.ietf.org/html/rfc9110#section-7.6.2
+func ParseMaxForwards(p []byte) (n int64, ok bool) {
+	return ParseContentLength(p)
+}
+
+// ParseContentLengthList parses Content-Length header value which could
+// contain the same value repeated as comma separated list, such as "42, 42".
+// Some proxies produce such values when combining multiple Content-Length
+// headers.
+//
+// Only the 1#Content-Length list is accepted, that is, values must be
+// separated by commas and empty list elements are rejected.
+//
+// It returns ErrContentLengthMismatch if values differ and ErrMalformed if
+// any of values is malformed. Both cases must be treated as unrecoverable
+// error, since they could be an attempt of request smuggling.
+// See https://tools.ietf.org/html/rfc9110#section-8.6
+func ParseContentLengthList(data []byte) (n int64, err error) {
+	var has bool
+	s := ListScanner{Flags: ScanRequireComma | ScanRejectEmpty}
+	ok := s.ScanTokens(data, func(v []byte) bool {
+		m, ok := ParseContentLength(v)
+		switch {
+		case !ok:
+			err = ErrMalformed
+		case has && m != n:
+			err = ErrContentLengthMismatch
+		default:
+			n, has = m, true
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrMalformed
+	}
+	return n, nil
+}
+
+// MaxDeltaSeconds is the value which delta-seconds greater than the greatest
+// representable integer are treated as.
+// See https://tools.ietf.org/html/rfc9111#section-1.2.2
+const MaxDeltaSeconds = 1 << 31
+
+// ParseDeltaSeconds parses delta-seconds value, such as used in Age header or
+// max-age directive of Cache-Control header:
+//
+// delta-seconds = 1*DIGIT
+//
+// Values greater than MaxDeltaSeconds are treated as MaxDeltaSeconds. It
+// returns false if p is malformed.
+// See https://tools.ietf.org/html/rfc9111#section-1.2.2
+func ParseDeltaSeconds(p []byte) (n int64, ok bool) {
+	if len(p) == 0 {
+		return 0, false
+	}
+	for _, c := range p {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if n = n*10 + int64(c-'0'); n > MaxDeltaSeconds {
+			n = MaxDeltaSeconds
+		}
+	}
+	return n, true
+}
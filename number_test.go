@@ -0,0 +1,112 @@
+package httphead
+
+import "testing"
+
+func TestParseDigits(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp uint64
+		ok  bool
+	}{
+		{"0", 0, true},
+		{"00042", 42, true},
+		{"18446744073709551615", 18446744073709551615, true},
+		{"18446744073709551616", 0, false},
+		{"", 0, false},
+		{"+1", 0, false},
+		{"-1", 0, false},
+		{" 1", 0, false},
+		{"1_000", 0, false},
+	} {
+		act, ok := ParseDigits([]byte(test.in))
+		if act != test.exp || ok != test.ok {
+			t.Errorf("ParseDigits(%q) = %d, %v; want %d, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseContentLength(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp int64
+		ok  bool
+	}{
+		{"3495", 3495, true},
+		{"9223372036854775807", 9223372036854775807, true},
+		{"9223372036854775808", 0, false},
+		{"+3495", 0, false},
+		{"3495,3495", 0, false},
+	} {
+		act, ok := ParseContentLength([]byte(test.in))
+		if act != test.exp || ok != test.ok {
+			t.Errorf("ParseContentLength(%q) = %d, %v; want %d, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseMaxForwards(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp int64
+		ok  bool
+	}{
+		{"0", 0, true},
+		{"10", 10, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{" 10", 0, false},
+		{"10, 10", 0, false},
+		{"99999999999999999999", 0, false},
+	} {
+		act, ok := ParseMaxForwards([]byte(test.in))
+		if act != test.exp || ok != test.ok {
+			t.Errorf("ParseMaxForwards(%q) = %d, %v; want %d, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseContentLengthList(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp int64
+		err error
+	}{
+		{"42", 42, nil},
+		{"42, 42", 42, nil},
+		{"42,42 ,42", 42, nil},
+		{"42, 43", 0, ErrContentLengthMismatch},
+		{"42, +42", 0, ErrMalformed},
+		{"42, -1", 0, ErrMalformed},
+		{"42;x", 0, ErrMalformed},
+		{"", 0, ErrMalformed},
+		{"42 42", 0, ErrMalformed},
+		{",42,", 0, ErrMalformed},
+		{"42,,42", 0, ErrMalformed},
+		{"42, ,42", 0, ErrMalformed},
+		{"42,", 0, ErrMalformed},
+	} {
+		act, err := ParseContentLengthList([]byte(test.in))
+		if act != test.exp || err != test.err {
+			t.Errorf("ParseContentLengthList(%q) = %d, %v; want %d, %v", test.in, act, err, test.exp, test.err)
+		}
+	}
+}
+
+func TestParseDeltaSeconds(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp int64
+		ok  bool
+	}{
+		{"60", 60, true},
+		{"2147483648", MaxDeltaSeconds, true},
+		{"99999999999999999999999", MaxDeltaSeconds, true},
+		{"-1", 0, false},
+		{"", 0, false},
+	} {
+		act, ok := ParseDeltaSeconds([]byte(test.in))
+		if act != test.exp || ok != test.ok {
+			t.Errorf("ParseDeltaSeconds(%q) = %d, %v; want %d, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
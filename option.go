@@ -36,11 +36,23 @@ func (opt Option) String() string {
 	return "{" + string(opt.Name) + " " + opt.Parameters.String() + "}"
 }
 
-// NewOption creates named option with given parameters.
+// NewOption creates named option with given parameters. Parameters are set in
+// order of their keys, such that written option does not depend on map
+// iteration order. Empty values are set as nil, that is, such parameters are
+// written as bare keys.
 func NewOption(name string, params map[string]string) Option {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
 	p := Parameters{}
-	for k, v := range params {
-		p.Set([]byte(k), []byte(v))
+	for _, k := range keys {
+		var v []byte
+		if s := params[k]; s != "" {
+			v = []byte(s)
+		}
+		p.Set([]byte(k), v)
 	}
 	return Option{
 		Name:       []byte(name),
@@ -56,6 +68,44 @@ func (opt Option) Equal(b Option) bool {
 	return false
 }
 
+// Options represents a list of header options.
+type Options []Option
+
+// String represents options as a header value, in the same form as
+// WriteOptions() does.
+func (opts Options) String() string {
+	return WriteOptionsString(opts)
+}
+
+// AppendTo appends options written in the same form as WriteOptions() does
+// to dst and returns the extended slice.
+func (opts Options) AppendTo(dst []byte) []byte {
+	buf := bytes.NewBuffer(dst)
+	_, _ = WriteOptions(buf, opts)
+	return buf.Bytes()
+}
+
+// ForEachErr calls cb for each option. It stops iteration and returns the
+// error returned by cb, if any.
+func (opts Options) ForEachErr(cb func(Option) error) error {
+	for _, opt := range opts {
+		if err := cb(opt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Strings returns each option written in the same form as WriteOptions()
+// does.
+func (opts Options) Strings() []string {
+	ret := make([]string, len(opts))
+	for i := range opts {
+		ret[i] = WriteOptionsString(opts[i : i+1])
+	}
+	return ret
+}
+
 // Parameters represents option's parameters.
 type Parameters struct {
 	pos   int
@@ -126,6 +176,24 @@ func (p *Parameters) Get(key string) (value []byte, ok bool) {
 	return nil, false
 }
 
+// GetExt returns value by key respecting RFC8187 extended parameter with the
+// same name followed by "*". That is, if there is a "key*" parameter with
+// valid ext-value, its decoded value is returned. Otherwise the value of plain
+// "key" parameter is returned, if any.
+//
+// Note that raw value of extended parameter is still available via Get().
+// See https://tools.ietf.org/html/rfc6266#section-4.3
+func (p *Parameters) GetExt(key string) (value []byte, ok bool) {
+	for _, v := range p.data() {
+		if len(v.key) == len(key)+1 && isExtName(v.key) && string(v.key[:len(key)]) == key {
+			if value, ok = extValue(v.value); ok {
+				return value, true
+			}
+		}
+	}
+	return p.Get(key)
+}
+
 // Set sets value by key.
 func (p *Parameters) Set(key, value []byte) {
 	p.bytes += len(key) + len(value)
@@ -152,6 +220,17 @@ func (p *Parameters) ForEach(cb func(k, v []byte) bool) {
 	}
 }
 
+// ForEachErr iterates over parameters key-value pairs and calls cb for each
+// one. It stops iteration and returns the error returned by cb, if any.
+func (p *Parameters) ForEachErr(cb func(k, v []byte) error) error {
+	for _, v := range p.data() {
+		if err := cb(v.key, v.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // String represents parameters as a string.
 func (p *Parameters) String() (ret string) {
 	ret = "["
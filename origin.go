@@ -0,0 +1,115 @@
+package httphead
+
+import "bytes"
+
+// MatchOrigin reports whether origin, such as value of Origin header, matches
+// any of allowed origins. Origins are compared by scheme, host and port:
+// scheme and host are compared case-insensitively and omitted port is treated
+// as default port of the scheme. That is, "https://example.com" matches
+// "HTTPS://Example.com:443". Allowed origin "*" matches any origin.
+//
+// Serialized origin is:
+//
+// origin = scheme "://" host [ ":" port ] / "null"
+//
+// Note that "null" origin matches only "null" or "*" allowed origins.
+// See https://tools.ietf.org/html/rfc6454#section-7
+func MatchOrigin(origin []byte, allowed ...[]byte) bool {
+	return matchOrigin(origin, allowed) != -1
+}
+
+// AppendAllowOrigin appends Access-Control-Allow-Origin header value for the
+// given request origin to dst. If origin matches one of allowed origins except
+// "*" (see MatchOrigin()), the origin itself is appended. If it matches only
+// "*", then "*" is appended.
+//
+// It returns false if origin is not allowed. In that case dst is returned as
+// is.
+func AppendAllowOrigin(dst, origin []byte, allowed ...[]byte) ([]byte, bool) {
+	i := matchOrigin(origin, allowed)
+	if i == -1 {
+		return dst, false
+	}
+	if isWildcard(allowed[i]) {
+		return append(dst, '*'), true
+	}
+	return append(dst, origin...), true
+}
+
+// matchOrigin returns index of the allowed origin matched by origin. It
+// prefers exact matches to "*" match.
+func matchOrigin(origin []byte, allowed [][]byte) int {
+	wildcard := -1
+	scheme, host, port, ok := splitOrigin(origin)
+	for i, a := range allowed {
+		if isWildcard(a) {
+			if wildcard == -1 {
+				wildcard = i
+			}
+			continue
+		}
+		if !ok {
+			if bytes.Equal(origin, a) && isNullOrigin(a) {
+				return i
+			}
+			continue
+		}
+		s, h, p, ok := splitOrigin(a)
+		if ok &&
+			bytes.EqualFold(scheme, s) &&
+			bytes.EqualFold(host, h) &&
+			bytes.Equal(port, p) {
+			return i
+		}
+	}
+	if wildcard != -1 && (ok || isNullOrigin(origin)) {
+		return wildcard
+	}
+	return -1
+}
+
+var (
+	schemeSeparator = []byte("://")
+	portHTTP        = []byte("80")
+	portHTTPS       = []byte("443")
+)
+
+// splitOrigin splits serialized origin into its parts. If port is omitted,
+// the default port of http, https, ws and wss schemes is returned.
+func splitOrigin(p []byte) (scheme, host, port []byte, ok bool) {
+	i := bytes.Index(p, schemeSeparator)
+	if i <= 0 {
+		return nil, nil, nil, false
+	}
+	scheme, host = p[:i], p[i+len(schemeSeparator):]
+	if j := bytes.LastIndexByte(host, ':'); j != -1 && bytes.IndexByte(host[j:], ']') == -1 {
+		host, port = host[:j], host[j+1:]
+		if _, ok := ParseDigits(port); !ok {
+			return nil, nil, nil, false
+		}
+		// Strip leading zeros.
+		for len(port) > 1 && port[0] == '0' {
+			port = port[1:]
+		}
+	}
+	if len(host) == 0 || bytes.IndexAny(host, "/?#@ ") != -1 {
+		return nil, nil, nil, false
+	}
+	if port == nil {
+		switch {
+		case bytes.EqualFold(scheme, []byte("http")), bytes.EqualFold(scheme, []byte("ws")):
+			port = portHTTP
+		case bytes.EqualFold(scheme, []byte("https")), bytes.EqualFold(scheme, []byte("wss")):
+			port = portHTTPS
+		}
+	}
+	return scheme, host, port, true
+}
+
+func isWildcard(p []byte) bool {
+	return len(p) == 1 && p[0] == '*'
+}
+
+func isNullOrigin(p []byte) bool {
+	return string(p) == "null"
+}
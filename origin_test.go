@@ -0,0 +1,39 @@
+package httphead
+
+import "testing"
+
+func TestMatchOrigin(t *testing.T) {
+	for _, test := range []struct {
+		origin  string
+		allowed []string
+		exp     bool
+		header  string
+	}{
+		{"https://example.com", []string{"https://example.com"}, true, "https://example.com"},
+		{"https://example.com", []string{"HTTPS://Example.COM:443"}, true, "https://example.com"},
+		{"http://example.com:80", []string{"http://example.com"}, true, "http://example.com:80"},
+		{"http://example.com:8080", []string{"http://example.com"}, false, ""},
+		{"http://example.com", []string{"https://example.com"}, false, ""},
+		{"https://evil.com", []string{"https://example.com", "*"}, true, "*"},
+		{"https://example.com", []string{"*", "https://example.com"}, true, "https://example.com"},
+		{"http://[::1]", []string{"http://[::1]:80"}, true, "http://[::1]"},
+		{"null", []string{"null"}, true, "null"},
+		{"null", []string{"*"}, true, "*"},
+		{"null", []string{"https://example.com"}, false, ""},
+		{"example.com", []string{"*"}, false, ""},
+		{"https://example.com/path", []string{"https://example.com"}, false, ""},
+		{"https://example.com:x", []string{"https://example.com"}, false, ""},
+	} {
+		allowed := make([][]byte, len(test.allowed))
+		for i, a := range test.allowed {
+			allowed[i] = []byte(a)
+		}
+		if act := MatchOrigin([]byte(test.origin), allowed...); act != test.exp {
+			t.Errorf("MatchOrigin(%q, %q) = %v; want %v", test.origin, test.allowed, act, test.exp)
+		}
+		act, ok := AppendAllowOrigin(nil, []byte(test.origin), allowed...)
+		if string(act) != test.header || ok != test.exp {
+			t.Errorf("AppendAllowOrigin(%q, %q) = %q, %v; want %q, %v", test.origin, test.allowed, act, ok, test.header, test.exp)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package httphead
+
+import "io"
+
+// Preference represents single preference of Prefer or Preference-Applied
+// header value, such as "return=minimal" or "respond-async".
+// See https://tools.ietf.org/html/rfc7240#section-2
+type Preference struct {
+	// Name is the preference token. Note that preference names are
+	// case-insensitive, but kept as is.
+	Name []byte
+
+	// Value is the preference value. It is nil if preference has no value.
+	Value []byte
+
+	// Parameters contains preference parameters. Parameters without value
+	// have nil value.
+	Parameters Parameters
+}
+
+// ParsePrefer parses Prefer header value and appends preferences to given
+// slice in order of their appearance:
+//
+// Prefer     = 1#preference
+// preference = token [ BWS "=" BWS word ] *( OWS ";" [ OWS parameter ] )
+// parameter  = token [ BWS "=" BWS word ]
+// word       = token / quoted-string
+//
+// Note that appended preferences consist of subslices of data, except
+// quoted-string values with escaped characters. It returns false if data is
+// malformed.
+// See https://tools.ietf.org/html/rfc7240#section-2
+func ParsePrefer(data []byte, prefs []Preference) ([]Preference, bool) {
+	return parsePreferences(data, prefs, true)
+}
+
+// ParsePreferenceApplied parses Preference-Applied header value and appends
+// applied preferences to given slice in order of their appearance:
+//
+// Preference-Applied = 1#applied-pref
+// applied-pref       = token [ BWS "=" BWS word ]
+//
+// It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc7240#section-3
+func ParsePreferenceApplied(data []byte, prefs []Preference) ([]Preference, bool) {
+	return parsePreferences(data, prefs, false)
+}
+
+func parsePreferences(data []byte, prefs []Preference, params bool) ([]Preference, bool) {
+	const (
+		stateName = iota
+		stateAfterName
+		stateValue
+		stateAfterValue
+		stateParamName
+		stateParamAfterName
+		stateParamValue
+	)
+	var (
+		n     = len(prefs)
+		state = stateName
+		param []byte
+	)
+	lexer := newScanner(data, ScanNoComments)
+	for lexer.Next() {
+		t, v := lexer.Type(), lexer.Bytes()
+		word := t == ItemToken || t == ItemString
+		sep := byte(0)
+		if t == ItemSeparator {
+			sep = v[0]
+		}
+		switch {
+		case state == stateName && t == ItemToken:
+			prefs = append(prefs, Preference{Name: v})
+			state = stateAfterName
+
+		case state == stateName && sep == ',':
+
+		case state == stateAfterName && sep == '=':
+			state = stateValue
+
+		case state == stateValue && word:
+			prefs[len(prefs)-1].Value = v
+			state = stateAfterValue
+
+		case state == stateParamName && t == ItemToken:
+			param = v
+			state = stateParamAfterName
+
+		case state == stateParamAfterName && sep == '=':
+			state = stateParamValue
+
+		case state == stateParamValue && word:
+			prefs[len(prefs)-1].Parameters.Set(param, v)
+			state = stateAfterValue
+
+		case params && sep == ';' && state != stateName && state != stateValue && state != stateParamValue:
+			// Note that parameters are only allowed after some preference.
+			if state == stateParamAfterName {
+				prefs[len(prefs)-1].Parameters.Set(param, nil)
+			}
+			state = stateParamName
+
+		case sep == ',' && state != stateValue && state != stateParamValue:
+			if state == stateParamAfterName {
+				prefs[len(prefs)-1].Parameters.Set(param, nil)
+			}
+			state = stateName
+
+		default:
+			return prefs[:n], false
+		}
+	}
+	switch state {
+	case stateValue, stateParamValue:
+		return prefs[:n], false
+	case stateParamAfterName:
+		prefs[len(prefs)-1].Parameters.Set(param, nil)
+	}
+	if lexer.err != nil || len(prefs) == n {
+		return prefs[:n], false
+	}
+	return prefs, true
+}
+
+// WritePrefer writes preferences list to the dest in the form of Prefer or
+// Preference-Applied header value. Values are written as quoted-strings if
+// they contain non-token characters.
+func WritePrefer(dest io.Writer, prefs []Preference) (n int, err error) {
+	w := writer{w: dest}
+	for i, p := range prefs {
+		if i > 0 {
+			w.write(comma)
+		}
+		writeTokenSanitized(&w, p.Name)
+		if p.Value != nil {
+			w.write(equality)
+			writeWord(&w, p.Value)
+		}
+		for _, param := range p.Parameters.data() {
+			w.write(semicolon)
+			writeTokenSanitized(&w, param.key)
+			if param.value != nil {
+				w.write(equality)
+				writeWord(&w, param.value)
+			}
+		}
+	}
+	return w.result()
+}
+
+// writeWord writes token or quoted-string, including empty one.
+func writeWord(bw *writer, bts []byte) {
+	if len(bts) == 0 {
+		bw.write(quote)
+		bw.write(quote)
+		return
+	}
+	writeTokenSanitized(bw, bts)
+}
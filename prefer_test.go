@@ -0,0 +1,73 @@
+package httphead
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParsePrefer(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{
+			in:  `respond-async`,
+			exp: `respond-async`,
+			ok:  true,
+		},
+		{
+			in:  `respond-async, wait=100`,
+			exp: `respond-async,wait=100`,
+			ok:  true,
+		},
+		{
+			in:  `return = representation ; foo=bar;; baz , ,handling="lenient"`,
+			exp: `return=representation;foo=bar;baz,handling=lenient`,
+			ok:  true,
+		},
+		{
+			in:  `foo; bar="a b"; baz=""`,
+			exp: `foo;bar="a b";baz=""`,
+			ok:  true,
+		},
+		{in: ``},
+		{in: `,`},
+		{in: `wait=`},
+		{in: `wait=100=1`},
+		{in: `wait 100`},
+		{in: `foo;bar=`},
+		{in: `foo;=bar`},
+		{in: `foo (comment)`},
+		{in: `"foo"`},
+		{in: `;a`},
+		{in: `; a=1`},
+		{in: `,;x`},
+	} {
+		prefs, ok := ParsePrefer([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParsePrefer(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		var buf bytes.Buffer
+		if _, err := WritePrefer(&buf, prefs); err != nil {
+			t.Fatal(err)
+		}
+		if act := buf.String(); act != test.exp {
+			t.Errorf("ParsePrefer(%q) = %s; want %s", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestParsePreferenceApplied(t *testing.T) {
+	prefs, ok := ParsePreferenceApplied([]byte(`return=minimal, respond-async`), nil)
+	if !ok || len(prefs) != 2 || string(prefs[0].Value) != "minimal" || prefs[1].Value != nil {
+		t.Errorf("ParsePreferenceApplied() = %v, %v; want two preferences", prefs, ok)
+	}
+	if _, ok := ParsePreferenceApplied([]byte(`return=minimal;foo`), nil); ok {
+		t.Errorf("ParsePreferenceApplied() wellformed sign is true; want false")
+	}
+}
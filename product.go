@@ -0,0 +1,108 @@
+package httphead
+
+// Product represents single element of User-Agent or Server header value,
+// which is either product identifier or comment.
+// See https://tools.ietf.org/html/rfc9110#section-10.1.5
+type Product struct {
+	// Name is the product name, such as "Mozilla". It is empty for comments.
+	Name []byte
+
+	// Version is the optional product version, such as "5.0".
+	Version []byte
+
+	// Comment is the comment text without enclosing parentheses, such as
+	// "X11; Linux x86_64". Nested comments are kept as is, with their
+	// parentheses.
+	Comment []byte
+}
+
+// IsComment reports whether p represents a comment.
+func (p Product) IsComment() bool {
+	return p.Name == nil
+}
+
+// String returns string representation of p.
+func (p Product) String() string {
+	if p.IsComment() {
+		return "(" + string(p.Comment) + ")"
+	}
+	if p.Version == nil {
+		return string(p.Name)
+	}
+	return string(p.Name) + "/" + string(p.Version)
+}
+
+// ScanProducts scans User-Agent or Server header value:
+//
+// User-Agent      = product *( RWS ( product / comment ) )
+// product         = token [ "/" product-version ]
+// product-version = token
+//
+// It calls it for every scanned product or comment. If it returns false,
+// scanning stops. Note that the first element must be a product.
+//
+// Scanned products consist of subslices of data, except comments with
+// escaped characters. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-10.1.5
+func ScanProducts(data []byte, it func(Product) bool) bool {
+	lexer := newScanner(data, 0)
+	var n int
+	for lexer.Next() {
+		var p Product
+		switch lexer.Type() {
+		case ItemComment:
+			if n == 0 {
+				return false
+			}
+			p.Comment = lexer.Bytes()
+
+		case ItemToken:
+			p.Name = lexer.Bytes()
+			if lexer.Peek() != '/' {
+				break
+			}
+			lexer.Advance(1)
+			if !OctetTypes[lexer.Peek()].IsToken() || !lexer.Next() {
+				return false
+			}
+			p.Version = lexer.Bytes()
+
+		default:
+			return false
+		}
+		n++
+		if !it(p) {
+			return true
+		}
+	}
+	return lexer.Err() == nil && n > 0
+}
+
+// ParseUserAgent parses User-Agent header value and appends products and
+// comments to given slice in order of their appearance. That is, for
+// "Mozilla/5.0 (X11; Linux x86_64) Firefox/115.0" it appends "Mozilla/5.0",
+// "(X11; Linux x86_64)" and "Firefox/115.0".
+//
+// See ScanProducts() for details.
+func ParseUserAgent(data []byte, products []Product) ([]Product, bool) {
+	return parseProducts(data, products)
+}
+
+// ParseServer parses Server header value and appends products and comments
+// to given slice in order of their appearance:
+//
+// Server = product *( RWS ( product / comment ) )
+//
+// See ScanProducts() for details.
+// See https://tools.ietf.org/html/rfc9110#section-10.2.4
+func ParseServer(data []byte, products []Product) ([]Product, bool) {
+	return parseProducts(data, products)
+}
+
+func parseProducts(data []byte, products []Product) ([]Product, bool) {
+	ok := ScanProducts(data, func(p Product) bool {
+		products = append(products, p)
+		return true
+	})
+	return products, ok
+}
@@ -0,0 +1,99 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{
+			in: "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0",
+			exp: []string{
+				"Mozilla/5.0",
+				"(X11; Linux x86_64; rv:109.0)",
+				"Gecko/20100101",
+				"Firefox/115.0",
+			},
+			ok: true,
+		},
+		{
+			in:  "curl/8.0.1",
+			exp: []string{"curl/8.0.1"},
+			ok:  true,
+		},
+		{
+			in:  "Bot (compatible; (nested) comment) Lib",
+			exp: []string{"Bot", "(compatible; (nested) comment)", "Lib"},
+			ok:  true,
+		},
+		{
+			in:  `Bot (escaped \) paren)`,
+			exp: []string{"Bot", "(escaped ) paren)"},
+			ok:  true,
+		},
+		{in: "", ok: false},
+		{in: "(comment) Bot/1.0", ok: false},
+		{in: "Bot/", ok: false},
+		{in: "Bot/ 1.0", ok: false},
+		{in: "Bot/1.0/2", exp: []string{"Bot/1.0"}, ok: false},
+		{in: `Bot "quoted"`, exp: []string{"Bot"}, ok: false},
+		{in: "Bot (unclosed", exp: []string{"Bot"}, ok: false},
+	} {
+		act, ok := ParseUserAgent([]byte(test.in), nil)
+		var strs []string
+		for _, p := range act {
+			strs = append(strs, p.String())
+		}
+		if ok != test.ok || !reflect.DeepEqual(strs, test.exp) {
+			t.Errorf("ParseUserAgent(%q) = %q, %v; want %q, %v", test.in, strs, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestProductIsComment(t *testing.T) {
+	products, ok := ParseUserAgent([]byte("Bot/1.0 (+http://example.com/bot) ()"), nil)
+	if !ok || len(products) != 3 {
+		t.Fatalf("ParseUserAgent() = %v, %v; want 3 products", products, ok)
+	}
+	for i, exp := range []bool{false, true, true} {
+		if act := products[i].IsComment(); act != exp {
+			t.Errorf("products[%d].IsComment() = %v; want %v", i, act, exp)
+		}
+	}
+	if act, exp := string(products[0].Version), "1.0"; act != exp {
+		t.Errorf("unexpected version: %q; want %q", act, exp)
+	}
+}
+
+func TestParseServer(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []Product
+		ok  bool
+	}{
+		{
+			in: "Apache/2.4.1 (Unix)",
+			exp: []Product{
+				{Name: []byte("Apache"), Version: []byte("2.4.1")},
+				{Comment: []byte("Unix")},
+			},
+			ok: true,
+		},
+		{
+			in:  "nginx",
+			exp: []Product{{Name: []byte("nginx")}},
+			ok:  true,
+		},
+		{in: "(Unix)", ok: false},
+	} {
+		act, ok := ParseServer([]byte(test.in), nil)
+		if ok != test.ok || !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseServer(%q) = %v, %v; want %v, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package httphead
+
+// Profile bundles tolerance to the legacy or non-compliant constructions into
+// a single configuration, which could be applied to options, tokens and
+// cookie scanning. Zero Profile is the strictest one. Note that only
+// EmptyElements and BWS apply to cookie scanning, since cookie syntax has no
+// folding, quoted-strings or parameters; the rest of cookie scanning
+// configuration is taken from Cookie field.
+type Profile struct {
+	// ObsFold allows obsolete line folding (CRLF followed by SP or HT).
+	ObsFold bool
+
+	// ObsText allows non-ASCII bytes inside quoted-strings and comments.
+	ObsText bool
+
+	// EmptyElements allows empty list elements, such as "a,,b". For cookie
+	// scanning, if false, malformed pairs make scanning fail instead of
+	// being skipped.
+	EmptyElements bool
+
+	// BWS allows whitespace around "=" of parameters and after parameter
+	// values. For cookie scanning, if false, exactly one SP is required
+	// after ";" separating cookie pairs, unless Cookie.Space is set.
+	BWS bool
+
+	// BareSemicolons allows empty parameters, such as "a;;b=1".
+	BareSemicolons bool
+
+	// Flags contains additional flags for list scanning.
+	Flags ScanFlag
+
+	// Cookie contains base configuration of cookie scanning, which is
+	// adjusted by the fields above. See CookieScanner() method.
+	Cookie CookieScanner
+}
+
+var (
+	// ProfileStrictRFC9110 accepts only values which are wellformed according
+	// to RFC9110 and RFC6265.
+	ProfileStrictRFC9110 = Profile{
+		Flags: ScanRejectControl | ScanRequireComma,
+		Cookie: CookieScanner{
+			Strict: true,
+		},
+	}
+
+	// ProfileCompatible2616 accepts values in the same way as RFC2616 does.
+	// It is the default behavior of the package scanners.
+	ProfileCompatible2616 = Profile{
+		ObsFold:       true,
+		ObsText:       true,
+		EmptyElements: true,
+		BWS:           true,
+		Cookie:        DefaultCookieScanner,
+	}
+
+	// ProfileBrowserLenient accepts malformed values which are often sent by
+	// the browsers or other clients in the wild.
+	ProfileBrowserLenient = Profile{
+		ObsFold:        true,
+		ObsText:        true,
+		EmptyElements:  true,
+		BWS:            true,
+		BareSemicolons: true,
+		Cookie: CookieScanner{
+			NameMode:               CookieNameVerbatim,
+			DisableValueValidation: true,
+		},
+	}
+)
+
+// ScanFlags returns scan flags which correspond to the profile.
+func (p Profile) ScanFlags() ScanFlag {
+	f := p.Flags
+	if !p.ObsFold {
+		f |= ScanRejectObsFold
+	}
+	if !p.ObsText {
+		f |= ScanRejectObsText
+	}
+	if !p.EmptyElements {
+		f |= ScanRejectEmpty
+	}
+	if !p.BWS {
+		f |= ScanRejectBWS | ScanRejectTrailingSpace
+	}
+	if p.BareSemicolons {
+		f |= ScanBareSemicolons
+	}
+	return f
+}
+
+// ListScanner returns ListScanner configured with the profile.
+func (p Profile) ListScanner() ListScanner {
+	return ListScanner{Flags: p.ScanFlags()}
+}
+
+// CookieScanner returns CookieScanner configured with the profile. That is,
+// p.Cookie adjusted according to EmptyElements and BWS fields.
+func (p Profile) CookieScanner() CookieScanner {
+	c := p.Cookie
+	if !p.EmptyElements {
+		c.BreakOnPairError = true
+	}
+	if !p.BWS && c.Space == SpaceAuto {
+		c.Space = SpaceRequired
+	}
+	return c
+}
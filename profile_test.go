@@ -0,0 +1,101 @@
+package httphead
+
+import "testing"
+
+func TestProfile(t *testing.T) {
+	for _, test := range []struct {
+		in string
+
+		strict  bool
+		compat  bool
+		lenient bool
+	}{
+		{`foo;a=1, bar`, true, true, true},
+		{`foo;a=1,,bar`, false, true, true},
+		{`foo;a = 1`, false, true, true},
+		{`foo;a=1 ;b`, false, true, true},
+		{"foo;a=\"\xc3\xa9\"", false, true, true},
+		{"foo;a=1,\r\n bar", false, true, true},
+		{"foo;a=\"\x00\"", false, true, true},
+		{`foo;;a=1`, false, false, true},
+		{`foo;,bar`, false, false, true},
+		{`foo bar`, false, true, true},
+	} {
+		for _, p := range []struct {
+			name    string
+			profile Profile
+			exp     bool
+		}{
+			{"strict", ProfileStrictRFC9110, test.strict},
+			{"compat", ProfileCompatible2616, test.compat},
+			{"lenient", ProfileBrowserLenient, test.lenient},
+		} {
+			s := p.profile.ListScanner()
+			ok := s.ScanOptions([]byte(test.in), func(_ int, _, _, _ []byte) Control {
+				return ControlContinue
+			})
+			if ok != p.exp {
+				t.Errorf("%s: ScanOptions(%q) = %v; want %v", p.name, test.in, ok, p.exp)
+			}
+		}
+	}
+}
+
+func TestProfileCookie(t *testing.T) {
+	for _, test := range []struct {
+		in string
+
+		strict  int
+		compat  int
+		lenient int
+
+		// strictOK is the expected result of strict scanning; other
+		// presets skip malformed pairs and never fail.
+		strictOK bool
+	}{
+		{`foo=bar; bar=baz`, 2, 2, 2, true},
+		{`foo=bar;bar=baz`, 1, 2, 2, false},
+		{`foo=a b; bar=baz`, 0, 2, 2, false},
+		{`foo=a\b; bar=baz`, 0, 1, 2, false},
+		{`f@o=bar; bar=baz`, 0, 1, 2, false},
+		{`foo=bar; baz; bar=baz`, 1, 3, 3, false},
+	} {
+		for _, p := range []struct {
+			name    string
+			profile Profile
+			exp     int
+			ok      bool
+		}{
+			{"strict", ProfileStrictRFC9110, test.strict, test.strictOK},
+			{"compat", ProfileCompatible2616, test.compat, true},
+			{"lenient", ProfileBrowserLenient, test.lenient, true},
+		} {
+			var n int
+			ok := p.profile.CookieScanner().Scan([]byte(test.in), func(_, _ []byte) bool {
+				n++
+				return true
+			})
+			if n != p.exp || ok != p.ok {
+				t.Errorf("%s: Scan(%q) returned %d pairs, %v; want %d, %v", p.name, test.in, n, ok, p.exp, p.ok)
+			}
+		}
+	}
+}
+
+func TestProfileCookieScanner(t *testing.T) {
+	p := Profile{}
+	if c := p.CookieScanner(); !c.BreakOnPairError || c.Space != SpaceRequired {
+		t.Errorf("zero Profile CookieScanner() = %+v; want strict pair and space handling", c)
+	}
+	p = Profile{
+		EmptyElements: true,
+		BWS:           true,
+	}
+	if c := p.CookieScanner(); c != (CookieScanner{}) {
+		t.Errorf("lenient Profile CookieScanner() = %+v; want zero CookieScanner", c)
+	}
+	p = Profile{Cookie: CookieScanner{Space: SpaceOptional}}
+	if c := p.CookieScanner(); c.Space != SpaceOptional {
+		t.Errorf("CookieScanner() overrides Cookie.Space: %v", c.Space)
+	}
+}
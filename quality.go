@@ -0,0 +1,59 @@
+package httphead
+
+// ParseQuality parses RFC7231 qvalue into fixed-point integer of thousandths.
+// That is, "0.5" is parsed into 500 and "1" into 1000:
+//
+// qvalue = ( "0" [ "." 0*3DIGIT ] ) / ( "1" [ "." 0*3("0") ] )
+//
+// It returns ErrQuality if value is out of 0..1 range or has more than three
+// digits after the decimal point.
+// See https://tools.ietf.org/html/rfc7231#section-5.3.1
+func ParseQuality(p []byte) (q uint16, err error) {
+	if len(p) == 0 || len(p) > 5 {
+		return 0, ErrQuality
+	}
+	switch p[0] {
+	case '0':
+	case '1':
+		q = 1000
+	default:
+		return 0, ErrQuality
+	}
+	if len(p) == 1 {
+		return q, nil
+	}
+	if p[1] != '.' {
+		return 0, ErrQuality
+	}
+	m := uint16(100)
+	for _, c := range p[2:] {
+		if c < '0' || c > '9' {
+			return 0, ErrQuality
+		}
+		q += uint16(c-'0') * m
+		m /= 10
+	}
+	if q > 1000 {
+		return 0, ErrQuality
+	}
+	return q, nil
+}
+
+// AppendQuality appends qvalue representation of fixed-point integer of
+// thousandths q to dst. That is, 500 is appended as "0.5" and 1000 as "1".
+// Trailing zeros are omitted. Values greater than 1000 are appended as "1".
+func AppendQuality(dst []byte, q uint16) []byte {
+	if q >= 1000 {
+		return append(dst, '1')
+	}
+	dst = append(dst, '0')
+	if q == 0 {
+		return dst
+	}
+	dst = append(dst, '.')
+	for m := uint16(100); q > 0; m /= 10 {
+		dst = append(dst, '0'+byte(q/m))
+		q %= m
+	}
+	return dst
+}
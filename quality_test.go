@@ -0,0 +1,77 @@
+package httphead
+
+import "testing"
+
+var qualityCases = []struct {
+	in  string
+	q   uint16
+	err error
+}{
+	{in: "1", q: 1000},
+	{in: "1.", q: 1000},
+	{in: "1.000", q: 1000},
+	{in: "0", q: 0},
+	{in: "0.5", q: 500},
+	{in: "0.05", q: 50},
+	{in: "0.123", q: 123},
+	{in: "0.1234", err: ErrQuality},
+	{in: "1.5", err: ErrQuality},
+	{in: "1.001", err: ErrQuality},
+	{in: "2", err: ErrQuality},
+	{in: ".5", err: ErrQuality},
+	{in: "0,5", err: ErrQuality},
+	{in: "0.a", err: ErrQuality},
+	{in: "", err: ErrQuality},
+}
+
+func TestParseQuality(t *testing.T) {
+	for _, test := range qualityCases {
+		t.Run(test.in, func(t *testing.T) {
+			q, err := ParseQuality([]byte(test.in))
+			if err != test.err {
+				t.Fatalf("ParseQuality(%q) error is %v; want %v", test.in, err, test.err)
+			}
+			if q != test.q {
+				t.Errorf("ParseQuality(%q) = %d; want %d", test.in, q, test.q)
+			}
+		})
+	}
+}
+
+func TestAppendQuality(t *testing.T) {
+	for _, test := range []struct {
+		q   uint16
+		exp string
+	}{
+		{1000, "1"},
+		{1500, "1"},
+		{0, "0"},
+		{500, "0.5"},
+		{50, "0.05"},
+		{5, "0.005"},
+		{123, "0.123"},
+		{120, "0.12"},
+	} {
+		act := AppendQuality([]byte("q="), test.q)
+		if exp := "q=" + test.exp; string(act) != exp {
+			t.Errorf("AppendQuality(%d) = %q; want %q", test.q, act, exp)
+		}
+	}
+	for q := uint16(0); q <= 1000; q++ {
+		p := AppendQuality(nil, q)
+		if act, err := ParseQuality(p); err != nil || act != q {
+			t.Fatalf("ParseQuality(AppendQuality(%d)) = %d, %v; want %d", q, act, err, q)
+		}
+	}
+}
+
+func BenchmarkParseQuality(b *testing.B) {
+	for _, bench := range qualityCases {
+		p := []byte(bench.in)
+		b.Run(bench.in, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = ParseQuality(p)
+			}
+		})
+	}
+}
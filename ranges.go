@@ -0,0 +1,257 @@
+package httphead
+
+import (
+	"bytes"
+	"sort"
+	"time"
+)
+
+// ScanAcceptRanges scans Accept-Ranges header value and calls it for each
+// range unit:
+//
+// Accept-Ranges     = acceptable-ranges
+// acceptable-ranges = 1#range-unit
+//
+// Reserved "none" unit, which means that no range units are supported, is not
+// passed to it. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-14.3
+func ScanAcceptRanges(data []byte, it func(unit []byte) bool) bool {
+	return ScanTokens(data, func(unit []byte) bool {
+		if bytes.EqualFold(unit, rangeUnitNone) {
+			return true
+		}
+		return it(unit)
+	})
+}
+
+// ParseAcceptRanges parses Accept-Ranges header value and appends range units
+// to given slice in order of their appearance. If value contains only reserved
+// "none" unit, none is true. Note that "none" itself is never appended.
+//
+// Appended units are subslices of data. It returns false if data is
+// malformed.
+// See https://tools.ietf.org/html/rfc9110#section-14.3
+func ParseAcceptRanges(data []byte, units [][]byte) (_ [][]byte, none, ok bool) {
+	n := len(units)
+	ok = ScanAcceptRanges(data, func(unit []byte) bool {
+		units = append(units, unit)
+		return true
+	})
+	if !ok {
+		return units[:n], false, false
+	}
+	return units, len(units) == n, true
+}
+
+var (
+	rangeUnitNone  = []byte("none")
+	rangeUnitBytes = []byte("bytes")
+)
+
+// ByteRange represents single range of "bytes" range unit, which is either
+// int-range, such as "0-499" or "500-", or suffix-range, such as "-500".
+// See https://tools.ietf.org/html/rfc9110#section-14.1.2
+type ByteRange struct {
+	// First and Last are positions of the first and the last bytes of the
+	// int-range. Last is -1 if it is omitted. Both are -1 for suffix-range.
+	First, Last int64
+
+	// SuffixLength is the length of suffix-range. It is meaningful only if
+	// First is -1.
+	SuffixLength int64
+}
+
+// IsSuffix reports whether r is a suffix-range.
+func (r ByteRange) IsSuffix() bool {
+	return r.First == -1
+}
+
+// Satisfiable reports whether r is satisfiable for representation with given
+// size. That is, int-range should start inside representation and
+// suffix-range should have non-zero length.
+func (r ByteRange) Satisfiable(size int64) bool {
+	if r.IsSuffix() {
+		return r.SuffixLength > 0 && size > 0
+	}
+	return r.First < size
+}
+
+// Resolve returns positions of the first and the last bytes of r for
+// representation with given size. It returns false if r is not satisfiable.
+func (r ByteRange) Resolve(size int64) (first, last int64, ok bool) {
+	if !r.Satisfiable(size) {
+		return 0, 0, false
+	}
+	if r.IsSuffix() {
+		first = size - r.SuffixLength
+		if first < 0 {
+			first = 0
+		}
+		return first, size - 1, true
+	}
+	last = r.Last
+	if last == -1 || last >= size {
+		last = size - 1
+	}
+	return r.First, last, true
+}
+
+// SplitRange splits Range header value into its range unit and range set:
+//
+// ranges-specifier = range-unit "=" range-set
+//
+// It is useful for units other than "bytes", which range set syntax is not
+// defined. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-14.2
+func SplitRange(data []byte) (unit, set []byte, ok bool) {
+	data = trim(data)
+	i := bytes.IndexByte(data, '=')
+	if i == -1 {
+		return nil, nil, false
+	}
+	unit, set = data[:i], data[i+1:]
+	if n, t := ScanToken(unit); t != ItemToken || n != len(unit) || len(set) == 0 {
+		return nil, nil, false
+	}
+	return unit, set, true
+}
+
+// ParseRange parses Range header value of "bytes" range unit and appends
+// parsed ranges to the given slice:
+//
+// Range        = ranges-specifier
+// range-set    = 1#range-spec
+// range-spec   = int-range / suffix-range / other-range
+// int-range    = first-pos "-" [ last-pos ]
+// suffix-range = "-" suffix-length
+//
+// It returns false if data is malformed or its range unit is not "bytes".
+// Use SplitRange() for other range units.
+// See https://tools.ietf.org/html/rfc9110#section-14.1.1
+func ParseRange(data []byte, ranges []ByteRange) ([]ByteRange, bool) {
+	unit, set, ok := SplitRange(data)
+	if !ok || !bytes.EqualFold(unit, rangeUnitBytes) {
+		return ranges, false
+	}
+	n := len(ranges)
+	for len(set) > 0 {
+		spec := set
+		if i := bytes.IndexByte(set, ','); i != -1 {
+			spec, set = set[:i], set[i+1:]
+		} else {
+			set = nil
+		}
+		if spec = trim(spec); len(spec) == 0 {
+			continue
+		}
+		r, ok := parseByteRange(spec)
+		if !ok {
+			return ranges[:n], false
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == n {
+		return ranges, false
+	}
+	return ranges, true
+}
+
+func parseByteRange(spec []byte) (r ByteRange, ok bool) {
+	i := bytes.IndexByte(spec, '-')
+	if i == -1 {
+		return r, false
+	}
+	if i == 0 {
+		r.First, r.Last = -1, -1
+		r.SuffixLength, ok = ParseContentLength(spec[1:])
+		return r, ok
+	}
+	if r.First, ok = ParseContentLength(spec[:i]); !ok {
+		return r, false
+	}
+	if i == len(spec)-1 {
+		r.Last = -1
+		return r, true
+	}
+	if r.Last, ok = ParseContentLength(spec[i+1:]); !ok || r.Last < r.First {
+		return r, false
+	}
+	return r, true
+}
+
+// CanonicalizeRanges resolves ranges for representation with given size,
+// drops unsatisfiable ones, sorts the rest by position and coalesces
+// overlapping or adjacent ranges. Resulting ranges are int-ranges with both
+// positions set. It reuses ranges storage.
+//
+// Note that server could ignore or reject the request if ranges are
+// overlapping or too many, since it could be an attack.
+// See https://tools.ietf.org/html/rfc9110#section-14.2
+func CanonicalizeRanges(ranges []ByteRange, size int64) []ByteRange {
+	rs := ranges[:0]
+	for _, r := range ranges {
+		first, last, ok := r.Resolve(size)
+		if !ok {
+			continue
+		}
+		rs = append(rs, ByteRange{First: first, Last: last})
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].First < rs[j].First
+	})
+	var n int
+	for i, r := range rs {
+		if i > 0 && r.First <= rs[n-1].Last+1 {
+			if r.Last > rs[n-1].Last {
+				rs[n-1].Last = r.Last
+			}
+			continue
+		}
+		rs[n] = r
+		n++
+	}
+	return rs[:n]
+}
+
+// IfRange represents If-Range header value, which is either an entity-tag or
+// an HTTP-date.
+// See https://tools.ietf.org/html/rfc9110#section-13.1.5
+type IfRange struct {
+	// ETag contains entity-tag if IsDate is false.
+	ETag ETag
+
+	// Date contains HTTP-date if IsDate is true.
+	Date time.Time
+
+	// IsDate reports whether value is an HTTP-date.
+	IsDate bool
+}
+
+// ParseIfRange parses If-Range header value. It treats data as an
+// entity-tag if it starts with double quote or weakness indicator, and as an
+// HTTP-date otherwise.
+//
+// It returns false if data is malformed.
+func ParseIfRange(data []byte) (r IfRange, ok bool) {
+	data = trim(data)
+	if len(data) > 0 && data[0] == '"' || bytes.HasPrefix(data, weakPrefix) {
+		r.ETag, ok = ParseETag(data)
+		return r, ok
+	}
+	r.Date, ok = ParseHTTPDate(data)
+	r.IsDate = ok
+	return r, ok
+}
+
+// Match reports whether range request condition is true for representation
+// with given current entity-tag and modification date. That is, it reports
+// whether requested range could be sent instead of the whole representation.
+//
+// Entity-tag condition is true only if both tags are strong and equal. Date
+// condition is true only if it exactly matches lastModified.
+func (r IfRange) Match(etag ETag, lastModified time.Time) bool {
+	if r.IsDate {
+		return !lastModified.IsZero() && r.Date.Equal(lastModified)
+	}
+	return r.ETag.StrongMatch(etag)
+}
@@ -0,0 +1,227 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanAcceptRanges(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{"bytes", []string{"bytes"}, true},
+		{"none", nil, true},
+		{"bytes, items", []string{"bytes", "items"}, true},
+		{"", nil, false},
+		{"bytes;q=1", []string{"bytes"}, false},
+	} {
+		var act []string
+		ok := ScanAcceptRanges([]byte(test.in), func(unit []byte) bool {
+			act = append(act, string(unit))
+			return true
+		})
+		if ok != test.ok || !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ScanAcceptRanges(%q) = %q, %v; want %q, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseAcceptRanges(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		exp  []string
+		none bool
+		ok   bool
+	}{
+		{"bytes", []string{"bytes"}, false, true},
+		{"none", nil, true, true},
+		{"NONE", nil, true, true},
+		{"none, bytes", []string{"bytes"}, false, true},
+		{"", nil, false, false},
+		{"bytes;q=1", nil, false, false},
+	} {
+		act, none, ok := ParseAcceptRanges([]byte(test.in), nil)
+		if ok != test.ok || none != test.none || !reflect.DeepEqual(stringsOf(act), test.exp) {
+			t.Errorf(
+				"ParseAcceptRanges(%q) = %q, %v, %v; want %q, %v, %v",
+				test.in, act, none, ok, test.exp, test.none, test.ok,
+			)
+		}
+	}
+}
+
+func TestIfRange(t *testing.T) {
+	date := time.Date(1994, time.November, 6, 8, 49, 37, 0, time.UTC)
+	strong := ETag{Tag: []byte("xyzzy")}
+	weak := ETag{Tag: []byte("xyzzy"), Weak: true}
+
+	for _, test := range []struct {
+		in       string
+		ok       bool
+		isDate   bool
+		etag     ETag
+		modified time.Time
+		match    bool
+	}{
+		{
+			in:    `"xyzzy"`,
+			ok:    true,
+			etag:  strong,
+			match: true,
+		},
+		{
+			in:    `"xyzzy"`,
+			ok:    true,
+			etag:  weak,
+			match: false,
+		},
+		{
+			in:    `W/"xyzzy"`,
+			ok:    true,
+			etag:  strong,
+			match: false,
+		},
+		{
+			in:    `"other"`,
+			ok:    true,
+			etag:  strong,
+			match: false,
+		},
+		{
+			in:       `Sun, 06 Nov 1994 08:49:37 GMT`,
+			ok:       true,
+			isDate:   true,
+			modified: date,
+			match:    true,
+		},
+		{
+			in:       `Sun, 06 Nov 1994 08:49:37 GMT`,
+			ok:       true,
+			isDate:   true,
+			modified: date.Add(time.Second),
+			match:    false,
+		},
+		{
+			in: `"xyzzy`,
+			ok: false,
+		},
+		{
+			in: `yesterday`,
+			ok: false,
+		},
+	} {
+		r, ok := ParseIfRange([]byte(test.in))
+		if ok != test.ok {
+			t.Errorf("ParseIfRange(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if r.IsDate != test.isDate {
+			t.Errorf("ParseIfRange(%q) IsDate = %v; want %v", test.in, r.IsDate, test.isDate)
+		}
+		if m := r.Match(test.etag, test.modified); m != test.match {
+			t.Errorf("ParseIfRange(%q).Match() = %v; want %v", test.in, m, test.match)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []ByteRange
+		ok  bool
+	}{
+		{
+			in:  `bytes=0-499`,
+			exp: []ByteRange{{0, 499, 0}},
+			ok:  true,
+		},
+		{
+			in:  `Bytes=0-499, 500-999 ,,9500-,-500`,
+			exp: []ByteRange{{0, 499, 0}, {500, 999, 0}, {9500, -1, 0}, {-1, -1, 500}},
+			ok:  true,
+		},
+		{in: `bytes=`},
+		{in: `bytes=,`},
+		{in: `bytes=500-499`},
+		{in: `bytes=a-b`},
+		{in: `bytes=-`},
+		{in: `bytes=0-1-2`},
+		{in: `bytes=0 -1`},
+		{in: `bytes=+0-1`},
+		{in: `items=0-1`},
+		{in: `bytes 0-1`},
+		{in: `bytes=99999999999999999999-`},
+	} {
+		act, ok := ParseRange([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseRange(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseRange(%q) = %v; want %v", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	unit, set, ok := SplitRange([]byte(` items=0-9 `))
+	if !ok || string(unit) != "items" || string(set) != "0-9" {
+		t.Errorf("SplitRange() = %q, %q, %v; want %q, %q, true", unit, set, ok, "items", "0-9")
+	}
+	if _, _, ok := SplitRange([]byte(`it ems=0-9`)); ok {
+		t.Errorf("SplitRange() wellformed sign is true; want false")
+	}
+}
+
+func TestCanonicalizeRanges(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		size int64
+		exp  []ByteRange
+	}{
+		{
+			in:   `bytes=0-499`,
+			size: 1000,
+			exp:  []ByteRange{{0, 499, 0}},
+		},
+		{
+			in:   `bytes=500-999,0-499`,
+			size: 1000,
+			exp:  []ByteRange{{0, 999, 0}},
+		},
+		{
+			in:   `bytes=900-,0-100,50-150,-50,2000-`,
+			size: 1000,
+			exp:  []ByteRange{{0, 150, 0}, {900, 999, 0}},
+		},
+		{
+			in:   `bytes=-5000,0-0`,
+			size: 1000,
+			exp:  []ByteRange{{0, 999, 0}},
+		},
+		{
+			in:   `bytes=1000-,-0`,
+			size: 1000,
+			exp:  []ByteRange{},
+		},
+		{
+			in:   `bytes=0-9,20-29`,
+			size: 25,
+			exp:  []ByteRange{{0, 9, 0}, {20, 24, 0}},
+		},
+	} {
+		rs, ok := ParseRange([]byte(test.in), nil)
+		if !ok {
+			t.Fatalf("ParseRange(%q) wellformed sign is false; want true", test.in)
+		}
+		if act := CanonicalizeRanges(rs, test.size); !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("CanonicalizeRanges(%q, %d) = %v; want %v", test.in, test.size, act, test.exp)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package httphead
+
+import "math"
+
+// RateLimit represents RateLimit header value.
+type RateLimit struct {
+	// Limit is the request quota in the current time window.
+	Limit int64
+
+	// Remaining is the remaining quota in the current time window.
+	Remaining int64
+
+	// Reset is the number of seconds until the quota resets.
+	Reset int64
+}
+
+// ParseRateLimit parses RateLimit header value in this form:
+//
+// RateLimit = limit=sf-integer, remaining=sf-integer, reset=sf-integer
+//
+// All of limit, remaining and reset keys must be present. Unknown keys are
+// ignored. It returns false if data is malformed.
+// See https://tools.ietf.org/html/draft-ietf-httpapi-ratelimit-headers-07
+func ParseRateLimit(data []byte) (r RateLimit, ok bool) {
+	const (
+		hasLimit = 1 << iota
+		hasRemaining
+		hasReset
+	)
+	var (
+		has   int
+		valid = true
+	)
+	ok = ScanDirectives(data, func(name, value []byte) Control {
+		var (
+			dst *int64
+			bit int
+		)
+		switch string(name) {
+		case "limit":
+			dst, bit = &r.Limit, hasLimit
+		case "remaining":
+			dst, bit = &r.Remaining, hasRemaining
+		case "reset":
+			dst, bit = &r.Reset, hasReset
+		default:
+			return ControlContinue
+		}
+		if *dst, valid = parseInt64(value); !valid {
+			return ControlBreak
+		}
+		has |= bit
+		return ControlContinue
+	})
+	if !ok || !valid || has != hasLimit|hasRemaining|hasReset {
+		return RateLimit{}, false
+	}
+	return r, true
+}
+
+// RateLimitPolicy represents single quota policy of RateLimit-Policy header
+// value.
+type RateLimitPolicy struct {
+	// Quota is the request quota of the policy.
+	Quota int64
+
+	// Window is the time window of the policy in seconds. It is zero if
+	// window is not specified.
+	Window int64
+}
+
+// ParseRateLimitPolicy parses RateLimit-Policy header value and appends
+// policies to given slice:
+//
+// RateLimit-Policy = 1#quota-policy
+// quota-policy     = sf-integer *( ";" parameter )
+//
+// Window of the policy is taken from "w" parameter. Other parameters are
+// ignored. It returns policies as is and false if data is malformed.
+// See https://tools.ietf.org/html/draft-ietf-httpapi-ratelimit-headers-07
+func ParseRateLimitPolicy(data []byte, policies []RateLimitPolicy) ([]RateLimitPolicy, bool) {
+	n := len(policies)
+	index := -1
+	valid := true
+	ok := ScanOptions(data, func(i int, name, attr, val []byte) Control {
+		if i != index {
+			index = i
+			quota, ok := parseInt64(name)
+			if !ok {
+				valid = false
+				return ControlBreak
+			}
+			policies = append(policies, RateLimitPolicy{Quota: quota})
+		}
+		if string(attr) == "w" {
+			p := &policies[len(policies)-1]
+			if p.Window, valid = parseInt64(val); !valid {
+				return ControlBreak
+			}
+		}
+		return ControlContinue
+	})
+	if !ok || !valid {
+		return policies[:n], false
+	}
+	return policies, true
+}
+
+func parseInt64(p []byte) (int64, bool) {
+	n, ok := ParseDigits(p)
+	if !ok || n > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(n), true
+}
@@ -0,0 +1,84 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp RateLimit
+		ok  bool
+	}{
+		{
+			in:  `limit=100, remaining=50, reset=30`,
+			exp: RateLimit{Limit: 100, Remaining: 50, Reset: 30},
+			ok:  true,
+		},
+		{
+			in:  `reset=30,remaining=0,limit=10,foo=bar`,
+			exp: RateLimit{Limit: 10, Remaining: 0, Reset: 30},
+			ok:  true,
+		},
+		{
+			in: `limit=100, remaining=50`,
+		},
+		{
+			in: `limit=100, remaining=-1, reset=30`,
+		},
+	} {
+		act, ok := ParseRateLimit([]byte(test.in))
+		if act != test.exp || ok != test.ok {
+			t.Errorf("ParseRateLimit(%q) = %+v, %v; want %+v, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseRateLimitPolicy(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []RateLimitPolicy
+		ok  bool
+	}{
+		{
+			in:  `100;w=60`,
+			exp: []RateLimitPolicy{{Quota: 100, Window: 60}},
+			ok:  true,
+		},
+		{
+			in: `10;w=1, 50;w=60;burst=1000, 1000`,
+			exp: []RateLimitPolicy{
+				{Quota: 10, Window: 1},
+				{Quota: 50, Window: 60},
+				{Quota: 1000},
+			},
+			ok: true,
+		},
+		{
+			in: `default;w=60`,
+		},
+		{
+			in: `100;w=x`,
+		},
+		{
+			in: `10;w=1, 50;w=x`,
+		},
+	} {
+		act, ok := ParseRateLimitPolicy([]byte(test.in), nil)
+		if len(act) == 0 && len(test.exp) == 0 {
+			act = test.exp
+		}
+		if !reflect.DeepEqual(act, test.exp) || ok != test.ok {
+			t.Errorf("ParseRateLimitPolicy(%q) = %+v, %v; want %+v, %v", test.in, act, ok, test.exp, test.ok)
+		}
+	}
+}
+
+func TestParseRateLimitPolicyAppend(t *testing.T) {
+	policies := []RateLimitPolicy{{Quota: 1}}
+	act, ok := ParseRateLimitPolicy([]byte(`10;w=1, 50;w=x`), policies)
+	if ok || !reflect.DeepEqual(act, policies) {
+		t.Errorf("ParseRateLimitPolicy() = %+v, %v; want %+v, false", act, ok, policies)
+	}
+}
@@ -0,0 +1,197 @@
+package httphead
+
+import (
+	"bytes"
+	"time"
+)
+
+// SameSite represents SameSite attribute of the Set-Cookie header.
+type SameSite byte
+
+const (
+	// SameSiteDefault means that attribute is missing or has unknown value.
+	SameSiteDefault SameSite = iota
+	SameSiteLax
+	SameSiteStrict
+	SameSiteNone
+)
+
+// String represents SameSite value as it appears in Set-Cookie header.
+func (s SameSite) String() string {
+	switch s {
+	case SameSiteLax:
+		return "Lax"
+	case SameSiteStrict:
+		return "Strict"
+	case SameSiteNone:
+		return "None"
+	default:
+		return ""
+	}
+}
+
+// SetCookie represents parsed value of the Set-Cookie header.
+// See https://tools.ietf.org/html/rfc6265#section-5.2
+type SetCookie struct {
+	Name, Value []byte
+
+	// Expires is zero if Expires attribute is missing or malformed.
+	Expires time.Time
+
+	// MaxAge follows the net/http convention: zero means that Max-Age
+	// attribute is missing or malformed, negative value means that it is
+	// "0" or less, that is, cookie must be removed immediately.
+	MaxAge int64
+
+	// Domain is the value of Domain attribute without leading ".".
+	Domain []byte
+
+	// Path is the value of Path attribute. It is nil if attribute is missing
+	// or does not begin with "/".
+	Path []byte
+
+	Secure      bool
+	HttpOnly    bool
+	Partitioned bool
+	SameSite    SameSite
+}
+
+// ScanSetCookie scans Set-Cookie header value:
+//
+// set-cookie-string = cookie-pair *( ";" SP cookie-av )
+//
+// It returns cookie name and value and calls it for every attribute with its
+// name and value. Value is nil for attributes without "=", such as "Secure".
+// Surrounding whitespace of attribute names and values is trimmed; quotes
+// around cookie value are removed. If it returns false, scanning stops.
+//
+// Note that name and value are subslices of data. It returns false if cookie
+// pair is malformed.
+// See https://tools.ietf.org/html/rfc6265#section-5.2
+func ScanSetCookie(data []byte, it func(attr, value []byte) bool) (name, value []byte, ok bool) {
+	if exceedsLimit(data) {
+		return nil, nil, false
+	}
+	pair := data
+	if i := bytes.IndexByte(data, ';'); i != -1 {
+		pair, data = data[:i], data[i+1:]
+	} else {
+		data = nil
+	}
+	i := bytes.IndexByte(pair, '=')
+	if i == -1 {
+		return nil, nil, false
+	}
+	name = trim(pair[:i])
+	value = stripQuotes(trim(pair[i+1:]))
+	if len(name) == 0 || !ValidCookieName(name) || !ValidCookieValue(value, false) {
+		return nil, nil, false
+	}
+	for len(data) > 0 {
+		av := data
+		if i := bytes.IndexByte(data, ';'); i != -1 {
+			av, data = data[:i], data[i+1:]
+		} else {
+			data = nil
+		}
+		var v []byte
+		if i := bytes.IndexByte(av, '='); i != -1 {
+			av, v = av[:i], trim(av[i+1:])
+		}
+		if av = trim(av); len(av) == 0 {
+			continue
+		}
+		if !it(av, v) {
+			break
+		}
+	}
+	return name, value, true
+}
+
+// ParseSetCookie parses Set-Cookie header value into SetCookie. Unknown and
+// malformed attributes are ignored as RFC6265 requires; if attribute appears
+// more than once, its last value is used.
+//
+// Note that returned byte slices are subslices of data. It returns false if
+// cookie pair is malformed.
+func ParseSetCookie(data []byte) (c SetCookie, ok bool) {
+	c.Name, c.Value, ok = ScanSetCookie(data, func(attr, value []byte) bool {
+		switch {
+		case bytes.EqualFold(attr, attrExpires):
+			if t, ok := ParseHTTPDate(value); ok {
+				c.Expires = t
+			}
+		case bytes.EqualFold(attr, attrMaxAge):
+			if n, ok := parseMaxAge(value); ok {
+				c.MaxAge = n
+			}
+		case bytes.EqualFold(attr, attrDomain):
+			if len(value) > 0 && value[0] == '.' {
+				value = value[1:]
+			}
+			if len(value) > 0 {
+				c.Domain = value
+			}
+		case bytes.EqualFold(attr, attrPath):
+			if len(value) > 0 && value[0] == '/' {
+				c.Path = value
+			} else {
+				c.Path = nil
+			}
+		case bytes.EqualFold(attr, attrSecure):
+			c.Secure = true
+		case bytes.EqualFold(attr, attrHttpOnly):
+			c.HttpOnly = true
+		case bytes.EqualFold(attr, attrPartitioned):
+			c.Partitioned = true
+		case bytes.EqualFold(attr, attrSameSite):
+			switch {
+			case bytes.EqualFold(value, sameSiteLax):
+				c.SameSite = SameSiteLax
+			case bytes.EqualFold(value, sameSiteStrict):
+				c.SameSite = SameSiteStrict
+			case bytes.EqualFold(value, sameSiteNone):
+				c.SameSite = SameSiteNone
+			default:
+				c.SameSite = SameSiteDefault
+			}
+		}
+		return true
+	})
+	if !ok {
+		return SetCookie{}, false
+	}
+	return c, true
+}
+
+// parseMaxAge parses Max-Age attribute value. Non-positive values are
+// returned as -1.
+func parseMaxAge(p []byte) (int64, bool) {
+	var neg bool
+	if len(p) > 0 && p[0] == '-' {
+		neg, p = true, p[1:]
+	}
+	n, ok := ParseDeltaSeconds(p)
+	if !ok {
+		return 0, false
+	}
+	if neg || n == 0 {
+		return -1, true
+	}
+	return n, true
+}
+
+var (
+	attrExpires     = []byte("Expires")
+	attrMaxAge      = []byte("Max-Age")
+	attrDomain      = []byte("Domain")
+	attrPath        = []byte("Path")
+	attrSecure      = []byte("Secure")
+	attrHttpOnly    = []byte("HttpOnly")
+	attrPartitioned = []byte("Partitioned")
+	attrSameSite    = []byte("SameSite")
+
+	sameSiteLax    = []byte("Lax")
+	sameSiteStrict = []byte("Strict")
+	sameSiteNone   = []byte("None")
+)
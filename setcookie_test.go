@@ -0,0 +1,100 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSetCookie(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp SetCookie
+		ok  bool
+	}{
+		{
+			in: `id=a3fWa`,
+			exp: SetCookie{
+				Name:  []byte("id"),
+				Value: []byte("a3fWa"),
+			},
+			ok: true,
+		},
+		{
+			in: `id="a3fWa"; Expires=Wed, 21 Oct 2015 07:28:00 GMT; Max-Age=3600; Domain=.example.com; Path=/docs; Secure; HttpOnly; SameSite=Lax; Partitioned`,
+			exp: SetCookie{
+				Name:        []byte("id"),
+				Value:       []byte("a3fWa"),
+				Expires:     time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC),
+				MaxAge:      3600,
+				Domain:      []byte("example.com"),
+				Path:        []byte("/docs"),
+				Secure:      true,
+				HttpOnly:    true,
+				Partitioned: true,
+				SameSite:    SameSiteLax,
+			},
+			ok: true,
+		},
+		{
+			in: `id=; max-age=0; samesite=strict;secure;;`,
+			exp: SetCookie{
+				Name:     []byte("id"),
+				Value:    []byte(""),
+				MaxAge:   -1,
+				Secure:   true,
+				SameSite: SameSiteStrict,
+			},
+			ok: true,
+		},
+		{
+			in: `id=1; Expires=never; Max-Age=1h; Path=docs; SameSite=Bogus; Foo=bar`,
+			exp: SetCookie{
+				Name:  []byte("id"),
+				Value: []byte("1"),
+			},
+			ok: true,
+		},
+		{
+			in: `id=1; Max-Age=-5; Max-Age=10; Domain=a.com; Domain=b.com`,
+			exp: SetCookie{
+				Name:   []byte("id"),
+				Value:  []byte("1"),
+				MaxAge: 10,
+				Domain: []byte("b.com"),
+			},
+			ok: true,
+		},
+		{in: ``},
+		{in: `id`},
+		{in: `=1; Secure`},
+		{in: `i d=1`},
+		{in: `id=a"b`},
+	} {
+		c, ok := ParseSetCookie([]byte(test.in))
+		if ok != test.ok {
+			t.Errorf("ParseSetCookie(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !reflect.DeepEqual(c, test.exp) {
+			t.Errorf("ParseSetCookie(%q) = %+v; want %+v", test.in, c, test.exp)
+		}
+	}
+}
+
+func TestScanSetCookie(t *testing.T) {
+	var act []string
+	name, value, ok := ScanSetCookie([]byte(`a=b; Path = /; Secure; X=1`), func(attr, value []byte) bool {
+		act = append(act, string(attr)+"="+string(value))
+		return string(attr) != "Secure"
+	})
+	if !ok {
+		t.Fatalf("ScanSetCookie() wellformed sign is false; want true")
+	}
+	if string(name) != "a" || string(value) != "b" {
+		t.Errorf("ScanSetCookie() = %q, %q; want %q, %q", name, value, "a", "b")
+	}
+	if exp := []string{"Path=/", "Secure="}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("ScanSetCookie() attributes = %q; want %q", act, exp)
+	}
+}
@@ -0,0 +1,674 @@
+// Package sfv provides parsing of RFC8941 Structured Field Values, such as
+// values of Priority, Client Hints or Signature headers. It also supports
+// Date and Display String types introduced by RFC9651.
+//
+// As the httphead package does, it avoids allocations where possible. That
+// is, parsed strings, tokens, byte sequences and keys are subslices of the
+// parsed data, except strings with escaped characters.
+package sfv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"time"
+	"unicode/utf8"
+)
+
+// Type describes type of the bare item.
+type Type byte
+
+const (
+	// TypeUndef reports that item is undefined.
+	TypeUndef Type = iota
+	// TypeInteger reports that item is sf-integer.
+	TypeInteger
+	// TypeDecimal reports that item is sf-decimal.
+	TypeDecimal
+	// TypeString reports that item is sf-string.
+	TypeString
+	// TypeToken reports that item is sf-token.
+	TypeToken
+	// TypeByteSequence reports that item is sf-binary.
+	TypeByteSequence
+	// TypeBoolean reports that item is sf-boolean.
+	TypeBoolean
+	// TypeDate reports that item is RFC9651 sf-date.
+	TypeDate
+	// TypeDisplayString reports that item is RFC9651 sf-displaystring.
+	TypeDisplayString
+)
+
+// String returns string representation of the type.
+func (t Type) String() string {
+	switch t {
+	case TypeInteger:
+		return "integer"
+	case TypeDecimal:
+		return "decimal"
+	case TypeString:
+		return "string"
+	case TypeToken:
+		return "token"
+	case TypeByteSequence:
+		return "byte sequence"
+	case TypeBoolean:
+		return "boolean"
+	case TypeDate:
+		return "date"
+	case TypeDisplayString:
+		return "display string"
+	default:
+		return "undefined"
+	}
+}
+
+// BareItem represents RFC8941 bare item. Only the fields corresponding to
+// the Type are meaningful.
+// See https://tools.ietf.org/html/rfc8941#section-3.3
+type BareItem struct {
+	Type Type
+
+	// Int contains value of integer or date in seconds since Unix epoch.
+	Int int64
+
+	// Decimal contains value of decimal.
+	Decimal float64
+
+	// Bool contains value of boolean.
+	Bool bool
+
+	// Bytes contains unescaped value of string, token, decoded UTF-8 value
+	// of display string, or base64-encoded value of byte sequence without
+	// surrounding colons.
+	Bytes []byte
+}
+
+// Time returns value of date as time.Time in UTC. It returns zero time if
+// item is not a date.
+func (b BareItem) Time() time.Time {
+	if b.Type != TypeDate {
+		return time.Time{}
+	}
+	return time.Unix(b.Int, 0).UTC()
+}
+
+// Decode appends decoded value of byte sequence to dst. It returns false if
+// item is not a byte sequence or its value is not a valid base64 text.
+func (b BareItem) Decode(dst []byte) ([]byte, bool) {
+	if b.Type != TypeByteSequence {
+		return dst, false
+	}
+	n := len(dst)
+	m := base64.StdEncoding.DecodedLen(len(b.Bytes))
+	if cap(dst)-n < m {
+		grow := make([]byte, n, n+m)
+		copy(grow, dst)
+		dst = grow
+	}
+	m, err := base64.StdEncoding.Decode(dst[n:n+m], b.Bytes)
+	if err != nil {
+		return dst[:n], false
+	}
+	return dst[:n+m], true
+}
+
+// Param represents single parameter of an item or inner list.
+type Param struct {
+	Key   []byte
+	Value BareItem
+}
+
+// Params represents ordered parameters of an item or inner list.
+// See https://tools.ietf.org/html/rfc8941#section-3.1.2
+type Params []Param
+
+// Get returns value of parameter with given key and flag about existence
+// such parameter.
+func (p Params) Get(key string) (BareItem, bool) {
+	for _, param := range p {
+		if string(param.Key) == key {
+			return param.Value, true
+		}
+	}
+	return BareItem{}, false
+}
+
+// Item represents RFC8941 item, that is, bare item with parameters.
+// See https://tools.ietf.org/html/rfc8941#section-3.3
+type Item struct {
+	BareItem
+	Params Params
+}
+
+// Member represents member of list or dictionary, which is either an item or
+// an inner list. Params contains parameters of the item or the inner list.
+type Member struct {
+	Item
+
+	// InnerList contains items of inner list if IsInnerList is true.
+	InnerList []Item
+
+	// IsInnerList reports whether member is an inner list.
+	IsInnerList bool
+}
+
+// DictMember represents member of dictionary with its key.
+type DictMember struct {
+	Key []byte
+	Member
+}
+
+// Dictionary represents ordered dictionary.
+// See https://tools.ietf.org/html/rfc8941#section-3.2
+type Dictionary []DictMember
+
+// Get returns member with given key and flag about existence such member.
+func (d Dictionary) Get(key string) (Member, bool) {
+	for _, m := range d {
+		if string(m.Key) == key {
+			return m.Member, true
+		}
+	}
+	return Member{}, false
+}
+
+// ParseItem parses structured field item from data:
+//
+// sf-item   = bare-item parameters
+// bare-item = sf-integer / sf-decimal / sf-string / sf-token / sf-binary / sf-boolean / sf-date / sf-displaystring
+//
+// It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc8941#section-4.2.3
+func ParseItem(data []byte) (item Item, ok bool) {
+	p := parser{data: data}
+	return p.topItem()
+}
+
+// ParseList parses structured field list from data and appends its members
+// to given slice:
+//
+// sf-list       = list-member *( OWS "," OWS list-member )
+// list-member   = sf-item / inner-list
+// inner-list    = "(" *SP [ sf-item *( 1*SP sf-item ) *SP ] ")" parameters
+//
+// Empty data is a valid empty list. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc8941#section-4.2.1
+func ParseList(data []byte, list []Member) ([]Member, bool) {
+	p := parser{data: data}
+	return p.list(list)
+}
+
+// ParseDictionary parses structured field dictionary from data and appends
+// its members to given dictionary:
+//
+// sf-dictionary = dict-member *( OWS "," OWS dict-member )
+// dict-member   = member-key ( parameters / ( "=" member-value ))
+// member-key    = key
+//
+// Member without value is a boolean true item. If key appears more than once,
+// the last value is used, but the member keeps position of the first one.
+// Empty data is a valid empty dictionary. It returns false if data is
+// malformed.
+// See https://tools.ietf.org/html/rfc8941#section-4.2.2
+func ParseDictionary(data []byte, dict Dictionary) (Dictionary, bool) {
+	p := parser{data: data}
+	return p.dictionary(dict)
+}
+
+// CheckItem checks that data is a valid structured field item. It returns
+// offset at which data became malformed and false, or len(data) and true if
+// data is wellformed.
+func CheckItem(data []byte) (offset int, ok bool) {
+	p := parser{data: data}
+	_, ok = p.topItem()
+	return p.offset(ok), ok
+}
+
+// CheckList checks that data is a valid structured field list. See
+// CheckItem() for returned values.
+func CheckList(data []byte) (offset int, ok bool) {
+	p := parser{data: data}
+	_, ok = p.list(nil)
+	return p.offset(ok), ok
+}
+
+// CheckDictionary checks that data is a valid structured field dictionary.
+// See CheckItem() for returned values.
+func CheckDictionary(data []byte) (offset int, ok bool) {
+	p := parser{data: data}
+	_, ok = p.dictionary(nil)
+	return p.offset(ok), ok
+}
+
+func (p *parser) topItem() (item Item, ok bool) {
+	p.skipSP()
+	if item, ok = p.item(); !ok {
+		return Item{}, false
+	}
+	p.skipSP()
+	if !p.eof() {
+		return Item{}, false
+	}
+	return item, true
+}
+
+func (p *parser) list(list []Member) ([]Member, bool) {
+	n := len(list)
+	p.skipSP()
+	for !p.eof() {
+		m, ok := p.member()
+		if !ok {
+			return list[:n], false
+		}
+		list = append(list, m)
+		if !p.next() {
+			return list[:n], false
+		}
+	}
+	return list, true
+}
+
+func (p *parser) dictionary(dict Dictionary) (Dictionary, bool) {
+	n := len(dict)
+	p.skipSP()
+	for !p.eof() {
+		key, ok := p.key()
+		if !ok {
+			return dict[:n], false
+		}
+		var m Member
+		if p.peek() == '=' {
+			p.pos++
+			if m, ok = p.member(); !ok {
+				return dict[:n], false
+			}
+		} else {
+			m.BareItem = BareItem{Type: TypeBoolean, Bool: true}
+			if m.Params, ok = p.params(); !ok {
+				return dict[:n], false
+			}
+		}
+		dict = setMember(dict, n, key, m)
+		if !p.next() {
+			return dict[:n], false
+		}
+	}
+	return dict, true
+}
+
+func setMember(dict Dictionary, n int, key []byte, m Member) Dictionary {
+	for i := n; i < len(dict); i++ {
+		if bytes.Equal(dict[i].Key, key) {
+			dict[i].Member = m
+			return dict
+		}
+	}
+	return append(dict, DictMember{Key: key, Member: m})
+}
+
+type parser struct {
+	data []byte
+	pos  int
+}
+
+// offset returns position at which parsing stopped.
+func (p *parser) offset(ok bool) int {
+	if ok {
+		return len(p.data)
+	}
+	return p.pos
+}
+
+func (p *parser) eof() bool {
+	return p.pos == len(p.data)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+func (p *parser) skipSP() {
+	for !p.eof() && p.data[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) skipOWS() {
+	for !p.eof() && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// next skips list members separator. It returns false if separator is
+// malformed or there is a trailing comma.
+func (p *parser) next() bool {
+	p.skipOWS()
+	if p.eof() {
+		return true
+	}
+	if p.data[p.pos] != ',' {
+		return false
+	}
+	p.pos++
+	p.skipOWS()
+	return !p.eof()
+}
+
+func (p *parser) member() (m Member, ok bool) {
+	if p.peek() != '(' {
+		m.Item, ok = p.item()
+		return m, ok
+	}
+	p.pos++
+	m.IsInnerList = true
+	for {
+		p.skipSP()
+		if p.peek() == ')' {
+			p.pos++
+			m.Params, ok = p.params()
+			return m, ok
+		}
+		item, ok := p.item()
+		if !ok {
+			return m, false
+		}
+		m.InnerList = append(m.InnerList, item)
+		if c := p.peek(); c != ' ' && c != ')' {
+			return m, false
+		}
+	}
+}
+
+func (p *parser) item() (item Item, ok bool) {
+	if item.BareItem, ok = p.bareItem(); !ok {
+		return item, false
+	}
+	item.Params, ok = p.params()
+	return item, ok
+}
+
+func (p *parser) params() (params Params, ok bool) {
+	for p.peek() == ';' {
+		p.pos++
+		p.skipSP()
+		key, ok := p.key()
+		if !ok {
+			return nil, false
+		}
+		value := BareItem{Type: TypeBoolean, Bool: true}
+		if p.peek() == '=' {
+			p.pos++
+			if value, ok = p.bareItem(); !ok {
+				return nil, false
+			}
+		}
+		params = setParam(params, key, value)
+	}
+	return params, true
+}
+
+func setParam(params Params, key []byte, value BareItem) Params {
+	for i := range params {
+		if bytes.Equal(params[i].Key, key) {
+			params[i].Value = value
+			return params
+		}
+	}
+	return append(params, Param{Key: key, Value: value})
+}
+
+func (p *parser) key() ([]byte, bool) {
+	start := p.pos
+	if c := p.peek(); !isLCAlpha(c) && c != '*' {
+		return nil, false
+	}
+	for p.pos++; !p.eof() && isKeyChar(p.data[p.pos]); p.pos++ {
+	}
+	return p.data[start:p.pos], true
+}
+
+func (p *parser) bareItem() (BareItem, bool) {
+	switch c := p.peek(); {
+	case c == '-' || isDigit(c):
+		return p.number()
+	case c == '"':
+		return p.string()
+	case c == '*' || isAlpha(c):
+		return p.token()
+	case c == ':':
+		return p.byteSequence()
+	case c == '?':
+		return p.boolean()
+	case c == '@':
+		return p.date()
+	case c == '%':
+		return p.displayString()
+	default:
+		return BareItem{}, false
+	}
+}
+
+func (p *parser) number() (b BareItem, ok bool) {
+	neg := p.peek() == '-'
+	if neg {
+		p.pos++
+	}
+	var (
+		n      int64
+		digits int
+		frac   int
+		point  = -1
+	)
+	for ; !p.eof(); p.pos++ {
+		c := p.data[p.pos]
+		if c == '.' && point == -1 {
+			if digits == 0 || digits > 12 {
+				return b, false
+			}
+			point = digits
+			continue
+		}
+		if !isDigit(c) {
+			break
+		}
+		n = n*10 + int64(c-'0')
+		if digits++; point == -1 && digits > 15 {
+			return b, false
+		}
+		if point != -1 {
+			if frac++; frac > 3 {
+				return b, false
+			}
+		}
+	}
+	if digits == 0 || point != -1 && frac == 0 {
+		return b, false
+	}
+	if neg {
+		n = -n
+	}
+	if point == -1 {
+		return BareItem{Type: TypeInteger, Int: n}, true
+	}
+	d := float64(n)
+	for i := 0; i < frac; i++ {
+		d /= 10
+	}
+	return BareItem{Type: TypeDecimal, Decimal: d}, true
+}
+
+func (p *parser) string() (b BareItem, ok bool) {
+	p.pos++
+	start := p.pos
+	var buf []byte
+	for ; !p.eof(); p.pos++ {
+		switch c := p.data[p.pos]; {
+		case c == '\\':
+			if p.pos+1 == len(p.data) {
+				return b, false
+			}
+			if e := p.data[p.pos+1]; e != '"' && e != '\\' {
+				return b, false
+			}
+			if buf == nil {
+				buf = make([]byte, 0, len(p.data)-start)
+			}
+			buf = append(buf, p.data[start:p.pos]...)
+			p.pos++
+			start = p.pos
+		case c == '"':
+			v := p.data[start:p.pos]
+			if buf != nil {
+				v = append(buf, v...)
+			}
+			p.pos++
+			return BareItem{Type: TypeString, Bytes: v}, true
+		case c < 0x20 || c > 0x7e:
+			return b, false
+		}
+	}
+	return b, false
+}
+
+func (p *parser) token() (BareItem, bool) {
+	start := p.pos
+	for p.pos++; !p.eof() && isTokenChar(p.data[p.pos]); p.pos++ {
+	}
+	return BareItem{Type: TypeToken, Bytes: p.data[start:p.pos]}, true
+}
+
+func (p *parser) byteSequence() (b BareItem, ok bool) {
+	p.pos++
+	start := p.pos
+	for ; !p.eof(); p.pos++ {
+		c := p.data[p.pos]
+		if c == ':' {
+			v := p.data[start:p.pos]
+			p.pos++
+			return BareItem{Type: TypeByteSequence, Bytes: v}, true
+		}
+		if !isAlpha(c) && !isDigit(c) && c != '+' && c != '/' && c != '=' {
+			return b, false
+		}
+	}
+	return b, false
+}
+
+func (p *parser) boolean() (b BareItem, ok bool) {
+	if p.pos+1 == len(p.data) {
+		return b, false
+	}
+	switch p.data[p.pos+1] {
+	case '0':
+	case '1':
+		b.Bool = true
+	default:
+		return b, false
+	}
+	p.pos += 2
+	b.Type = TypeBoolean
+	return b, true
+}
+
+// date parses RFC9651 sf-date:
+//
+// sf-date = "@" sf-integer
+//
+// See https://www.rfc-editor.org/rfc/rfc9651#section-3.3.7
+func (p *parser) date() (b BareItem, ok bool) {
+	p.pos++
+	if b, ok = p.number(); !ok || b.Type != TypeInteger {
+		return BareItem{}, false
+	}
+	b.Type = TypeDate
+	return b, true
+}
+
+// displayString parses RFC9651 sf-displaystring:
+//
+// sf-displaystring = "%" DQUOTE *( unescaped / "\" / pct-encoded ) DQUOTE
+// pct-encoded      = "%" lc-hexdig lc-hexdig
+//
+// Decoded value must be a valid UTF-8 text.
+// See https://www.rfc-editor.org/rfc/rfc9651#section-3.3.8
+func (p *parser) displayString() (b BareItem, ok bool) {
+	if p.pos+1 == len(p.data) || p.data[p.pos+1] != '"' {
+		return b, false
+	}
+	p.pos += 2
+	start := p.pos
+	var buf []byte
+	for ; !p.eof(); p.pos++ {
+		switch c := p.data[p.pos]; {
+		case c == '%':
+			if p.pos+2 >= len(p.data) {
+				return b, false
+			}
+			hi, lo := unhex(p.data[p.pos+1]), unhex(p.data[p.pos+2])
+			if hi < 0 || lo < 0 {
+				return b, false
+			}
+			if buf == nil {
+				buf = make([]byte, 0, len(p.data)-start)
+			}
+			buf = append(buf, p.data[start:p.pos]...)
+			buf = append(buf, byte(hi<<4|lo))
+			p.pos += 2
+			start = p.pos + 1
+		case c == '"':
+			v := p.data[start:p.pos]
+			if buf != nil {
+				v = append(buf, v...)
+			}
+			if !utf8.Valid(v) {
+				return b, false
+			}
+			p.pos++
+			return BareItem{Type: TypeDisplayString, Bytes: v}, true
+		case c < 0x20 || c > 0x7e:
+			return b, false
+		}
+	}
+	return b, false
+}
+
+// unhex returns value of lowercase hexadecimal digit c or -1 if c is not
+// such digit.
+func unhex(c byte) int {
+	switch {
+	case isDigit(c):
+		return int(c - '0')
+	case 'a' <= c && c <= 'f':
+		return int(c-'a') + 10
+	}
+	return -1
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isLCAlpha(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isAlpha(c byte) bool {
+	return isLCAlpha(c) || 'A' <= c && c <= 'Z'
+}
+
+func isKeyChar(c byte) bool {
+	return isLCAlpha(c) || isDigit(c) || c == '_' || c == '-' || c == '.' || c == '*'
+}
+
+// isTokenChar reports whether c is allowed in sf-token after the first
+// character, that is, c is tchar, ":" or "/".
+func isTokenChar(c byte) bool {
+	switch c {
+	case '!', '#', '$', '%', '&', '\'', '*', '+', '-', '.', '^', '_', '`', '|', '~', ':', '/':
+		return true
+	}
+	return isAlpha(c) || isDigit(c)
+}
@@ -0,0 +1,280 @@
+package sfv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseItem(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{`42`, `integer(42)`, true},
+		{`-42`, `integer(-42)`, true},
+		{`999999999999999`, `integer(999999999999999)`, true},
+		{`4.5`, `decimal(4.5)`, true},
+		{`-0.125`, `decimal(-0.125)`, true},
+		{`"hello world"`, `string(hello world)`, true},
+		{`"a \"b\" \\c"`, `string(a "b" \c)`, true},
+		{`foo123/456:x`, `token(foo123/456:x)`, true},
+		{`*foo`, `token(*foo)`, true},
+		{`:cHJldGVuZCB0aGlzIGlzIGJpbmFyeSBjb250ZW50Lg==:`, `byte sequence(cHJldGVuZCB0aGlzIGlzIGJpbmFyeSBjb250ZW50Lg==)`, true},
+		{`?1`, `boolean(true)`, true},
+		{`?0`, `boolean(false)`, true},
+		{`  5;foo=bar;baz;q=0.5 `, `integer(5);foo=token(bar);baz=boolean(true);q=decimal(0.5)`, true},
+		{`1;a=1;a=2`, `integer(1);a=integer(2)`, true},
+
+		{``, ``, false},
+		{`1000000000000000`, ``, false},
+		{`1234567890123.0`, ``, false},
+		{`1.1234`, ``, false},
+		{`1.`, ``, false},
+		{`-`, ``, false},
+		{`"abc`, ``, false},
+		{`"a\b"`, ``, false},
+		{"\"a\tb\"", ``, false},
+		{`:abc`, ``, false},
+		{`:a*b:`, ``, false},
+		{`?2`, ``, false},
+		{`1 2`, ``, false},
+		{`1;A=1`, ``, false},
+		{`1;a=`, ``, false},
+		{"\t1", ``, false},
+		{`(1 2)`, ``, false},
+	} {
+		item, ok := ParseItem([]byte(test.in))
+		if ok != test.ok {
+			t.Errorf("ParseItem(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if act := dumpItem(item); ok && act != test.exp {
+			t.Errorf("ParseItem(%q) = %s; want %s", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{``, nil, true},
+		{`sugar, tea, rum`, []string{`token(sugar)`, `token(tea)`, `token(rum)`}, true},
+		{"a;x=1 ,\tb", []string{`token(a);x=integer(1)`, `token(b)`}, true},
+		{
+			`("foo" "bar");lvl=5, ( ), (1);a`,
+			[]string{`(string(foo) string(bar));lvl=integer(5)`, `()`, `(integer(1));a=boolean(true)`},
+			true,
+		},
+		{`a,`, nil, false},
+		{`a,,b`, nil, false},
+		{`a b`, nil, false},
+		{`(1 2`, nil, false},
+		{`(1,2)`, nil, false},
+		{`(1)x`, nil, false},
+	} {
+		list, ok := ParseList([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseList(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		var act []string
+		for _, m := range list {
+			act = append(act, dumpMember(m))
+		}
+		if !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseList(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		check  func([]byte) (int, bool)
+		offset int
+		ok     bool
+	}{
+		{`1;a`, CheckItem, 3, true},
+		{`1, 2`, CheckItem, 1, false},
+		{`a, (b c);x`, CheckList, 10, true},
+		{`a b`, CheckList, 2, false},
+		{`a=1, b=?2`, CheckList, 1, false},
+		{`a=1, b=?2`, CheckDictionary, 7, false},
+		{`a, b;x=1`, CheckDictionary, 8, true},
+	} {
+		offset, ok := test.check([]byte(test.in))
+		if offset != test.offset || ok != test.ok {
+			t.Errorf(
+				"check(%q) = %d, %v; want %d, %v",
+				test.in, offset, ok, test.offset, test.ok,
+			)
+		}
+	}
+}
+
+func TestParseDictionary(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{``, nil, true},
+		{
+			`en="Applepie", da=:w4ZibGV0w6ZydGU=:`,
+			[]string{`en=string(Applepie)`, `da=byte sequence(w4ZibGV0w6ZydGU=)`},
+			true,
+		},
+		{
+			`a=?0, b, c;foo=bar`,
+			[]string{`a=boolean(false)`, `b=boolean(true)`, `c=boolean(true);foo=token(bar)`},
+			true,
+		},
+		{
+			`u=1, i, a=2, u=3`,
+			[]string{`u=integer(3)`, `i=boolean(true)`, `a=integer(2)`},
+			true,
+		},
+		{
+			`rating=1.5, feelings=(joy sadness)`,
+			[]string{`rating=decimal(1.5)`, `feelings=(token(joy) token(sadness))`},
+			true,
+		},
+		{`A=1`, nil, false},
+		{`a=`, nil, false},
+		{`a=1,`, nil, false},
+		{`a=1 b=2`, nil, false},
+		{`=1`, nil, false},
+	} {
+		dict, ok := ParseDictionary([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseDictionary(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		var act []string
+		for _, m := range dict {
+			act = append(act, string(m.Key)+"="+dumpMember(m.Member))
+		}
+		if !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseDictionary(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestDictionaryGet(t *testing.T) {
+	dict, ok := ParseDictionary([]byte(`u=3, i`), nil)
+	if !ok {
+		t.Fatalf("ParseDictionary() wellformed sign is false; want true")
+	}
+	if m, ok := dict.Get("u"); !ok || m.Int != 3 {
+		t.Errorf("Get(u) = %v, %v; want 3, true", m.Int, ok)
+	}
+	if _, ok := dict.Get("x"); ok {
+		t.Errorf("Get(x) existence sign is true; want false")
+	}
+}
+
+func TestBareItemDecode(t *testing.T) {
+	item, _ := ParseItem([]byte(`:aGVsbG8=:`))
+	if act, ok := item.Decode(nil); !ok || string(act) != "hello" {
+		t.Errorf("Decode() = %q, %v; want %q, true", act, ok, "hello")
+	}
+	item, _ = ParseItem([]byte(`:aGVsbG8:`))
+	if _, ok := item.Decode(nil); ok {
+		t.Errorf("Decode() wellformed sign is true; want false")
+	}
+}
+
+func dumpBareItem(b BareItem) string {
+	var v string
+	switch b.Type {
+	case TypeInteger, TypeDate:
+		v = strconv.FormatInt(b.Int, 10)
+	case TypeDecimal:
+		v = strconv.FormatFloat(b.Decimal, 'f', -1, 64)
+	case TypeBoolean:
+		v = strconv.FormatBool(b.Bool)
+	default:
+		v = string(b.Bytes)
+	}
+	return b.Type.String() + "(" + v + ")"
+}
+
+func dumpParams(params Params) string {
+	var sb strings.Builder
+	for _, p := range params {
+		sb.WriteString(";" + string(p.Key) + "=" + dumpBareItem(p.Value))
+	}
+	return sb.String()
+}
+
+func dumpItem(item Item) string {
+	return dumpBareItem(item.BareItem) + dumpParams(item.Params)
+}
+
+func dumpMember(m Member) string {
+	if !m.IsInnerList {
+		return dumpItem(m.Item)
+	}
+	items := make([]string, len(m.InnerList))
+	for i, item := range m.InnerList {
+		items[i] = dumpItem(item)
+	}
+	return "(" + strings.Join(items, " ") + ")" + dumpParams(m.Params)
+}
+
+func TestParseItemRFC9651(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{`@1659578233`, `date(1659578233)`, true},
+		{`@-1;a`, `date(-1);a=boolean(true)`, true},
+		{`%"This is intended for display to %c3%bcsers."`, `display string(This is intended for display to üsers.)`, true},
+		{`%"a\b"`, `display string(a\b)`, true},
+		{`%""`, `display string()`, true},
+
+		{`@`, ``, false},
+		{`@1.5`, ``, false},
+		{`@x`, ``, false},
+		{`%"%C3%BC"`, ``, false},
+		{`%"%c3"`, ``, false},
+		{`%"%c"`, ``, false},
+		{`%"abc`, ``, false},
+		{`%abc`, ``, false},
+		{"%\"\xc3\xbc\"", ``, false},
+	} {
+		item, ok := ParseItem([]byte(test.in))
+		if ok != test.ok {
+			t.Errorf("ParseItem(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if act := dumpItem(item); ok && act != test.exp {
+			t.Errorf("ParseItem(%q) = %s; want %s", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestBareItemTime(t *testing.T) {
+	item, _ := ParseItem([]byte(`@1659578233`))
+	if act, exp := item.Time(), time.Date(2022, time.August, 4, 1, 57, 13, 0, time.UTC); !act.Equal(exp) {
+		t.Errorf("Time() = %v; want %v", act, exp)
+	}
+	item, _ = ParseItem([]byte(`1659578233`))
+	if act := item.Time(); !act.IsZero() {
+		t.Errorf("Time() = %v; want zero time", act)
+	}
+}
@@ -0,0 +1,39 @@
+package httphead
+
+import "strings"
+
+// ParseOptionsString is the same as ParseOptions() but accepts data as a
+// string.
+//
+// Note that appended options consist of subslices of data copy. That is, it
+// is safe to keep them after data is released.
+func ParseOptionsString(data string, options []Option) ([]Option, bool) {
+	return ParseOptions([]byte(data), options)
+}
+
+// SelectString is the same as Select() but accepts data as a string.
+func (s OptionSelector) SelectString(data string, options []Option) ([]Option, bool) {
+	return s.Select([]byte(data), options)
+}
+
+// WriteOptionsString is the same as WriteOptions() but returns options list
+// as a string.
+func WriteOptionsString(options []Option) string {
+	var sb strings.Builder
+	_, _ = WriteOptions(&sb, options)
+	return sb.String()
+}
+
+// ScanCookieString is the same as ScanCookie() but accepts data as a string
+// and passes strings to the callback.
+func ScanCookieString(data string, it func(key, value string) bool) bool {
+	return DefaultCookieScanner.ScanString(data, it)
+}
+
+// ScanString is the same as Scan() but accepts data as a string and passes
+// strings to the callback.
+func (c CookieScanner) ScanString(data string, it func(name, value string) bool) bool {
+	return c.Scan([]byte(data), func(name, value []byte) bool {
+		return it(string(name), string(value))
+	})
+}
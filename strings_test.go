@@ -0,0 +1,46 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOptionsString(t *testing.T) {
+	opts, ok := ParseOptionsString(`foo;a=1, bar;b="x y"`, nil)
+	if !ok {
+		t.Fatalf("ParseOptionsString() wellformed sign is false; want true")
+	}
+	exp := []string{`foo;a=1`, `bar;b="x y"`}
+	if act := Options(opts).Strings(); !reflect.DeepEqual(act, exp) {
+		t.Errorf("Strings() = %q; want %q", act, exp)
+	}
+	if act, exp := WriteOptionsString(opts), `foo;a=1,bar;b="x y"`; act != exp {
+		t.Errorf("WriteOptionsString() = %q; want %q", act, exp)
+	}
+}
+
+func TestSelectString(t *testing.T) {
+	s := OptionSelector{Flags: SelectUnique}
+	opts, ok := s.SelectString(`foo,foo;a=1,bar`, nil)
+	if !ok {
+		t.Fatalf("SelectString() wellformed sign is false; want true")
+	}
+	exp := []Option{NewOption("foo", nil), NewOption("bar", nil)}
+	if !optionsEqual(opts, exp) {
+		t.Errorf("SelectString() = %v; want %v", opts, exp)
+	}
+}
+
+func TestScanCookieString(t *testing.T) {
+	var act []string
+	ok := ScanCookieString(`foo=bar; bar="baz"`, func(name, value string) bool {
+		act = append(act, name+"="+value)
+		return true
+	})
+	if !ok {
+		t.Fatalf("ScanCookieString() wellformed sign is false; want true")
+	}
+	if exp := []string{"foo=bar", "bar=baz"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("ScanCookieString() = %q; want %q", act, exp)
+	}
+}
@@ -0,0 +1,218 @@
+package httphead
+
+import (
+	"strconv"
+
+	"github.com/gobwas/httphead/sfv"
+)
+
+// ListOptions appends members of structured field list to given slice of
+// Option, such that code written against ParseOptions() could consume
+// structured field headers:
+//
+// foo;a=1;b, bar;c="x y"
+//
+// Members must be tokens, which become option names. Parameter values must be
+// tokens, strings, integers or decimals, which become their textual
+// representation, or boolean true, which becomes parameter without value.
+// Inner lists, other types of members and parameters are not representable
+// as Option.
+//
+// Returned options consist of subslices of the parsed data, except integer
+// and decimal parameter values. It returns options as is and false if some
+// member is not representable.
+func ListOptions(list []sfv.Member, options []Option) ([]Option, bool) {
+	n := len(options)
+	for _, m := range list {
+		if m.IsInnerList || m.Type != sfv.TypeToken {
+			return options[:n], false
+		}
+		opt := Option{Name: m.Bytes}
+		if !paramsToParameters(m.Params, &opt.Parameters) {
+			return options[:n], false
+		}
+		options = append(options, opt)
+	}
+	return options, true
+}
+
+// DictionaryOptions appends members of structured field dictionary to given
+// slice of Option. Member keys become option names. Only members with
+// boolean true value are representable as Option, such as "a;x=1, b". See
+// ListOptions() for parameters conversion rules.
+//
+// It returns options as is and false if some member is not representable.
+func DictionaryOptions(dict sfv.Dictionary, options []Option) ([]Option, bool) {
+	n := len(options)
+	for _, m := range dict {
+		if m.IsInnerList || m.Type != sfv.TypeBoolean || !m.Bool {
+			return options[:n], false
+		}
+		opt := Option{Name: m.Key}
+		if !paramsToParameters(m.Params, &opt.Parameters) {
+			return options[:n], false
+		}
+		options = append(options, opt)
+	}
+	return options, true
+}
+
+// OptionsList appends options to given structured field list, such that
+// options parsed by ParseOptions() could be consumed by code written against
+// the sfv package. Option names become tokens, thus they must be valid
+// sf-token. Parameter keys must be valid structured field keys, that is,
+// lower case. Parameters without value become boolean true; values which are
+// valid integers or decimals become numbers, valid sf-token values become
+// tokens and other values become strings.
+//
+// It returns list as is and false if some option is not representable.
+// See https://tools.ietf.org/html/rfc8941#section-3.1
+func OptionsList(options []Option, list []sfv.Member) ([]sfv.Member, bool) {
+	n := len(list)
+	for _, opt := range options {
+		if !isSFToken(opt.Name) {
+			return list[:n], false
+		}
+		m := sfv.Member{Item: sfv.Item{
+			BareItem: sfv.BareItem{
+				Type:  sfv.TypeToken,
+				Bytes: opt.Name,
+			},
+		}}
+		var ok bool
+		if m.Params, ok = parametersToParams(&opt.Parameters); !ok {
+			return list[:n], false
+		}
+		list = append(list, m)
+	}
+	return list, true
+}
+
+// OptionsDictionary appends options to given structured field dictionary.
+// Option names become keys of members with boolean true value, thus they must
+// be valid structured field keys. If some name is met twice, the last option
+// overrides the previous one, as RFC8941 requires. See OptionsList() for
+// parameters conversion rules.
+//
+// It returns dict as is and false if some option is not representable.
+// See https://tools.ietf.org/html/rfc8941#section-3.2
+func OptionsDictionary(options []Option, dict sfv.Dictionary) (sfv.Dictionary, bool) {
+	n := len(dict)
+	for _, opt := range options {
+		if !isSFKey(opt.Name) {
+			return dict[:n], false
+		}
+		m := sfv.DictMember{Key: opt.Name}
+		m.Type = sfv.TypeBoolean
+		m.Bool = true
+		var ok bool
+		if m.Params, ok = parametersToParams(&opt.Parameters); !ok {
+			return dict[:n], false
+		}
+		i := n
+		for ; i < len(dict); i++ {
+			if string(dict[i].Key) == string(m.Key) {
+				break
+			}
+		}
+		if i < len(dict) {
+			dict[i] = m
+		} else {
+			dict = append(dict, m)
+		}
+	}
+	return dict, true
+}
+
+func paramsToParameters(params sfv.Params, dst *Parameters) bool {
+	for _, p := range params {
+		var value []byte
+		switch p.Value.Type {
+		case sfv.TypeToken, sfv.TypeString:
+			value = p.Value.Bytes
+		case sfv.TypeInteger:
+			value = strconv.AppendInt(nil, p.Value.Int, 10)
+		case sfv.TypeDecimal:
+			value = strconv.AppendFloat(nil, p.Value.Decimal, 'f', -1, 64)
+		case sfv.TypeBoolean:
+			if !p.Value.Bool {
+				return false
+			}
+		default:
+			return false
+		}
+		dst.Set(p.Key, value)
+	}
+	return true
+}
+
+func parametersToParams(p *Parameters) (params sfv.Params, ok bool) {
+	ok = true
+	p.ForEach(func(k, v []byte) bool {
+		if !isSFKey(k) {
+			ok = false
+			return false
+		}
+		var item sfv.BareItem
+		if item, ok = bareItem(v); ok {
+			params = append(params, sfv.Param{Key: k, Value: item})
+		}
+		return ok
+	})
+	return params, ok
+}
+
+// bareItem returns structured field bare item representing parameter value v.
+func bareItem(v []byte) (sfv.BareItem, bool) {
+	switch {
+	case v == nil:
+		return sfv.BareItem{Type: sfv.TypeBoolean, Bool: true}, true
+	case isSFToken(v):
+		return sfv.BareItem{Type: sfv.TypeToken, Bytes: v}, true
+	}
+	if item, ok := sfv.ParseItem(v); ok && len(item.Params) == 0 {
+		switch item.Type {
+		case sfv.TypeInteger, sfv.TypeDecimal:
+			return item.BareItem, true
+		}
+	}
+	for _, c := range v {
+		if c < 0x20 || c > 0x7e {
+			return sfv.BareItem{}, false
+		}
+	}
+	return sfv.BareItem{Type: sfv.TypeString, Bytes: v}, true
+}
+
+// isSFToken reports whether p is a valid structured field token:
+//
+// sf-token = ( ALPHA / "*" ) *( tchar / ":" / "/" )
+func isSFToken(p []byte) bool {
+	if len(p) == 0 || !isAlpha(p[0]) && p[0] != '*' {
+		return false
+	}
+	for _, c := range p[1:] {
+		if !OctetTypes[c].IsToken() && c != ':' && c != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+// isSFKey reports whether p is a valid structured field key:
+//
+// key = ( lcalpha / "*" ) *( lcalpha / DIGIT / "_" / "-" / "." / "*" )
+func isSFKey(p []byte) bool {
+	if len(p) == 0 || !('a' <= p[0] && p[0] <= 'z') && p[0] != '*' {
+		return false
+	}
+	for _, c := range p[1:] {
+		switch {
+		case 'a' <= c && c <= 'z', isDigit(c):
+		case c == '_', c == '-', c == '.', c == '*':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,180 @@
+package httphead
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gobwas/httphead/sfv"
+)
+
+func TestListOptions(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []Option
+		ok  bool
+	}{
+		{
+			in: `foo;a=1;b;c="x y", bar;d=tok;e=0.5`,
+			exp: []Option{
+				NewOption("foo", map[string]string{"a": "1", "c": "x y"}),
+				NewOption("bar", map[string]string{"d": "tok", "e": "0.5"}),
+			},
+			ok: true,
+		},
+		{in: `foo, "bar"`},
+		{in: `foo, (bar baz)`},
+		{in: `foo;a=?0`},
+		{in: `foo;a=:YQ==:`},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			list, ok := sfv.ParseList([]byte(test.in), nil)
+			if !ok {
+				t.Fatalf("can not parse list")
+			}
+			act, ok := ListOptions(list, nil)
+			if ok != test.ok {
+				t.Fatalf("ListOptions() ok = %v; want %v", ok, test.ok)
+			}
+			if !ok {
+				if len(act) != 0 {
+					t.Errorf("ListOptions() = %v; want empty", act)
+				}
+				return
+			}
+			if len(act) != len(test.exp) {
+				t.Fatalf("ListOptions() = %v; want %v", act, test.exp)
+			}
+			for i := range act {
+				if act[i].Name == nil || string(act[i].Name) != string(test.exp[i].Name) {
+					t.Errorf("ListOptions()[%d] = %v; want %v", i, act[i], test.exp[i])
+				}
+			}
+			// Bare parameter has no value.
+			if v, ok := act[0].Parameters.Get("b"); !ok || v != nil {
+				t.Errorf("unexpected bare parameter: %q %v", v, ok)
+			}
+			for _, k := range []string{"a", "c"} {
+				exp, _ := test.exp[0].Parameters.Get(k)
+				if v, _ := act[0].Parameters.Get(k); string(v) != string(exp) {
+					t.Errorf("parameter %q = %q; want %q", k, v, exp)
+				}
+			}
+		})
+	}
+}
+
+func TestDictionaryOptions(t *testing.T) {
+	dict, _ := sfv.ParseDictionary([]byte(`a;x=1, b`), nil)
+	act, ok := DictionaryOptions(dict, nil)
+	if !ok {
+		t.Fatalf("DictionaryOptions() ok = false")
+	}
+	exp := []Option{
+		NewOption("a", map[string]string{"x": "1"}),
+		NewOption("b", nil),
+	}
+	if fmt.Sprint(act) != fmt.Sprint(exp) {
+		t.Errorf("DictionaryOptions() = %v; want %v", act, exp)
+	}
+
+	dict, _ = sfv.ParseDictionary([]byte(`a=1`), nil)
+	if _, ok := DictionaryOptions(dict, nil); ok {
+		t.Errorf("DictionaryOptions() ok = true for non-boolean member")
+	}
+}
+
+func TestOptionsList(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{
+			in:  `foo;a=1;b;c="x y";d=tok;e=0.5`,
+			exp: `[token(foo)[a=integer(1) b=boolean(true) c=string(x y) d=token(tok) e=decimal(0.5)]]`,
+			ok:  true,
+		},
+		{in: `foo;A=1`},
+		{in: `1foo`},
+		{in: "foo;a=\"\xff\""},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			opts, ok := ParseOptions([]byte(test.in), nil)
+			if !ok {
+				t.Fatalf("can not parse options")
+			}
+			list, ok := OptionsList(opts, nil)
+			if ok != test.ok {
+				t.Fatalf("OptionsList() ok = %v; want %v", ok, test.ok)
+			}
+			if !ok {
+				return
+			}
+			if act := dumpMembers(list); act != test.exp {
+				t.Errorf("OptionsList() = %s; want %s", act, test.exp)
+			}
+		})
+	}
+}
+
+func TestOptionsDictionary(t *testing.T) {
+	opts, _ := ParseOptions([]byte(`a;x=1, b, a;y`), nil)
+	dict, ok := OptionsDictionary(opts, nil)
+	if !ok {
+		t.Fatalf("OptionsDictionary() ok = false")
+	}
+	var act string
+	for _, m := range dict {
+		act += string(m.Key) + "=" + dumpMembers([]sfv.Member{m.Member}) + " "
+	}
+	if exp := `a=[boolean(true)[y=boolean(true)]] b=[boolean(true)[]] `; act != exp {
+		t.Errorf("OptionsDictionary() = %s; want %s", act, exp)
+	}
+	opts, _ = ParseOptions([]byte(`Foo`), nil)
+	if _, ok := OptionsDictionary(opts, nil); ok {
+		t.Errorf("OptionsDictionary() ok = true for upper case key")
+	}
+}
+
+func TestOptionsListRoundTrip(t *testing.T) {
+	in := []byte(`foo;a=1;b;c="x y", bar;e=0.5`)
+	list, _ := sfv.ParseList(in, nil)
+	opts, ok := ListOptions(list, nil)
+	if !ok {
+		t.Fatalf("ListOptions() ok = false")
+	}
+	back, ok := OptionsList(opts, nil)
+	if !ok {
+		t.Fatalf("OptionsList() ok = false")
+	}
+	if act, exp := dumpMembers(back), dumpMembers(list); act != exp {
+		t.Errorf("round trip = %s; want %s", act, exp)
+	}
+}
+
+func dumpMembers(list []sfv.Member) string {
+	dump := func(b sfv.BareItem) string {
+		switch b.Type {
+		case sfv.TypeInteger:
+			return fmt.Sprintf("%s(%d)", b.Type, b.Int)
+		case sfv.TypeDecimal:
+			return fmt.Sprintf("%s(%g)", b.Type, b.Decimal)
+		case sfv.TypeBoolean:
+			return fmt.Sprintf("%s(%t)", b.Type, b.Bool)
+		default:
+			return fmt.Sprintf("%s(%s)", b.Type, b.Bytes)
+		}
+	}
+	var ret string
+	for _, m := range list {
+		ret += "[" + dump(m.BareItem) + "["
+		for i, p := range m.Params {
+			if i > 0 {
+				ret += " "
+			}
+			ret += string(p.Key) + "=" + dump(p.Value)
+		}
+		ret += "]]"
+	}
+	return ret
+}
@@ -0,0 +1,160 @@
+package httphead
+
+import (
+	"io"
+	"strings"
+)
+
+// TokenFlag encodes way of tokens list writing.
+type TokenFlag byte
+
+const (
+	// TokenUnique causes writer to skip tokens which were already written.
+	// Tokens are compared case-insensitively, thus it should not be used for
+	// case-sensitive tokens such as request methods.
+	TokenUnique TokenFlag = 1 << iota
+
+	// TokenLowercase causes writer to write tokens in lower case.
+	TokenLowercase
+
+	// TokenCanonical causes writer to write tokens in canonical case of
+	// header field names. That is, the first letter and any letter following
+	// a hyphen are in upper case, and the rest are in lower case, such as
+	// "Content-Type". It is ignored if TokenLowercase is set.
+	TokenCanonical
+)
+
+// String represents flag as string.
+func (f TokenFlag) String() string {
+	var flags [3]string
+	var n int
+	if f&TokenUnique != 0 {
+		flags[n] = "unique"
+		n++
+	}
+	if f&TokenLowercase != 0 {
+		flags[n] = "lowercase"
+		n++
+	}
+	if f&TokenCanonical != 0 {
+		flags[n] = "canonical"
+		n++
+	}
+	return "[" + strings.Join(flags[:n], "|") + "]"
+}
+
+// WriteTokens writes tokens list to the dest in the same form as
+// ScanTokens() parses, such as values of Connection, Vary, Allow or Trailer
+// headers:
+//
+// list = 1#token
+//
+// It returns ErrMalformed without writing anything if some of tokens is not a
+// valid token.
+func WriteTokens(dest io.Writer, tokens [][]byte, flags TokenFlag) (n int, err error) {
+	if !validTokens(tokens) {
+		return 0, ErrMalformed
+	}
+	w := writer{w: dest}
+	writeTokens(&w, tokens, flags)
+	return w.result()
+}
+
+// AppendTokens appends tokens list written in the same form as WriteTokens()
+// does to dst and returns the extended slice. It returns false and dst as is
+// if some of tokens is not a valid token.
+func AppendTokens(dst []byte, tokens [][]byte, flags TokenFlag) ([]byte, bool) {
+	if !validTokens(tokens) {
+		return dst, false
+	}
+	w := writer{buf: dst}
+	writeTokens(&w, tokens, flags)
+	return w.buf, true
+}
+
+func validTokens(tokens [][]byte) bool {
+	for _, t := range tokens {
+		if n, typ := ScanToken(t); typ != ItemToken || n != len(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeTokens(w *writer, tokens [][]byte, flags TokenFlag) {
+	var buf [32]byte
+	for i, t := range tokens {
+		// Note that the first token is never skipped.
+		if flags&TokenUnique != 0 && containsFold(tokens[:i], t) {
+			continue
+		}
+		if i > 0 {
+			w.write(comma)
+		}
+		switch {
+		case flags&TokenLowercase != 0:
+			w.write(appendCase(buf[:0], t, false))
+		case flags&TokenCanonical != 0:
+			w.write(appendCase(buf[:0], t, true))
+		default:
+			w.write(t)
+		}
+	}
+}
+
+// TokenEqualFold reports whether tokens a and b are equal under ASCII case
+// folding. Unlike bytes.EqualFold() it does not apply Unicode case folding,
+// which is not applicable for HTTP tokens.
+func TokenEqualFold(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if lowercase[a[i]] != lowercase[b[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+// FoldToken appends src to dst in ASCII lower case and returns the extended
+// slice. It could be used to fold src in place by passing src[:0] as dst.
+func FoldToken(dst, src []byte) []byte {
+	for _, c := range src {
+		dst = append(dst, lowercase[c])
+	}
+	return dst
+}
+
+// lowercase is a table of ASCII lower case octets.
+var lowercase [256]byte
+
+func init() {
+	for c := range lowercase {
+		lowercase[c] = byte(c)
+		if 'A' <= c && c <= 'Z' {
+			lowercase[c] |= toLower
+		}
+	}
+}
+
+func containsFold(list [][]byte, p []byte) bool {
+	for _, v := range list {
+		if TokenEqualFold(v, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendCase appends p to dst in lower case or, if canonical is true, in
+// canonical case of header field names.
+func appendCase(dst, p []byte, canonical bool) []byte {
+	if !canonical {
+		return FoldToken(dst, p)
+	}
+	n := len(dst)
+	dst = append(dst, p...)
+	CanonicalizeHeaderKey(dst[n:])
+	return dst
+}
@@ -0,0 +1,78 @@
+package httphead
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteTokens(t *testing.T) {
+	for _, test := range []struct {
+		in    []string
+		flags TokenFlag
+		exp   string
+		ok    bool
+	}{
+		{[]string{"keep-alive", "Upgrade"}, 0, "keep-alive,Upgrade", true},
+		{[]string{"Accept", "accept", "Origin", "ACCEPT"}, TokenUnique, "Accept,Origin", true},
+		{[]string{"Accept-Encoding", "ORIGIN"}, TokenLowercase, "accept-encoding,origin", true},
+		{[]string{"accept-encoding", "x-REQUEST-id", "te"}, TokenCanonical, "Accept-Encoding,X-Request-Id,Te", true},
+		{[]string{"content-type", "Content-Type"}, TokenUnique | TokenCanonical, "Content-Type", true},
+		{[]string{"a", "b c"}, 0, "", false},
+		{[]string{""}, 0, "", false},
+		{nil, 0, "", true},
+	} {
+		var tokens [][]byte
+		for _, s := range test.in {
+			tokens = append(tokens, []byte(s))
+		}
+		var buf bytes.Buffer
+		_, err := WriteTokens(&buf, tokens, test.flags)
+		if act := buf.String(); act != test.exp || (err == nil) != test.ok {
+			t.Errorf("WriteTokens(%q, %s) = %q, %v; want %q", test.in, test.flags, act, err, test.exp)
+		}
+		act, ok := AppendTokens([]byte("x"), tokens, test.flags)
+		if string(act) != "x"+test.exp || ok != test.ok {
+			t.Errorf("AppendTokens(%q, %s) = %q, %v; want %q, %v", test.in, test.flags, act, ok, "x"+test.exp, test.ok)
+		}
+	}
+}
+
+func TestWriteTokensRoundTrip(t *testing.T) {
+	in := [][]byte{[]byte("Accept"), []byte("Origin"), []byte("accept")}
+	p, _ := AppendTokens(nil, in, TokenUnique|TokenLowercase)
+	var act []string
+	if !ScanTokens(p, func(v []byte) bool {
+		act = append(act, string(v))
+		return true
+	}) {
+		t.Fatalf("ScanTokens(%q) = false", p)
+	}
+	if len(act) != 2 || act[0] != "accept" || act[1] != "origin" {
+		t.Errorf("ScanTokens(AppendTokens()) = %q", act)
+	}
+}
+
+func TestTokenEqualFold(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		exp  bool
+	}{
+		{"gzip", "gzip", true},
+		{"Gzip", "gZIP", true},
+		{"Chunked", "chunked", true},
+		{"chunked", "chunke", false},
+		{"k", "K", false},
+		{"[", "{", false},
+	} {
+		if act := TokenEqualFold([]byte(test.a), []byte(test.b)); act != test.exp {
+			t.Errorf("TokenEqualFold(%q, %q) = %v; want %v", test.a, test.b, act, test.exp)
+		}
+	}
+}
+
+func TestFoldToken(t *testing.T) {
+	p := []byte("Content-Type_\xc0")
+	if act, exp := string(FoldToken(p[:0], p)), "content-type_\xc0"; act != exp {
+		t.Errorf("FoldToken() = %q; want %q", act, exp)
+	}
+}
@@ -0,0 +1,106 @@
+package httphead
+
+import "bytes"
+
+// ForbiddenTrailers contains names of header fields which must not be sent
+// in trailers, since they are needed for message framing, routing,
+// authentication, request modifiers, response control or content processing.
+// See https://tools.ietf.org/html/rfc9110#section-6.5.1
+var ForbiddenTrailers = []string{
+	"Authorization",
+	"Cache-Control",
+	"Connection",
+	"Content-Encoding",
+	"Content-Length",
+	"Content-Range",
+	"Content-Type",
+	"Expect",
+	"Host",
+	"Keep-Alive",
+	"Max-Forwards",
+	"Pragma",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Range",
+	"Set-Cookie",
+	"TE",
+	"Trailer",
+	"Transfer-Encoding",
+	"WWW-Authenticate",
+}
+
+// ParseTrailer parses Trailer header value and appends field names to given
+// slice in order of their appearance:
+//
+// Trailer = #field-name
+//
+// It returns false if data is malformed or contains some of
+// ForbiddenTrailers names. Note that appended names are subslices of data.
+// See https://tools.ietf.org/html/rfc9110#section-6.6.2
+func ParseTrailer(data []byte, names [][]byte) ([][]byte, bool) {
+	n := len(names)
+	valid := true
+	ok := ScanTokens(data, func(name []byte) bool {
+		if valid = !isForbiddenTrailer(name); !valid {
+			return false
+		}
+		names = append(names, name)
+		return true
+	})
+	if !ok || !valid {
+		return names[:n], false
+	}
+	return names, true
+}
+
+func isForbiddenTrailer(name []byte) bool {
+	for _, f := range ForbiddenTrailers {
+		if bytes.EqualFold(name, []byte(f)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expectation represents single expectation of Expect header value.
+type Expectation struct {
+	// Name is the expectation token, such as "100-continue".
+	Name []byte
+
+	// Value is the expectation value. It is nil if expectation has no
+	// value.
+	Value []byte
+
+	// Parameters contains expectation parameters.
+	Parameters Parameters
+}
+
+// IsContinue reports whether expectation is "100-continue", which is the
+// only one defined by RFC9110.
+func (e Expectation) IsContinue() bool {
+	return e.Value == nil && e.Parameters.Size() == 0 && bytes.EqualFold(e.Name, expectContinue)
+}
+
+var expectContinue = []byte("100-continue")
+
+// ParseExpect parses Expect header value and appends expectations to given
+// slice in order of their appearance:
+//
+// Expect      = #expectation
+// expectation = token [ "=" ( token / quoted-string ) parameters ]
+//
+// Note that server could respond with 417 (Expectation Failed) to the
+// request with expectations other than "100-continue". It returns false if
+// data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-10.1.1
+func ParseExpect(data []byte, exps []Expectation) ([]Expectation, bool) {
+	prefs, ok := parsePreferences(data, nil, true)
+	if !ok {
+		return exps, false
+	}
+	for _, p := range prefs {
+		exps = append(exps, Expectation(p))
+	}
+	return exps, true
+}
@@ -0,0 +1,72 @@
+package httphead
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseTrailer(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{`Expires`, []string{"Expires"}, true},
+		{`Server-Timing, X-Checksum,, Digest`, []string{"Server-Timing", "X-Checksum", "Digest"}, true},
+		{`X-Checksum, content-length`, nil, false},
+		{`Transfer-Encoding`, nil, false},
+		{`X-Checksum "x"`, nil, false},
+	} {
+		names, ok := ParseTrailer([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseTrailer(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if act := stringsOf(names); ok && !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseTrailer(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestParseExpect(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		exp  []string
+		cont []bool
+		ok   bool
+	}{
+		{`100-continue`, []string{"100-continue"}, []bool{true}, true},
+		{`100-Continue`, []string{"100-Continue"}, []bool{true}, true},
+		{`100-continue=1`, []string{"100-continue=1"}, []bool{false}, true},
+		{`foo=bar;a=1, 100-continue`, []string{"foo=bar;a=1", "100-continue"}, []bool{false, true}, true},
+		{`100-continue;a`, []string{"100-continue;a"}, []bool{false}, true},
+		{`foo=`, nil, nil, false},
+		{``, nil, nil, false},
+		{`;a`, nil, nil, false},
+		{`; 100-continue`, nil, nil, false},
+		{`,;x`, nil, nil, false},
+	} {
+		exps, ok := ParseExpect([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseExpect(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		var (
+			act  []string
+			cont []bool
+		)
+		for _, e := range exps {
+			var buf bytes.Buffer
+			WritePrefer(&buf, []Preference{Preference(e)})
+			act = append(act, buf.String())
+			cont = append(cont, e.IsContinue())
+		}
+		if !reflect.DeepEqual(act, test.exp) || !reflect.DeepEqual(cont, test.cont) {
+			t.Errorf("ParseExpect(%q) = %q %v; want %q %v", test.in, act, cont, test.exp, test.cont)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package httphead
+
+import "bytes"
+
+// TransferCoding represents single item of TE or Transfer-Encoding header
+// value.
+type TransferCoding struct {
+	// Coding is the transfer coding, such as "chunked" or "gzip". It could
+	// also be "trailers" for TE header.
+	Coding []byte
+
+	// Parameters contains transfer coding parameters except the weight.
+	Parameters Parameters
+
+	// Quality is the weight of coding in thousandths (see ParseQuality()).
+	// It is always 1000 for Transfer-Encoding header.
+	Quality uint16
+}
+
+// ParseTransferEncoding parses Transfer-Encoding header value and appends
+// codings to given slice in order of their appearance, that is, in order they
+// were applied:
+//
+// Transfer-Encoding  = #transfer-coding
+// transfer-coding    = token *( OWS ";" OWS transfer-parameter )
+// transfer-parameter = token BWS "=" BWS ( token / quoted-string )
+//
+// Unlike ScanOptions(), codings which are not separated by comma are treated
+// as malformed input. Note that appended codings consist of subslices of
+// data. It returns false if data is empty or malformed.
+// See https://tools.ietf.org/html/rfc9112#section-6.1
+func ParseTransferEncoding(data []byte, codings []TransferCoding) ([]TransferCoding, bool) {
+	n := len(codings)
+	codings, ok := parseTransferCodings(data, codings, false)
+	return codings, ok && len(codings) > n
+}
+
+// ParseTE parses TE header value and appends codings to given slice in order
+// of their appearance:
+//
+// TE        = #t-codings
+// t-codings = "trailers" / ( transfer-coding [ weight ] )
+//
+// Weight must be the last parameter of the coding. Empty value is valid and
+// means that only "chunked" coding is acceptable. Note that appended codings
+// consist of subslices of data. It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-10.1.4
+func ParseTE(data []byte, codings []TransferCoding) ([]TransferCoding, bool) {
+	if len(trim(data)) == 0 {
+		return codings, true
+	}
+	return parseTransferCodings(data, codings, true)
+}
+
+func parseTransferCodings(data []byte, codings []TransferCoding, weighted bool) ([]TransferCoding, bool) {
+	var (
+		index  = -1
+		valid  = true
+		weight bool
+	)
+	s := ListScanner{Flags: ScanRequireComma}
+	ok := s.ScanOptions(data, func(i int, name, attr, val []byte) Control {
+		if i != index {
+			index = i
+			weight = false
+			codings = append(codings, TransferCoding{
+				Coding:  name,
+				Quality: 1000,
+			})
+		}
+		if attr == nil {
+			return ControlContinue
+		}
+		if val == nil || weight {
+			valid = false
+			return ControlBreak
+		}
+		c := &codings[len(codings)-1]
+		if weighted && isQualityName(attr) {
+			var err error
+			if c.Quality, err = ParseQuality(val); err != nil {
+				valid = false
+				return ControlBreak
+			}
+			weight = true
+			return ControlContinue
+		}
+		c.Parameters.Set(attr, val)
+		return ControlContinue
+	})
+	return codings, ok && valid
+}
+
+// ChunkedLast reports whether the final transfer coding is "chunked". It also
+// reports whether "chunked" is applied at most once, since senders must not
+// apply it more than once.
+//
+// Note that request with Transfer-Encoding which final coding is not
+// "chunked" must be rejected, since its length could not be determined.
+// See https://tools.ietf.org/html/rfc9112#section-6.3
+func ChunkedLast(codings []TransferCoding) (last, ok bool) {
+	var n int
+	for _, c := range codings {
+		if bytes.EqualFold(c.Coding, codingChunked) {
+			n++
+		}
+	}
+	last = len(codings) > 0 && bytes.EqualFold(codings[len(codings)-1].Coding, codingChunked)
+	return last, n <= 1
+}
+
+var codingChunked = []byte("chunked")
@@ -0,0 +1,95 @@
+package httphead
+
+import "testing"
+
+func TestParseTransferEncoding(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		ok  bool
+	}{
+		{`chunked`, []string{"chunked"}, true},
+		{`gzip, Chunked`, []string{"gzip", "Chunked"}, true},
+		{`gzip,, ext;a=1;b="x y", chunked`, []string{"gzip", "ext;a=1;b=\"x y\"", "chunked"}, true},
+		{``, nil, false},
+		{` , `, nil, false},
+		{`gzip;q=0.5`, []string{"gzip;q=0.5"}, true},
+		{`gzip;a`, nil, false},
+		{`gzip chunked`, nil, false},
+	} {
+		codings, ok := ParseTransferEncoding([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseTransferEncoding(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if ok {
+			checkTransferCodings(t, test.in, codings, test.exp)
+		}
+	}
+}
+
+func TestParseTE(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		q   []uint16
+		ok  bool
+	}{
+		{``, nil, nil, true},
+		{`trailers, deflate;q=0.5`, []string{"trailers", "deflate"}, []uint16{1000, 500}, true},
+		{`ext;a=1;q=0, gzip;q=1`, []string{"ext;a=1", "gzip"}, []uint16{0, 1000}, true},
+		{`gzip;q=0.5;a=1`, nil, nil, false},
+		{`gzip;q=2`, nil, nil, false},
+		{`gzip;q=0.5;q=1`, nil, nil, false},
+		{`gzip deflate`, nil, nil, false},
+	} {
+		codings, ok := ParseTE([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseTE(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		checkTransferCodings(t, test.in, codings, test.exp)
+		for i, c := range codings {
+			if i < len(test.q) && c.Quality != test.q[i] {
+				t.Errorf("ParseTE(%q)[%d] quality is %d; want %d", test.in, i, c.Quality, test.q[i])
+			}
+		}
+	}
+}
+
+func TestChunkedLast(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		last bool
+		ok   bool
+	}{
+		{`chunked`, true, true},
+		{`gzip, CHUNKED`, true, true},
+		{`chunked, gzip`, false, true},
+		{`gzip`, false, true},
+		{`chunked, chunked`, true, false},
+	} {
+		codings, _ := ParseTransferEncoding([]byte(test.in), nil)
+		last, ok := ChunkedLast(codings)
+		if last != test.last || ok != test.ok {
+			t.Errorf("ChunkedLast(%q) = %v, %v; want %v, %v", test.in, last, ok, test.last, test.ok)
+		}
+	}
+}
+
+func checkTransferCodings(t *testing.T, in string, codings []TransferCoding, exp []string) {
+	t.Helper()
+	if len(codings) != len(exp) {
+		t.Errorf("parsed %d codings from %q; want %d", len(codings), in, len(exp))
+		return
+	}
+	for i, c := range codings {
+		opt := Option{Name: c.Coding, Parameters: c.Parameters}
+		if act := WriteOptionsString([]Option{opt}); act != exp[i] {
+			t.Errorf("coding #%d of %q is %s; want %s", i, in, act, exp[i])
+		}
+	}
+}
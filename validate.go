@@ -0,0 +1,288 @@
+package httphead
+
+import "github.com/gobwas/httphead/sfv"
+
+// Grammar describes the header value grammar used by Validate().
+type Grammar byte
+
+const (
+	// GrammarTokens describes comma separated list of tokens, the same as
+	// ScanTokens() expects:
+	//
+	// list = 1#token
+	GrammarTokens Grammar = iota
+
+	// GrammarOptions describes comma separated list of options, the same as
+	// ScanOptions() expects:
+	//
+	// values = 1#value
+	// value = token *( ";" param )
+	// param = token [ "=" (token | quoted-string) ]
+	GrammarOptions
+
+	// GrammarCookie describes RFC6265 cookie-string:
+	//
+	// cookie-string = cookie-pair *( ";" SP cookie-pair )
+	// cookie-pair   = cookie-name "=" cookie-value
+	//
+	// See https://tools.ietf.org/html/rfc6265#section-4.2.1
+	GrammarCookie
+
+	// GrammarRaw describes opaque field value, such as JSON value of
+	// Report-To header. Only RFC7230 field-value rules are checked, that is,
+	// absence of control characters other than HT.
+	// See https://tools.ietf.org/html/rfc7230#section-3.2
+	GrammarRaw
+
+	// GrammarStructuredItem describes RFC8941 structured field item, the same
+	// as sfv.ParseItem() expects.
+	// See https://tools.ietf.org/html/rfc8941#section-3.3
+	GrammarStructuredItem
+
+	// GrammarStructuredList describes RFC8941 structured field list, the same
+	// as sfv.ParseList() expects.
+	// See https://tools.ietf.org/html/rfc8941#section-3.1
+	GrammarStructuredList
+
+	// GrammarStructuredDictionary describes RFC8941 structured field
+	// dictionary, the same as sfv.ParseDictionary() expects.
+	// See https://tools.ietf.org/html/rfc8941#section-3.2
+	GrammarStructuredDictionary
+)
+
+// String represents grammar as a string.
+func (g Grammar) String() string {
+	switch g {
+	case GrammarTokens:
+		return "tokens"
+	case GrammarOptions:
+		return "options"
+	case GrammarCookie:
+		return "cookie"
+	case GrammarRaw:
+		return "raw"
+	case GrammarStructuredItem:
+		return "sf-item"
+	case GrammarStructuredList:
+		return "sf-list"
+	case GrammarStructuredDictionary:
+		return "sf-dictionary"
+	default:
+		return "unknown"
+	}
+}
+
+// Validate checks data against given grammar and returns all found errors in
+// order of their offsets. Unlike scanning functions, it does not stop on the
+// first error but tries to recover on the next list element.
+//
+// It returns nil if data is wellformed.
+//
+// Validate panics if grammar is unknown.
+func Validate(data []byte, grammar Grammar) (errs []SyntaxError) {
+	if exceedsLimit(data) {
+		return []SyntaxError{{
+			Offset: MaxValueLength,
+			Err:    ErrLimitExceeded,
+		}}
+	}
+	switch grammar {
+	case GrammarTokens, GrammarOptions:
+		var n int
+		eachElement(data, ',', func(offset int, elem []byte) {
+			if len(trim(elem)) == 0 {
+				return
+			}
+			if err := validateOption(elem, grammar == GrammarTokens); err != nil {
+				err.Offset += offset
+				errs = append(errs, *err)
+			}
+			n++
+		})
+		if n == 0 && len(errs) == 0 {
+			errs = append(errs, SyntaxError{
+				Offset:   len(data),
+				Err:      ErrMalformed,
+				Expected: "token",
+			})
+		}
+
+	case GrammarCookie:
+		eachElement(data, ';', func(offset int, pair []byte) {
+			if offset > 0 {
+				if len(pair) == 0 || pair[0] != ' ' {
+					var b byte
+					if len(pair) > 0 {
+						b = pair[0]
+					}
+					errs = append(errs, SyntaxError{
+						Offset:   offset,
+						Byte:     b,
+						Err:      ErrMalformed,
+						Expected: "' ' after ';'",
+					})
+					return
+				}
+				pair = pair[1:]
+				offset++
+			}
+			if err := validateCookie(pair); err != nil {
+				err.Offset += offset
+				errs = append(errs, *err)
+			}
+		})
+
+	case GrammarRaw:
+		if i := indexControl(data); i != -1 {
+			errs = append(errs, SyntaxError{
+				Offset: i,
+				Byte:   data[i],
+				Err:    ErrHeaderInjection,
+			})
+		}
+
+	case GrammarStructuredItem, GrammarStructuredList, GrammarStructuredDictionary:
+		if err := validateStructured(data, grammar); err != nil {
+			errs = append(errs, *err)
+		}
+
+	default:
+		panic("httphead: unknown grammar")
+	}
+
+	return errs
+}
+
+// eachElement calls it for each part of data separated by delim. Delimiters
+// inside quoted-strings are ignored.
+func eachElement(data []byte, delim byte, it func(offset int, elem []byte)) {
+	var (
+		pos   int
+		start int
+	)
+	for pos < len(data) {
+		switch data[pos] {
+		case '"':
+			n := ScanUntil(data[pos+1:], '"')
+			if n == -1 {
+				pos = len(data)
+			} else {
+				pos += n + 2
+			}
+		case delim:
+			it(start, data[start:pos])
+			pos++
+			start = pos
+		default:
+			pos++
+		}
+	}
+	it(start, data[start:])
+}
+
+// validateOption checks that non-empty elem is a single option (or a single
+// token if token is true). It returns error with offset relative to elem, if
+// any.
+func validateOption(elem []byte, token bool) *SyntaxError {
+	flags := ScanRejectControl | ScanNoComments | ScanRequireComma | scanRejectTruncated
+	if token {
+		flags |= scanTokensOnly
+	}
+	s := ListScanner{Flags: flags}
+	err := s.scanOptions(elem, func(int, []byte, []byte, []byte, int, int) Control {
+		return ControlContinue
+	})
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*SyntaxError); ok {
+		return e
+	}
+	return &SyntaxError{Err: err}
+}
+
+// validateStructured checks data against given structured field grammar.
+func validateStructured(data []byte, grammar Grammar) *SyntaxError {
+	var (
+		offset int
+		ok     bool
+	)
+	switch grammar {
+	case GrammarStructuredItem:
+		offset, ok = sfv.CheckItem(data)
+	case GrammarStructuredList:
+		offset, ok = sfv.CheckList(data)
+	case GrammarStructuredDictionary:
+		offset, ok = sfv.CheckDictionary(data)
+	}
+	if ok {
+		return nil
+	}
+	err := &SyntaxError{
+		Offset:   offset,
+		Err:      ErrMalformed,
+		Expected: grammar.String(),
+	}
+	if offset < len(data) {
+		err.Byte = data[offset]
+	} else {
+		err.Err = ErrTruncated
+	}
+	return err
+}
+
+// validateCookie checks that pair is a valid RFC6265 cookie-pair. It returns
+// error with offset relative to pair, if any.
+func validateCookie(pair []byte) *SyntaxError {
+	if i := indexControl(pair); i != -1 {
+		return &SyntaxError{
+			Offset: i,
+			Byte:   pair[i],
+			Err:    ErrHeaderInjection,
+		}
+	}
+	eq := -1
+	for i, c := range pair {
+		if c == '=' {
+			eq = i
+			break
+		}
+		if !OctetTypes[c].IsToken() {
+			return &SyntaxError{
+				Offset:   i,
+				Byte:     c,
+				Err:      ErrMalformed,
+				Expected: "token or '='",
+			}
+		}
+	}
+	if eq <= 0 {
+		expected := "token"
+		if len(pair) > 0 {
+			expected = "'='"
+		}
+		return &SyntaxError{
+			Offset:   len(pair),
+			Err:      ErrMalformed,
+			Expected: expected,
+		}
+	}
+
+	value := pair[eq+1:]
+	offset := eq + 1
+	if v := stripQuotes(value); len(v) != len(value) {
+		value = v
+		offset++
+	}
+	for i := range value {
+		if !ValidCookieValue(value[i:i+1], true) {
+			return &SyntaxError{
+				Offset:   offset + i,
+				Byte:     value[i],
+				Err:      ErrMalformed,
+				Expected: "cookie-octet",
+			}
+		}
+	}
+	return nil
+}
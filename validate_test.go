@@ -0,0 +1,187 @@
+package httphead
+
+import (
+	"fmt"
+	"testing"
+)
+
+var validateCases = []struct {
+	label   string
+	in      []byte
+	grammar Grammar
+	exp     []SyntaxError
+}{
+	{
+		label:   "tokens",
+		in:      []byte(`a, b,,c`),
+		grammar: GrammarTokens,
+	},
+	{
+		label:   "tokens",
+		in:      []byte(`a b, c;d, "e"`),
+		grammar: GrammarTokens,
+		exp: []SyntaxError{
+			{Offset: 2, Byte: 'b', Err: ErrMalformed, Expected: "','"},
+			{Offset: 6, Byte: ';', Err: ErrMalformed, Expected: "','"},
+			{Offset: 10, Byte: '"', Err: ErrMalformed, Expected: "token"},
+		},
+	},
+	{
+		label:   "tokens",
+		in:      []byte(` , `),
+		grammar: GrammarTokens,
+		exp: []SyntaxError{
+			{Offset: 3, Err: ErrMalformed, Expected: "token"},
+		},
+	},
+	{
+		label:   "options",
+		in:      []byte(`foo;a=1;b="x,y", bar`),
+		grammar: GrammarOptions,
+	},
+	{
+		label:   "options",
+		in:      []byte(`foo;a==1, bar;"b", baz;c=, qux;d="x`),
+		grammar: GrammarOptions,
+		exp: []SyntaxError{
+			{Offset: 6, Byte: '=', Err: ErrMalformed, Expected: `token or '"' after '='`},
+			{Offset: 14, Byte: '"', Err: ErrMalformed, Expected: "token after ';'"},
+			{Offset: 25, Err: ErrTruncated, Expected: `token or '"' after '='`},
+			{Offset: 33, Byte: '"', Err: ErrTruncated, Expected: `closing '"'`},
+		},
+	},
+	{
+		label:   "options",
+		in:      []byte("foo;a=\"x\r\ny\", bar(baz)"),
+		grammar: GrammarOptions,
+		exp: []SyntaxError{
+			{Offset: 8, Byte: '\r', Err: ErrHeaderInjection},
+			{Offset: 17, Byte: '(', Err: ErrMalformed, Expected: "';' or ','"},
+		},
+	},
+	{
+		label:   "cookie",
+		in:      []byte(`foo=bar; baz="qux"`),
+		grammar: GrammarCookie,
+	},
+	{
+		label:   "cookie",
+		in:      []byte(`foo=b ar;baz=qux; f@o=1; bar; x="y`),
+		grammar: GrammarCookie,
+		exp: []SyntaxError{
+			{Offset: 5, Byte: ' ', Err: ErrMalformed, Expected: "cookie-octet"},
+			{Offset: 9, Byte: 'b', Err: ErrMalformed, Expected: "' ' after ';'"},
+			{Offset: 19, Byte: '@', Err: ErrMalformed, Expected: "token or '='"},
+			{Offset: 28, Err: ErrMalformed, Expected: "'='"},
+			{Offset: 32, Byte: '"', Err: ErrMalformed, Expected: "cookie-octet"},
+		},
+	},
+	{
+		label:   "raw",
+		in:      []byte(`{"group":"endpoint-1","max_age":10886400}`),
+		grammar: GrammarRaw,
+	},
+	{
+		label:   "raw",
+		in:      []byte("{\"a\":\"b\r\n\"}"),
+		grammar: GrammarRaw,
+		exp: []SyntaxError{
+			{Offset: 7, Byte: '\r', Err: ErrHeaderInjection},
+		},
+	},
+	{
+		label:   "sf-item",
+		in:      []byte(`"foo";a=?1`),
+		grammar: GrammarStructuredItem,
+	},
+	{
+		label:   "sf-item",
+		in:      []byte(`1, 2`),
+		grammar: GrammarStructuredItem,
+		exp: []SyntaxError{
+			{Offset: 1, Byte: ',', Err: ErrMalformed, Expected: "sf-item"},
+		},
+	},
+	{
+		label:   "sf-list",
+		in:      []byte(`sugar, (tea rum);x=1`),
+		grammar: GrammarStructuredList,
+	},
+	{
+		label:   "sf-list",
+		in:      []byte(`a, b c`),
+		grammar: GrammarStructuredList,
+		exp: []SyntaxError{
+			{Offset: 5, Byte: 'c', Err: ErrMalformed, Expected: "sf-list"},
+		},
+	},
+	{
+		label:   "sf-list",
+		in:      []byte(`a,`),
+		grammar: GrammarStructuredList,
+		exp: []SyntaxError{
+			{Offset: 2, Err: ErrTruncated, Expected: "sf-list"},
+		},
+	},
+	{
+		label:   "sf-dictionary",
+		in:      []byte(`a=1, b;x, c=(1 2)`),
+		grammar: GrammarStructuredDictionary,
+	},
+	{
+		label:   "sf-dictionary",
+		in:      []byte(`a=1, B=2`),
+		grammar: GrammarStructuredDictionary,
+		exp: []SyntaxError{
+			{Offset: 5, Byte: 'B', Err: ErrMalformed, Expected: "sf-dictionary"},
+		},
+	},
+}
+
+func TestValidateScanOptions(t *testing.T) {
+	// Validate must agree with strict options scanning on wellformed input.
+	for _, in := range []string{
+		`foo`,
+		`foo;a=1, bar;b="x"`,
+		`foo;a=`,
+		`foo;`,
+		`foo bar`,
+		`foo;a=1 b`,
+		`foo;a b`,
+		`foo;a==1`,
+		`foo;"a"`,
+	} {
+		flags := ScanRejectControl | ScanNoComments | ScanRequireComma | scanRejectTruncated
+		err := ListScanner{Flags: flags}.ScanOptionsErr([]byte(in), func(int, []byte, []byte, []byte) Control {
+			return ControlContinue
+		})
+		errs := Validate([]byte(in), GrammarOptions)
+		if (err == nil) != (errs == nil) {
+			t.Errorf("Validate(%q) = %v; ScanOptionsErr() = %v", in, errs, err)
+		}
+		if err != nil && errs != nil && err.Error() != errs[0].Error() {
+			t.Errorf("Validate(%q) = %v; ScanOptionsErr() = %v", in, errs, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range validateCases {
+		t.Run(test.label, func(t *testing.T) {
+			act := Validate(test.in, test.grammar)
+			if fmt.Sprint(act) != fmt.Sprint(test.exp) {
+				t.Errorf("Validate(%q, %s) = %v; want %v", test.in, test.grammar, act, test.exp)
+			}
+		})
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	for _, bench := range validateCases {
+		b.Run(bench.label, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = Validate(bench.in, bench.grammar)
+			}
+		})
+	}
+}
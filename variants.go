@@ -0,0 +1,125 @@
+package httphead
+
+import "io"
+
+// Variant represents single item of Variants header value.
+type Variant struct {
+	// Field is the name of the header field which is used in content
+	// negotiation, such as Accept-Language.
+	Field []byte
+
+	// Values contains values of the field which are available.
+	Values [][]byte
+}
+
+// VariantKey represents single item of Variant-Key header value. It contains
+// one available value for each item of Variants header in the same order.
+type VariantKey [][]byte
+
+// ParseVariants parses Variants header value and appends its items to given
+// slice:
+//
+// Variants        = 1#variant-item
+// variant-item    = field-name *( OWS ";" OWS available-value )
+// available-value = token
+//
+// Note that appended items consist of subslices of data. It returns false if
+// data is malformed.
+// See https://tools.ietf.org/html/draft-ietf-httpbis-variants-04#section-2
+func ParseVariants(data []byte, variants []Variant) ([]Variant, bool) {
+	index := -1
+	valid := true
+	ok := ScanOptions(data, func(i int, name, attr, val []byte) Control {
+		if val != nil {
+			valid = false
+			return ControlBreak
+		}
+		if i != index {
+			index = i
+			variants = append(variants, Variant{Field: name})
+		}
+		if attr != nil {
+			v := &variants[len(variants)-1]
+			v.Values = append(v.Values, attr)
+		}
+		return ControlContinue
+	})
+	return variants, ok && valid
+}
+
+// ParseVariantKey parses Variant-Key header value and appends its items to
+// given slice:
+//
+// Variant-Key      = 1#available-values
+// available-values = available-value *( ";" available-value )
+//
+// Note that appended items consist of subslices of data. It returns false if
+// data is malformed.
+// See https://tools.ietf.org/html/draft-ietf-httpbis-variants-04#section-3
+func ParseVariantKey(data []byte, keys []VariantKey) ([]VariantKey, bool) {
+	index := -1
+	valid := true
+	ok := ScanOptions(data, func(i int, name, attr, val []byte) Control {
+		if val != nil {
+			valid = false
+			return ControlBreak
+		}
+		if i != index {
+			index = i
+			keys = append(keys, VariantKey{name})
+		}
+		if attr != nil {
+			k := &keys[len(keys)-1]
+			*k = append(*k, attr)
+		}
+		return ControlContinue
+	})
+	return keys, ok && valid
+}
+
+// WriteVariants writes Variants header value to the dest. It returns
+// ErrMalformed without writing anything if some of field names or values is
+// not a valid token.
+func WriteVariants(dest io.Writer, variants []Variant) (n int, err error) {
+	for _, v := range variants {
+		if !isToken(v.Field) || !validTokens(v.Values) {
+			return 0, ErrMalformed
+		}
+	}
+	w := writer{w: dest}
+	for i, v := range variants {
+		if i > 0 {
+			w.write(comma)
+		}
+		w.write(v.Field)
+		for _, value := range v.Values {
+			w.write(semicolon)
+			w.write(value)
+		}
+	}
+	return w.result()
+}
+
+// WriteVariantKey writes Variant-Key header value to the dest. It returns
+// ErrMalformed without writing anything if some of values is not a valid
+// token.
+func WriteVariantKey(dest io.Writer, keys []VariantKey) (n int, err error) {
+	for _, k := range keys {
+		if len(k) == 0 || !validTokens(k) {
+			return 0, ErrMalformed
+		}
+	}
+	w := writer{w: dest}
+	for i, k := range keys {
+		if i > 0 {
+			w.write(comma)
+		}
+		for j, value := range k {
+			if j > 0 {
+				w.write(semicolon)
+			}
+			w.write(value)
+		}
+	}
+	return w.result()
+}
@@ -0,0 +1,108 @@
+package httphead
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseVariants(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{
+			in:  `Accept-Encoding;gzip;br, Accept-Language;en ;fr`,
+			exp: `Accept-Encoding;gzip;br,Accept-Language;en;fr`,
+			ok:  true,
+		},
+		{
+			in:  `Accept-Language`,
+			exp: `Accept-Language`,
+			ok:  true,
+		},
+		{
+			in: `Accept-Language;en=1`,
+			ok: false,
+		},
+	} {
+		variants, ok := ParseVariants([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseVariants(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		var buf bytes.Buffer
+		if _, err := WriteVariants(&buf, variants); err != nil {
+			t.Fatal(err)
+		}
+		if act := buf.String(); act != test.exp {
+			t.Errorf("WriteVariants(ParseVariants(%q)) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestParseVariantKey(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp string
+		ok  bool
+	}{
+		{
+			in:  `gzip;fr, identity;fr`,
+			exp: `gzip;fr,identity;fr`,
+			ok:  true,
+		},
+		{
+			in:  `en`,
+			exp: `en`,
+			ok:  true,
+		},
+		{
+			in: `gzip;fr=1`,
+			ok: false,
+		},
+	} {
+		keys, ok := ParseVariantKey([]byte(test.in), nil)
+		if ok != test.ok {
+			t.Errorf("ParseVariantKey(%q) wellformed sign is %v; want %v", test.in, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if len(keys[0]) == 0 {
+			t.Errorf("ParseVariantKey(%q) returned empty key", test.in)
+		}
+		var buf bytes.Buffer
+		if _, err := WriteVariantKey(&buf, keys); err != nil {
+			t.Fatal(err)
+		}
+		if act := buf.String(); act != test.exp {
+			t.Errorf("WriteVariantKey(ParseVariantKey(%q)) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestWriteVariantsMalformed(t *testing.T) {
+	for _, v := range []string{"Accept\r\nX-Injected: 1", "a,b", "fr;x", "a b", ""} {
+		var buf bytes.Buffer
+		_, err := WriteVariants(&buf, []Variant{{
+			Field:  []byte("Accept-Language"),
+			Values: [][]byte{[]byte("en"), []byte(v)},
+		}})
+		if err != ErrMalformed || buf.Len() != 0 {
+			t.Errorf("WriteVariants(%q) = %q, %v; want nothing, %v", v, buf.String(), err, ErrMalformed)
+		}
+		_, err = WriteVariants(&buf, []Variant{{Field: []byte(v)}})
+		if err != ErrMalformed || buf.Len() != 0 {
+			t.Errorf("WriteVariants(%q) = %q, %v; want nothing, %v", v, buf.String(), err, ErrMalformed)
+		}
+		_, err = WriteVariantKey(&buf, []VariantKey{{[]byte("gzip"), []byte(v)}})
+		if err != ErrMalformed || buf.Len() != 0 {
+			t.Errorf("WriteVariantKey(%q) = %q, %v; want nothing, %v", v, buf.String(), err, ErrMalformed)
+		}
+	}
+}
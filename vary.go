@@ -0,0 +1,59 @@
+package httphead
+
+import "bytes"
+
+// ParseVary parses Vary header value and appends field names to given slice
+// in order of their first appearance:
+//
+// Vary = #( "*" / field-name )
+//
+// Field names are compared case-insensitively, such that duplicates are not
+// appended. If "*" is present, wildcard is true and no names are appended,
+// since response varies on everything. Note that appended names are subslices
+// of data.
+//
+// It returns false if data is malformed.
+// See https://tools.ietf.org/html/rfc9110#section-12.5.5
+func ParseVary(data []byte, names [][]byte) (_ [][]byte, wildcard, ok bool) {
+	n := len(names)
+	ok = ScanTokens(data, func(name []byte) bool {
+		if isWildcard(name) {
+			wildcard = true
+			return true
+		}
+		for _, prev := range names[n:] {
+			if bytes.EqualFold(prev, name) {
+				return true
+			}
+		}
+		names = append(names, name)
+		return true
+	})
+	if !ok || wildcard {
+		return names[:n], wildcard && ok, ok
+	}
+	return names, false, true
+}
+
+// VaryContains reports whether Vary header value data lists given field name
+// or "*". Field names are compared case-insensitively. It returns false if
+// data is malformed.
+func VaryContains(data []byte, field string) bool {
+	return containsToken(data, func(name []byte) bool {
+		return isWildcard(name) || equalFoldString(name, field)
+	})
+}
+
+// equalFoldString is like bytes.EqualFold() for ASCII, but compares p with
+// string without converting it.
+func equalFoldString(p []byte, s string) bool {
+	if len(p) != len(s) {
+		return false
+	}
+	for i := 0; i < len(p); i++ {
+		if lowercase[p[i]] != lowercase[s[i]] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package httphead
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVary(t *testing.T) {
+	for _, test := range []struct {
+		in  string
+		exp []string
+		any bool
+		ok  bool
+	}{
+		{`Accept-Encoding`, []string{"Accept-Encoding"}, false, true},
+		{`accept-encoding, Origin,, Accept-Encoding, origin`, []string{"accept-encoding", "Origin"}, false, true},
+		{`*`, nil, true, true},
+		{`Origin, *`, nil, true, true},
+		{`Origin "x"`, nil, false, false},
+		{``, nil, false, false},
+	} {
+		names, wildcard, ok := ParseVary([]byte(test.in), nil)
+		if ok != test.ok || wildcard != test.any {
+			t.Errorf("ParseVary(%q) = %v, %v; want %v, %v", test.in, wildcard, ok, test.any, test.ok)
+			continue
+		}
+		if act := stringsOf(names); !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("ParseVary(%q) = %q; want %q", test.in, act, test.exp)
+		}
+	}
+}
+
+func TestVaryContains(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		field string
+		exp   bool
+	}{
+		{`Accept-Encoding, Origin`, "origin", true},
+		{`Accept-Encoding, Origin`, "Accept", false},
+		{`*`, "Accept", true},
+		{`Origin "x"`, "Origin", true},
+		{`"x", Origin`, "Origin", false},
+	} {
+		if act := VaryContains([]byte(test.in), test.field); act != test.exp {
+			t.Errorf("VaryContains(%q, %q) = %v; want %v", test.in, test.field, act, test.exp)
+		}
+	}
+}
+
+func TestVaryContainsAllocs(t *testing.T) {
+	data := []byte(`Accept-Encoding, Accept-Language, Origin`)
+	allocs := testing.AllocsPerRun(100, func() {
+		VaryContains(data, "origin")
+	})
+	if allocs != 0 {
+		t.Errorf("VaryContains() allocates %v times; want 0", allocs)
+	}
+}
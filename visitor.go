@@ -0,0 +1,136 @@
+package httphead
+
+// OptionEvent describes the kind of event emitted by
+// ListScanner.ScanOptionEvents().
+type OptionEvent byte
+
+const (
+	// OptionStart is emitted when option name is scanned. Attribute and value
+	// are always nil for this event.
+	OptionStart OptionEvent = iota
+
+	// OptionParam is emitted for each parameter of the option.
+	OptionParam
+
+	// OptionEnd is emitted when all parameters of the option are scanned.
+	// It is not emitted for options skipped by ControlSkip returned from
+	// OptionStart callback, as also for the last option if scanning was
+	// interrupted by ControlBreak or malformed input.
+	OptionEnd
+)
+
+// String represents event as a string.
+func (e OptionEvent) String() string {
+	switch e {
+	case OptionStart:
+		return "start"
+	case OptionParam:
+		return "param"
+	case OptionEnd:
+		return "end"
+	default:
+		return "unknown"
+	}
+}
+
+// ScanOptionEvents scans options from data using DefaultListScanner.
+// See ListScanner.ScanOptionEvents() for details.
+func ScanOptionEvents(data []byte, it func(e OptionEvent, index int, option, attribute, value []byte) Control) bool {
+	return DefaultListScanner.ScanOptionEvents(data, it)
+}
+
+// ScanOptionEvents parses data in the same form as ScanOptions() does, but
+// calls given callback with explicit option boundary events. That is, for
+// each option callback is called with OptionStart event, then with
+// OptionParam event for each option parameter and then with OptionEnd event.
+//
+// Control value returned from OptionStart and OptionParam callbacks has the
+// same meaning as for ScanOptions(). Returning ControlSkip for OptionStart
+// event skips the whole option including its OptionEnd event. Only
+// ControlBreak makes sense for OptionEnd event.
+//
+// It returns false if data is malformed.
+func (s ListScanner) ScanOptionEvents(data []byte, it func(e OptionEvent, index int, option, attribute, value []byte) Control) bool {
+	var (
+		index   = -1
+		name    []byte
+		started bool
+		stopped bool
+	)
+	ok := s.ScanOptions(data, func(i int, option, attribute, value []byte) Control {
+		if stopped {
+			return ControlBreak
+		}
+		if i != index {
+			if started {
+				started = false
+				if it(OptionEnd, index, name, nil, nil) == ControlBreak {
+					stopped = true
+					return ControlBreak
+				}
+			}
+			index = i
+			name = option
+			switch it(OptionStart, i, option, nil, nil) {
+			case ControlBreak:
+				stopped = true
+				return ControlBreak
+			case ControlSkip:
+				return ControlSkip
+			}
+			started = true
+		}
+		if attribute == nil {
+			return ControlContinue
+		}
+		c := it(OptionParam, i, option, attribute, value)
+		if c == ControlBreak {
+			stopped = true
+		}
+		return c
+	})
+	if ok && started && !stopped {
+		it(OptionEnd, index, name, nil, nil)
+	}
+	return ok
+}
+
+// OptionVisitor is the interface of options visitor used by VisitOptions().
+type OptionVisitor interface {
+	// OnOption is called when option with given index and name is scanned.
+	// Returning ControlSkip skips all parameters of the option, as also the
+	// OnEnd() call for it.
+	OnOption(index int, name []byte) Control
+
+	// OnParam is called for each parameter of the current option.
+	OnParam(key, value []byte) Control
+
+	// OnEnd is called when all parameters of the option with given index are
+	// scanned.
+	OnEnd(index int)
+}
+
+// VisitOptions scans options from data using DefaultListScanner.
+// See ListScanner.VisitOptions() for details.
+func VisitOptions(data []byte, v OptionVisitor) bool {
+	return DefaultListScanner.VisitOptions(data, v)
+}
+
+// VisitOptions parses data in the same form as ScanOptions() does and calls
+// appropriate methods of given visitor. Control values returned from visitor
+// methods have the same meaning as for ScanOptionEvents().
+//
+// It returns false if data is malformed.
+func (s ListScanner) VisitOptions(data []byte, v OptionVisitor) bool {
+	return s.ScanOptionEvents(data, func(e OptionEvent, index int, option, attribute, value []byte) Control {
+		switch e {
+		case OptionStart:
+			return v.OnOption(index, option)
+		case OptionParam:
+			return v.OnParam(attribute, value)
+		default:
+			v.OnEnd(index)
+			return ControlContinue
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package httphead
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestScanOptionEvents(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		skip string
+		stop string
+		exp  string
+		ok   bool
+	}{
+		{
+			in:  `foo;a=1;b,bar,baz;c="x"`,
+			exp: `start#0(foo) param#0(foo;a=1) param#0(foo;b=) end#0(foo) start#1(bar) end#1(bar) start#2(baz) param#2(baz;c=x) end#2(baz)`,
+			ok:  true,
+		},
+		{
+			in:   `foo;a=1;b,bar,baz;c="x"`,
+			skip: "foo",
+			exp:  `start#0(foo) start#1(bar) end#1(bar) start#2(baz) param#2(baz;c=x) end#2(baz)`,
+			ok:   true,
+		},
+		{
+			in:   `foo;a=1;b,bar,baz;c="x"`,
+			stop: "bar",
+			exp:  `start#0(foo) param#0(foo;a=1) param#0(foo;b=) end#0(foo) start#1(bar)`,
+			ok:   true,
+		},
+		{
+			in:  `foo;a=1,bar;=`,
+			exp: `start#0(foo) param#0(foo;a=1)`,
+			ok:  false,
+		},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			var act []string
+			ok := ScanOptionEvents([]byte(test.in), func(e OptionEvent, i int, option, attribute, value []byte) Control {
+				s := fmt.Sprintf("%s#%d(%s", e, i, option)
+				if e == OptionParam {
+					s += fmt.Sprintf(";%s=%s", attribute, value)
+				}
+				act = append(act, s+")")
+				switch {
+				case e == OptionStart && string(option) == test.skip:
+					return ControlSkip
+				case e == OptionStart && string(option) == test.stop:
+					return ControlBreak
+				}
+				return ControlContinue
+			})
+			if ok != test.ok {
+				t.Errorf("unexpected result: %v; want %v", ok, test.ok)
+			}
+			if s := strings.Join(act, " "); s != test.exp {
+				t.Errorf("unexpected events:\n\tact: %s\n\texp: %s", s, test.exp)
+			}
+		})
+	}
+}
+
+type optionsCollector struct {
+	options []Option
+	skip    string
+}
+
+func (c *optionsCollector) OnOption(_ int, name []byte) Control {
+	if string(name) == c.skip {
+		return ControlSkip
+	}
+	c.options = append(c.options, Option{Name: name})
+	return ControlContinue
+}
+
+func (c *optionsCollector) OnParam(key, value []byte) Control {
+	c.options[len(c.options)-1].Parameters.Set(key, value)
+	return ControlContinue
+}
+
+func (c *optionsCollector) OnEnd(int) {}
+
+func TestVisitOptions(t *testing.T) {
+	c := optionsCollector{skip: "bar"}
+	ok := VisitOptions([]byte(`foo;a=1;b=2,bar;c=3,baz`), &c)
+	if !ok {
+		t.Fatalf("VisitOptions() = false; want true")
+	}
+	exp := []Option{
+		NewOption("foo", map[string]string{"a": "1", "b": "2"}),
+		NewOption("baz", nil),
+	}
+	if !optionsEqual(c.options, exp) {
+		t.Errorf("unexpected options: %v; want %v", c.options, exp)
+	}
+}
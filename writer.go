@@ -1,6 +1,10 @@
 package httphead
 
-import "io"
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
 
 var (
 	comma     = []byte{','}
@@ -8,6 +12,8 @@ var (
 	semicolon = []byte{';'}
 	quote     = []byte{'"'}
 	escape    = []byte{'\\'}
+	space     = []byte{' '}
+	asterisk  = []byte{'*'}
 )
 
 // WriteOptions write options list to the dest.
@@ -17,26 +23,243 @@ var (
 // param = token [ "=" (token | quoted-string) ]
 //
 // It wraps valuse into the quoted-string sequence if it contains any
-// non-token characters.
+// non-token characters. Parameters are written in order they were set.
+//
+// Parameters with nil value are written without value, such as "key", while
+// parameters with empty but non-nil value are written as `key=""`. Parsed
+// options keep that distinction, thus they are written the same way they were
+// parsed.
 func WriteOptions(dest io.Writer, options []Option) (n int, err error) {
+	return OptionWriter{}.WriteOptions(dest, options)
+}
+
+// AppendOptions appends options list written in the same form as
+// WriteOptions() does to dst and returns the extended slice. Unlike
+// WriteOptions(), it does not make any calls through io.Writer interface.
+func AppendOptions(dst []byte, options []Option) []byte {
+	return OptionWriter{}.AppendOptions(dst, options)
+}
+
+// QuoteMode describes how parameter values are written.
+type QuoteMode byte
+
+const (
+	// QuoteMinimal causes writer to write value as token if possible, and as
+	// quoted-string otherwise. Control characters and '"' are escaped with
+	// backslash. This is the default mode.
+	QuoteMinimal QuoteMode = iota
+
+	// QuoteAlways causes writer to write every value as quoted-string, even
+	// if it is a valid token. Control characters, '"' and '\\' are escaped
+	// with backslash.
+	QuoteAlways
+
+	// QuoteStrict causes writer to write value as token if possible, and as
+	// quoted-string otherwise. Only '"' and '\\' are escaped, as RFC7230
+	// requires. Control characters other than HTAB, which could not be
+	// represented in quoted-string, are omitted to prevent header injection.
+	QuoteStrict
+)
+
+// String represents mode as a string.
+func (m QuoteMode) String() string {
+	switch m {
+	case QuoteMinimal:
+		return "minimal"
+	case QuoteAlways:
+		return "always"
+	case QuoteStrict:
+		return "strict"
+	default:
+		return "unknown"
+	}
+}
+
+// OptionWriter contains options for writing options lists. Zero value writes
+// options the same way WriteOptions() does.
+//
+// OptionWriter also could write options incrementally, without building
+// []Option first. Such writing starts with Reset() or ResetBytes() and
+// consists of BeginOption() and Param() calls finished by End():
+//
+//	ow := OptionWriter{Quote: QuoteStrict}
+//	ow.Reset(dest)
+//	ow.BeginOption([]byte("permessage-deflate"))
+//	ow.Param([]byte("client_max_window_bits"), nil)
+//	ow.BeginOption([]byte("x-webkit-deflate-frame"))
+//	n, err := ow.End()
+type OptionWriter struct {
+	// Quote describes how parameter values are written. Option names and
+	// parameter keys are always written as in QuoteMinimal mode.
+	Quote QuoteMode
+
+	// SortParameters causes writer to write parameters of each option sorted
+	// by key, such that output does not depend on order they were set in.
+	// Keys are compared bytewise; parameters with equal keys keep their
+	// order. Otherwise parameters are written in order they were set.
+	//
+	// Note that it is ignored when writing incrementally with Param().
+	SortParameters bool
+
+	// ExtValues causes writer to write parameters which values contain
+	// non-ASCII bytes as RFC8187 extended parameters. That is, "key" with
+	// value "€" is written as "key*=UTF-8''%E2%82%AC". Such values are
+	// expected to be UTF-8 encoded. Parameters which keys already end with
+	// "*" are written as is.
+	// See https://tools.ietf.org/html/rfc8187#section-3.2
+	ExtValues bool
+
+	w     writer
+	begun bool
+}
+
+// WriteOptions is the same as WriteOptions() function, but respects writer
+// configuration.
+func (o OptionWriter) WriteOptions(dest io.Writer, options []Option) (n int, err error) {
 	w := writer{w: dest}
+	o.writeOptions(&w, options)
+	return w.result()
+}
+
+// AppendOptions is the same as AppendOptions() function, but respects writer
+// configuration.
+func (o OptionWriter) AppendOptions(dst []byte, options []Option) []byte {
+	w := writer{buf: dst}
+	o.writeOptions(&w, options)
+	return w.buf
+}
+
+// Reset makes o to write options incrementally to the dest. It discards any
+// previous writing state, but keeps configuration.
+func (o *OptionWriter) Reset(dest io.Writer) {
+	o.w = writer{w: dest}
+	o.begun = false
+}
+
+// ResetBytes makes o to write options incrementally by appending them to dst.
+// Resulting slice is available via Bytes(). It discards any previous writing
+// state, but keeps configuration.
+func (o *OptionWriter) ResetBytes(dst []byte) {
+	o.w = writer{buf: dst}
+	o.begun = false
+}
+
+// BeginOption writes option name, preceded by comma if it is not the first
+// option written since the last reset.
+func (o *OptionWriter) BeginOption(name []byte) {
+	if o.begun {
+		o.w.write(comma)
+	}
+	o.begun = true
+	writeTokenSanitized(&o.w, name)
+}
+
+// Param writes parameter of the option started by the last BeginOption()
+// call. If value is nil, only key is written; see WriteOptions() for details.
+//
+// It panics if there were no BeginOption() calls since the last reset.
+func (o *OptionWriter) Param(key, value []byte) {
+	if !o.begun {
+		panic("httphead: Param() called before BeginOption()")
+	}
+	o.writeParam(&o.w, key, value)
+}
+
+// Bytes returns slice given to ResetBytes() extended by options written
+// since then.
+func (o *OptionWriter) Bytes() []byte {
+	return o.w.buf
+}
+
+// End finishes incremental writing. It returns number of bytes written since
+// the last reset and the first error occurred during writing, if any.
+func (o *OptionWriter) End() (n int, err error) {
+	o.begun = false
+	return o.w.result()
+}
+
+func (o OptionWriter) writeOptions(w *writer, options []Option) {
 	for i, opt := range options {
 		if i > 0 {
 			w.write(comma)
 		}
 
-		writeTokenSanitized(&w, opt.Name)
+		writeTokenSanitized(w, opt.Name)
 
-		for _, p := range opt.Parameters.data() {
-			w.write(semicolon)
-			writeTokenSanitized(&w, p.key)
-			if len(p.value) != 0 {
-				w.write(equality)
-				writeTokenSanitized(&w, p.value)
-			}
+		params := opt.Parameters.data()
+		if o.SortParameters {
+			params = sortPairs(params)
+		}
+		for _, p := range params {
+			o.writeParam(w, p.key, p.value)
 		}
 	}
-	return w.result()
+}
+
+func (o OptionWriter) writeParam(w *writer, key, value []byte) {
+	w.write(semicolon)
+	writeTokenSanitized(w, key)
+	if o.ExtValues && !isExtName(key) && indexNonASCII(value) != -1 {
+		w.write(asterisk)
+		w.write(equality)
+		writeExtValue(w, "UTF-8", "", value)
+		return
+	}
+	if value != nil {
+		w.write(equality)
+		o.writeValue(w, value)
+	}
+}
+
+func indexNonASCII(p []byte) int {
+	for i, c := range p {
+		if c >= utf8.RuneSelf {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortPairs returns copy of ps stably sorted by key. It does not copy ps if
+// it is already sorted.
+func sortPairs(ps []pair) []pair {
+	sorted := true
+	for i := 1; i < len(ps) && sorted; i++ {
+		sorted = bytes.Compare(ps[i-1].key, ps[i].key) <= 0
+	}
+	if sorted {
+		return ps
+	}
+	ret := make([]pair, len(ps))
+	copy(ret, ps)
+	// Insertion sort is stable and fast enough for usual number of
+	// parameters.
+	for i := 1; i < len(ret); i++ {
+		for j := i; j > 0 && bytes.Compare(ret[j-1].key, ret[j].key) > 0; j-- {
+			ret[j-1], ret[j] = ret[j], ret[j-1]
+		}
+	}
+	return ret
+}
+
+func (o OptionWriter) writeValue(w *writer, value []byte) {
+	if len(value) == 0 {
+		w.write(quote)
+		w.write(quote)
+		return
+	}
+	switch o.Quote {
+	case QuoteAlways:
+		writeQuoted(w, value)
+	case QuoteStrict:
+		if n, t := ScanToken(value); t == ItemToken && n == len(value) {
+			w.write(value)
+		} else {
+			writeQuotedStrict(w, value)
+		}
+	default:
+		writeTokenSanitized(w, value)
+	}
 }
 
 // writeTokenSanitized writes token as is or as quouted string if it contains
@@ -80,8 +303,79 @@ func writeTokenSanitized(bw *writer, bts []byte) {
 	}
 }
 
+// AppendSanitizedToken appends p to dst as token, or as quoted-string if p
+// contains non-token characters. It uses the same rules WriteOptions() uses
+// for option names and parameters.
+func AppendSanitizedToken(dst, p []byte) []byte {
+	w := writer{buf: dst}
+	writeTokenSanitized(&w, p)
+	return w.buf
+}
+
+// AppendQuoted appends p to dst as quoted-string, even if p is a valid token.
+// See EscapeQuotedString() for escaping rules.
+func AppendQuoted(dst, p []byte) []byte {
+	w := writer{buf: dst}
+	writeQuoted(&w, p)
+	return w.buf
+}
+
+// EscapeQuotedString appends p to dst escaping '"', '\\' and control
+// characters with backslash, such that result could be placed between quotes
+// of quoted-string. Surrounding quotes are not appended.
+// See UnescapeQuotedString() for the reverse operation.
+func EscapeQuotedString(dst, p []byte) []byte {
+	w := writer{buf: dst}
+	writeEscaped(&w, p)
+	return w.buf
+}
+
+// writeQuoted writes bts as quoted-string, escaping '"', '\\' and control
+// characters.
+func writeQuoted(bw *writer, bts []byte) {
+	bw.write(quote)
+	writeEscaped(bw, bts)
+	bw.write(quote)
+}
+
+// writeQuotedStrict writes bts as quoted-string, escaping '"' and '\\'.
+// Control characters other than HTAB are omitted, since they are not allowed
+// in quoted-string.
+func writeQuotedStrict(bw *writer, bts []byte) {
+	var pos int
+	bw.write(quote)
+	for i, c := range bts {
+		switch {
+		case c == '"' || c == '\\':
+			bw.write(bts[pos:i])
+			bw.write(escape)
+			pos = i
+		case (c < 0x20 || c == 0x7f) && c != '\t':
+			bw.write(bts[pos:i])
+			pos = i + 1
+		}
+	}
+	bw.write(bts[pos:])
+	bw.write(quote)
+}
+
+func writeEscaped(bw *writer, bts []byte) {
+	var pos int
+	for i, c := range bts {
+		if OctetTypes[c].IsControl() || c == '"' || c == '\\' {
+			bw.write(bts[pos:i])
+			bw.write(escape)
+			bw.write(bts[i : i+1])
+			pos = i + 1
+		}
+	}
+	bw.write(bts[pos:])
+}
+
+// writer writes to w or, if w is nil, appends to buf.
 type writer struct {
 	w   io.Writer
+	buf []byte
 	n   int
 	err error
 }
@@ -90,12 +384,31 @@ func (w *writer) write(p []byte) {
 	if w.err != nil {
 		return
 	}
+	if w.w == nil {
+		w.buf = append(w.buf, p...)
+		w.n += len(p)
+		return
+	}
 	var n int
 	n, w.err = w.w.Write(p)
 	w.n += n
 	return
 }
 
+func (w *writer) writeString(s string) {
+	if w.err != nil {
+		return
+	}
+	if w.w == nil {
+		w.buf = append(w.buf, s...)
+		w.n += len(s)
+		return
+	}
+	var n int
+	n, w.err = io.WriteString(w.w, s)
+	w.n += n
+}
+
 func (w *writer) result() (int, error) {
 	return w.n, w.err
 }
@@ -58,6 +58,12 @@ func TestWriteOptions(t *testing.T) {
 			},
 			exp: `"\"foo\"","\"bar\""`,
 		},
+		{
+			options: []Option{
+				NewOption("x", map[string]string{"k": ""}),
+			},
+			exp: "x;k",
+		},
 	} {
 		t.Run("", func(t *testing.T) {
 			buf := bytes.Buffer{}
@@ -71,6 +77,192 @@ func TestWriteOptions(t *testing.T) {
 			if act := buf.String(); act != test.exp {
 				t.Errorf("WriteOptions = %#q; want %#q", act, test.exp)
 			}
+			if act, exp := string(AppendOptions([]byte("x: "), test.options)), "x: "+test.exp; act != exp {
+				t.Errorf("AppendOptions = %#q; want %#q", act, exp)
+			}
+		})
+	}
+}
+
+func BenchmarkAppendOptions(b *testing.B) {
+	opts := []Option{
+		NewOption("foo", map[string]string{"bar": "baz"}),
+		NewOption("a", nil),
+		NewOption("b", map[string]string{"c": "hello, world"}),
+	}
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = AppendOptions(buf[:0], opts)
+	}
+}
+
+func TestAppendQuoting(t *testing.T) {
+	for _, test := range []struct {
+		in        string
+		sanitized string
+		quoted    string
+		escaped   string
+	}{
+		{"foo", `foo`, `"foo"`, `foo`},
+		{"hello, world", `"hello, world"`, `"hello, world"`, `hello, world`},
+		{`say "hi"`, `"say \"hi\""`, `"say \"hi\""`, `say \"hi\"`},
+		{`a\b`, `"a\b"`, `"a\\b"`, `a\\b`},
+		{"", ``, `""`, ``},
+	} {
+		if act := string(AppendSanitizedToken(nil, []byte(test.in))); act != test.sanitized {
+			t.Errorf("AppendSanitizedToken(%#q) = %#q; want %#q", test.in, act, test.sanitized)
+		}
+		if act := string(AppendQuoted(nil, []byte(test.in))); act != test.quoted {
+			t.Errorf("AppendQuoted(%#q) = %#q; want %#q", test.in, act, test.quoted)
+		}
+		if act := string(EscapeQuotedString(nil, []byte(test.in))); act != test.escaped {
+			t.Errorf("EscapeQuotedString(%#q) = %#q; want %#q", test.in, act, test.escaped)
+		}
+		if act := string(UnescapeQuotedString([]byte(test.escaped))); act != test.in {
+			t.Errorf("UnescapeQuotedString(%#q) = %#q; want %#q", test.escaped, act, test.in)
+		}
+	}
+}
+
+func TestOptionWriterQuote(t *testing.T) {
+	opts := []Option{
+		NewOption("foo", map[string]string{"a": "token"}),
+		NewOption("bar", map[string]string{"b": `say "hi", \o/`}),
+		NewOption("baz", map[string]string{"c": "x\r\ny\tz"}),
+	}
+	for _, test := range []struct {
+		mode QuoteMode
+		exp  string
+	}{
+		{QuoteMinimal, `foo;a=token,bar;b="say \"hi\", \o/",baz;c="x` + "\r\ny\tz" + `"`},
+		{QuoteAlways, `foo;a="token",bar;b="say \"hi\", \\o/",baz;c="x` + "\r\ny\tz" + `"`},
+		{QuoteStrict, `foo;a=token,bar;b="say \"hi\", \\o/",baz;c="xy` + "\t" + `z"`},
+	} {
+		t.Run(test.mode.String(), func(t *testing.T) {
+			w := OptionWriter{Quote: test.mode}
+			var buf bytes.Buffer
+			if _, err := w.WriteOptions(&buf, opts); err != nil {
+				t.Fatal(err)
+			}
+			if act := buf.String(); act != test.exp {
+				t.Errorf("WriteOptions() = %#q; want %#q", act, test.exp)
+			}
+			if act := string(w.AppendOptions(nil, opts)); act != test.exp {
+				t.Errorf("AppendOptions() = %#q; want %#q", act, test.exp)
+			}
 		})
 	}
 }
+
+func TestOptionWriterSortParameters(t *testing.T) {
+	var opt Option
+	opt.Name = []byte("foo")
+	for _, kv := range [][2]string{{"c", "3"}, {"a", "1"}, {"b", "2"}, {"a", "0"}} {
+		opt.Parameters.Set([]byte(kv[0]), []byte(kv[1]))
+	}
+	opts := []Option{opt, NewOption("bar", map[string]string{"z": "1", "y": "2", "x": "3"})}
+	if act, exp := string(AppendOptions(nil, opts)), "foo;c=3;a=1;b=2;a=0,bar;x=3;y=2;z=1"; act != exp {
+		t.Errorf("AppendOptions() = %#q; want %#q", act, exp)
+	}
+	w := OptionWriter{SortParameters: true}
+	if act, exp := string(w.AppendOptions(nil, opts)), "foo;a=1;a=0;b=2;c=3,bar;x=3;y=2;z=1"; act != exp {
+		t.Errorf("AppendOptions() = %#q; want %#q", act, exp)
+	}
+	if act, exp := string(opt.Parameters.data()[0].key), "c"; act != exp {
+		t.Errorf("AppendOptions() modified parameters order")
+	}
+}
+
+func TestOptionWriterStream(t *testing.T) {
+	const exp = `permessage-deflate;client_max_window_bits;server_max_window_bits=10,foo;bar="a b"`
+	write := func(ow *OptionWriter) (int, error) {
+		ow.BeginOption([]byte("permessage-deflate"))
+		ow.Param([]byte("client_max_window_bits"), nil)
+		ow.Param([]byte("server_max_window_bits"), []byte("10"))
+		ow.BeginOption([]byte("foo"))
+		ow.Param([]byte("bar"), []byte("a b"))
+		return ow.End()
+	}
+
+	var (
+		ow  OptionWriter
+		buf bytes.Buffer
+	)
+	ow.Reset(&buf)
+	n, err := write(&ow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := buf.String(); act != exp || n != len(exp) {
+		t.Errorf("OptionWriter wrote %#q (%d bytes); want %#q (%d bytes)", act, n, exp, len(exp))
+	}
+
+	ow.ResetBytes([]byte("x: "))
+	if _, err := write(&ow); err != nil {
+		t.Fatal(err)
+	}
+	if act := string(ow.Bytes()); act != "x: "+exp {
+		t.Errorf("OptionWriter appended %#q; want %#q", act, "x: "+exp)
+	}
+}
+
+func TestOptionWriterStreamPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Param() before BeginOption() did not panic")
+		}
+	}()
+	var ow OptionWriter
+	ow.ResetBytes(nil)
+	ow.Param([]byte("a"), []byte("b"))
+}
+
+func TestOptionWriterExtValues(t *testing.T) {
+	var opt Option
+	opt.Name = []byte("attachment")
+	opt.Parameters.Set([]byte("filename"), []byte("€ rates.txt"))
+	opt.Parameters.Set([]byte("title"), []byte("plain"))
+	opt.Parameters.Set([]byte("name*"), []byte("UTF-8''%C3%A4"))
+	opts := []Option{opt}
+
+	w := OptionWriter{ExtValues: true}
+	exp := `attachment;filename*=UTF-8''%E2%82%AC%20rates.txt;title=plain;name*=UTF-8''%C3%A4`
+	if act := string(w.AppendOptions(nil, opts)); act != exp {
+		t.Errorf("AppendOptions() = %#q; want %#q", act, exp)
+	}
+	parsed, ok := ParseOptions([]byte(exp), nil)
+	if !ok || len(parsed) != 1 {
+		t.Fatalf("ParseOptions(%#q) = %v, %v", exp, parsed, ok)
+	}
+	if v, _ := parsed[0].Parameters.GetExt("filename"); string(v) != "€ rates.txt" {
+		t.Errorf("GetExt(filename) = %q; want %q", v, "€ rates.txt")
+	}
+}
+
+func TestWriteOptionsEmptyValue(t *testing.T) {
+	for _, in := range []string{
+		`permessage-deflate;client_max_window_bits`,
+		`foo;a="";b`,
+		`foo;a="",bar;b=""`,
+	} {
+		opts, ok := ParseOptions([]byte(in), nil)
+		if !ok {
+			t.Fatalf("ParseOptions(%#q) = false", in)
+		}
+		if act := string(AppendOptions(nil, opts)); act != in {
+			t.Errorf("AppendOptions(ParseOptions(%#q)) = %#q", in, act)
+		}
+	}
+	var opt Option
+	opt.Name = []byte("foo")
+	opt.Parameters.Set([]byte("flag"), nil)
+	opt.Parameters.Set([]byte("empty"), []byte{})
+	if act, exp := string(AppendOptions(nil, []Option{opt})), `foo;flag;empty=""`; act != exp {
+		t.Errorf("AppendOptions() = %#q; want %#q", act, exp)
+	}
+	w := OptionWriter{Quote: QuoteStrict}
+	if act, exp := string(w.AppendOptions(nil, []Option{opt})), `foo;flag;empty=""`; act != exp {
+		t.Errorf("AppendOptions() = %#q; want %#q", act, exp)
+	}
+}